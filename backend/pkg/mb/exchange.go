@@ -0,0 +1,150 @@
+package mb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/rs/zerolog"
+)
+
+// Exchange is a fanout exchange, every subscriber receives every message.
+type Exchange struct {
+	ch            *amqp.Channel
+	ExchangeName  string
+	log           *zerolog.Logger
+	isSubscribing bool
+	sync.Mutex
+}
+
+type ExConfig struct {
+	Name string
+}
+
+func (cm *ConnManager) NewExchange(cfg ExConfig) (*Exchange, error) {
+
+	ch, err := cm.conn.Channel()
+	if err != nil {
+		return nil, err
+	}
+
+	err = ch.ExchangeDeclare(
+		cfg.Name, // name
+		"fanout", // type
+		true,     // durable
+		false,    // auto-deleted
+		false,    // internal
+		false,    // no-wait
+		nil,      // arguments
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exchange{
+		ch:            ch,
+		ExchangeName:  cfg.Name,
+		log:           cm.log,
+		isSubscribing: false,
+		Mutex:         sync.Mutex{},
+	}, nil
+}
+
+func (e *Exchange) Publish(ctx context.Context, body any) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		e.log.Err(err).Msg("error encoding exchange message to JSON")
+		return err
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	return e.ch.PublishWithContext(
+		ctx,
+		e.ExchangeName, // Exchange
+		"",             // Routing key, ignored by fanout
+		false,          // Mandatory
+		false,          // Immediate
+		amqp.Publishing{
+			ContentType: "application/json",
+			Body:        jsonData,
+		})
+}
+
+// Subscribe binds a queue private to this connection to the exchange.
+// The queue is deleted with the connection, messages published while
+// nobody is subscribed are lost. Messages are acknowledged on delivery.
+func (e *Exchange) Subscribe() (<-chan Message, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	if e.isSubscribing {
+		err := errors.New("subscriber has already been registered for this exchange")
+		e.log.Err(err).Msg("error register subscriber for exchange")
+		return nil, err
+	}
+
+	q, err := e.ch.QueueDeclare(
+		"",    // Name, generated by the server
+		false, // Durable
+		true,  // Delete when unused
+		true,  // Exclusive
+		false, // No-wait
+		nil,   // Arguments
+	)
+	if err != nil {
+		e.log.Err(err).Msg("error declaring exchange queue")
+		return nil, err
+	}
+
+	err = e.ch.QueueBind(
+		q.Name,         // queue name
+		"",             // routing key, ignored by fanout
+		e.ExchangeName, // exchange
+		false,
+		nil,
+	)
+	if err != nil {
+		e.log.Err(err).Msg("error binding exchange queue")
+		return nil, err
+	}
+
+	msgs, err := e.ch.Consume(
+		q.Name,
+		"",    // Consumer
+		true,  // Auto-Ack
+		true,  // Exclusive
+		false, // No-local
+		false, // No-Wait
+		nil,   // Args
+	)
+	if err != nil {
+		e.log.Err(err).Msg("error initializing read channel for exchange")
+		return nil, err
+	}
+	e.isSubscribing = true
+
+	out := make(chan Message)
+
+	go func() {
+		defer close(out)
+
+		for d := range msgs {
+			out <- Message{
+				Body:    d.Body,
+				Type:    "application/json",
+				Headers: d.Headers,
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (e *Exchange) Close() {
+	if err := e.ch.Close(); err != nil {
+		e.log.Err(err).Msg("failed to close exchange channel")
+	}
+}
@@ -22,6 +22,10 @@ func main() {
 	}
 
 	keyFlag := flag.Bool("keygen", false, "To generate secure key for JWT")
+	algFlag := flag.String("alg", "RS256", "Algorithm of the generated key: RS256, ES256 or EdDSA")
+	activateFlag := flag.Duration("activate-in", 0, "Delay before the generated key signs, it is published meanwhile")
+	retireFlag := flag.String("retire", "", "KID of the key to retire")
+	retireInFlag := flag.Duration("retire-in", 0, "Delay before the retired key stops verifying tokens")
 	tokenFlag := flag.Bool("tokengen", false, "To generate JWT token")
 	kidFlag := flag.String("kid", "", "KID for generate JWT token")
 	roleFlag := flag.String("role", "", "Role for generate JWT token")
@@ -31,7 +35,11 @@ func main() {
 	flag.Parse()
 
 	if *keyFlag {
-		utils.GenerateKey(cfg)
+		utils.GenerateKey(cfg, *algFlag, *activateFlag)
+	}
+
+	if *retireFlag != "" {
+		utils.RetireKey(cfg, *retireFlag, *retireInFlag)
 	}
 
 	if *tokenFlag {
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS list_members;
+
+DROP TYPE IF EXISTS list_role;
+
+COMMIT;
\ No newline at end of file
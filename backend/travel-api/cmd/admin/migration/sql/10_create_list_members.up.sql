@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TYPE list_role AS ENUM ('viewer', 'editor');
+
+CREATE TABLE list_members (
+  list_id UUID NOT NULL,
+  user_id UUID NOT NULL,
+  role list_role NOT NULL,
+  invited_by UUID NOT NULL,
+  date_created TIMESTAMP NOT NULL,
+  date_updated TIMESTAMP NOT NULL,
+  PRIMARY KEY (list_id, user_id),
+  FOREIGN KEY (list_id) REFERENCES lists(list_id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+  FOREIGN KEY (invited_by) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX list_members_user_id_idx ON list_members (user_id);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS list_shares;
+
+COMMIT;
\ No newline at end of file
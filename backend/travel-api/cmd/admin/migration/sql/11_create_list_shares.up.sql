@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE list_shares (
+  share_id UUID PRIMARY KEY,
+  list_id UUID NOT NULL,
+  token TEXT UNIQUE NOT NULL,
+  created_by UUID NOT NULL,
+  date_created TIMESTAMP NOT NULL,
+  FOREIGN KEY (list_id) REFERENCES lists(list_id) ON DELETE CASCADE,
+  FOREIGN KEY (created_by) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX list_shares_list_id_idx ON list_shares (list_id);
+
+COMMIT;
\ No newline at end of file
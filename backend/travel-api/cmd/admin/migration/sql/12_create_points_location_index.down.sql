@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS points_location_geography_idx;
+
+DROP INDEX IF EXISTS points_location_idx;
+
+COMMIT;
\ No newline at end of file
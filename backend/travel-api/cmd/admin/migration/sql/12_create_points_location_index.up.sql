@@ -0,0 +1,8 @@
+BEGIN;
+
+CREATE INDEX points_location_idx ON points USING GIST (location);
+
+-- distances in meters are computed on geography
+CREATE INDEX points_location_geography_idx ON points USING GIST ((location::geography));
+
+COMMIT;
\ No newline at end of file
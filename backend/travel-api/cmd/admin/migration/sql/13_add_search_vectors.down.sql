@@ -0,0 +1,9 @@
+BEGIN;
+
+DROP INDEX IF EXISTS items_search_idx;
+DROP INDEX IF EXISTS lists_search_idx;
+
+ALTER TABLE items DROP COLUMN IF EXISTS search;
+ALTER TABLE lists DROP COLUMN IF EXISTS search;
+
+COMMIT;
\ No newline at end of file
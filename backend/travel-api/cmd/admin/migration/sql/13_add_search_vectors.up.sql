@@ -0,0 +1,18 @@
+BEGIN;
+
+-- the simple configuration does not stem, names and addresses are in many languages
+ALTER TABLE lists ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+  setweight(to_tsvector('simple', list_name), 'A') ||
+  setweight(to_tsvector('simple', COALESCE(description, '')), 'B')
+) STORED;
+
+ALTER TABLE items ADD COLUMN search TSVECTOR GENERATED ALWAYS AS (
+  setweight(to_tsvector('simple', item_name), 'A') ||
+  setweight(to_tsvector('simple', COALESCE(description, '')), 'B') ||
+  setweight(to_tsvector('simple', COALESCE(address, '')), 'C')
+) STORED;
+
+CREATE INDEX lists_search_idx ON lists USING GIN (search);
+CREATE INDEX items_search_idx ON items USING GIN (search);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE items DROP COLUMN IF EXISTS version;
+ALTER TABLE lists DROP COLUMN IF EXISTS version;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- bumped on every update, exposed as ETag for optimistic concurrency
+ALTER TABLE lists ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE items ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+COMMIT;
\ No newline at end of file
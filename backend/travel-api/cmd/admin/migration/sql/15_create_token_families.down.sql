@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS used_refresh_tokens;
+DROP TABLE IF EXISTS token_families;
+
+COMMIT;
\ No newline at end of file
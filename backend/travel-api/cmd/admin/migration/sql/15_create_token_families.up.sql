@@ -0,0 +1,26 @@
+BEGIN;
+
+-- a login starts a family, every refresh rotates its current token
+CREATE TABLE token_families (
+  family_id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  current_token_id UUID NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  date_created TIMESTAMP NOT NULL,
+  date_updated TIMESTAMP NOT NULL,
+  revoked_at TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX token_families_user_id_idx ON token_families (user_id);
+
+-- presenting a used token again revokes its family
+CREATE TABLE used_refresh_tokens (
+  token_id UUID PRIMARY KEY,
+  family_id UUID NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  used_at TIMESTAMP NOT NULL,
+  FOREIGN KEY (family_id) REFERENCES token_families(family_id) ON DELETE CASCADE
+);
+
+COMMIT;
\ No newline at end of file
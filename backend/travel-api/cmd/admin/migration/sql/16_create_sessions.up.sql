@@ -0,0 +1,19 @@
+BEGIN;
+
+-- a session is the refresh token family started by a login,
+-- it is terminated by revoking the family
+CREATE TABLE sessions (
+  session_id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  device_name TEXT NOT NULL,
+  user_agent TEXT NOT NULL,
+  ip TEXT NOT NULL,
+  date_created TIMESTAMP NOT NULL,
+  last_seen TIMESTAMP NOT NULL,
+  FOREIGN KEY (session_id) REFERENCES token_families(family_id) ON DELETE CASCADE,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX sessions_user_id_idx ON sessions (user_id);
+
+COMMIT;
\ No newline at end of file
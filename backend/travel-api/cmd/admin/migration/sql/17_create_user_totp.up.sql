@@ -0,0 +1,25 @@
+BEGIN;
+
+-- enabled once the first code is verified, last_step refuses replayed codes
+CREATE TABLE user_totp (
+  user_id UUID PRIMARY KEY,
+  secret TEXT NOT NULL,
+  enabled BOOLEAN NOT NULL DEFAULT false,
+  last_step BIGINT NOT NULL DEFAULT 0,
+  date_created TIMESTAMP NOT NULL,
+  date_updated TIMESTAMP NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+-- sha256 of the codes, each can be used once
+CREATE TABLE totp_recovery_codes (
+  code_hash TEXT PRIMARY KEY,
+  user_id UUID NOT NULL,
+  date_created TIMESTAMP NOT NULL,
+  date_used TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX totp_recovery_codes_user_id_idx ON totp_recovery_codes (user_id);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,19 @@
+BEGIN;
+
+-- opaque tokens for scripts and integrations, only the sha256 of a token
+-- is stored, scopes limit the routes it is accepted on
+CREATE TABLE personal_access_tokens (
+  token_id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  name TEXT NOT NULL,
+  token_hash TEXT NOT NULL UNIQUE,
+  scopes TEXT[] NOT NULL,
+  expires_at TIMESTAMP,
+  last_used_at TIMESTAMP,
+  date_created TIMESTAMP NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX personal_access_tokens_user_id_idx ON personal_access_tokens (user_id);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS roles;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,36 @@
+BEGIN;
+
+-- roles a user may hold in users.roles, ADMIN is granted every
+-- permission in code and has no rows in role_permissions
+CREATE TABLE roles (
+  role TEXT PRIMARY KEY,
+  description TEXT NOT NULL,
+  date_created TIMESTAMP NOT NULL,
+  date_updated TIMESTAMP NOT NULL
+);
+
+CREATE TABLE role_permissions (
+  role TEXT NOT NULL,
+  permission TEXT NOT NULL,
+  PRIMARY KEY (role, permission),
+  FOREIGN KEY (role) REFERENCES roles(role) ON DELETE CASCADE
+);
+
+INSERT INTO roles (role, description, date_created, date_updated) VALUES
+  ('ADMIN', 'every permission', NOW(), NOW()),
+  ('USER', 'own data only', NOW(), NOW()),
+  ('MODERATOR', 'reviews and removes abusive content', NOW(), NOW()),
+  ('SUPPORT', 'looks into user problems', NOW(), NOW());
+
+INSERT INTO role_permissions (role, permission) VALUES
+  ('MODERATOR', 'list.read.any'),
+  ('MODERATOR', 'list.write.any'),
+  ('MODERATOR', 'list.manage.any'),
+  ('MODERATOR', 'image.read.any'),
+  ('MODERATOR', 'user.read.any'),
+  ('MODERATOR', 'user.ban'),
+  ('SUPPORT', 'list.read.any'),
+  ('SUPPORT', 'image.read.any'),
+  ('SUPPORT', 'user.read.any');
+
+COMMIT;
\ No newline at end of file
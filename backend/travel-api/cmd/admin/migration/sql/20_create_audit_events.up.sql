@@ -0,0 +1,34 @@
+BEGIN;
+
+-- append-only record of who did what, when and from where. There is no
+-- foreign key on the actor, the events outlive the users they mention.
+CREATE TABLE audit_events (
+  event_id UUID PRIMARY KEY,
+  actor_id UUID,
+  action TEXT NOT NULL,
+  target_type TEXT NOT NULL,
+  target_id TEXT NOT NULL,
+  trace_id TEXT NOT NULL,
+  ip TEXT NOT NULL,
+  before JSONB,
+  after JSONB,
+  date_created TIMESTAMP NOT NULL
+);
+
+CREATE INDEX audit_events_date_created_idx ON audit_events (date_created);
+CREATE INDEX audit_events_actor_id_idx ON audit_events (actor_id, date_created);
+CREATE INDEX audit_events_target_idx ON audit_events (target_type, target_id, date_created);
+CREATE INDEX audit_events_action_idx ON audit_events (action, date_created);
+
+-- events are never changed, only deleted by the retention job
+CREATE FUNCTION audit_events_reject_update() RETURNS trigger AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_reject_update
+BEFORE UPDATE ON audit_events
+FOR EACH ROW EXECUTE FUNCTION audit_events_reject_update();
+
+COMMIT;
\ No newline at end of file
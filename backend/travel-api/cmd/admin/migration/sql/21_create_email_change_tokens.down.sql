@@ -0,0 +1,5 @@
+BEGIN;
+
+DROP TABLE IF EXISTS email_change_tokens;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,13 @@
+BEGIN;
+
+-- pending email changes, email is the new address awaiting confirmation
+CREATE TABLE email_change_tokens (
+  token_id TEXT UNIQUE PRIMARY KEY,
+  user_id UUID NOT NULL,
+  email TEXT NOT NULL,
+  issued_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,12 @@
+BEGIN;
+
+-- single-use login links, token_id is the SHA-256 of the token sent by email
+CREATE TABLE magic_link_tokens (
+  token_id TEXT UNIQUE PRIMARY KEY,
+  user_id UUID NOT NULL,
+  issued_at TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP NOT NULL,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+COMMIT;
\ No newline at end of file
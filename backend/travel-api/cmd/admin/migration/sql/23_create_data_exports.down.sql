@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS data_exports;
+DROP TYPE IF EXISTS export_status;
+
+COMMIT;
\ No newline at end of file
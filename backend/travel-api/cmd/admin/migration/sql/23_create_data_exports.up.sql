@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TYPE export_status AS ENUM ('pending', 'ready', 'failed');
+
+-- archives of the user data, token_id is the SHA-256 of the download token
+-- sent by email once the archive is ready
+CREATE TABLE data_exports (
+  export_id UUID PRIMARY KEY,
+  user_id UUID NOT NULL,
+  status export_status NOT NULL,
+  token_id TEXT UNIQUE,
+  date_created TIMESTAMP NOT NULL,
+  expires_at TIMESTAMP,
+  FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX data_exports_user_id_idx ON data_exports (user_id);
+
+COMMIT;
\ No newline at end of file
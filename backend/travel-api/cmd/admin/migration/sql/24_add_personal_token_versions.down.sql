@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE personal_access_tokens DROP COLUMN IF EXISTS token_version;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,10 @@
+BEGIN;
+
+-- the token version of the user when the token was created, the token
+-- stops working once the user is logged out everywhere
+ALTER TABLE personal_access_tokens ADD COLUMN token_version INTEGER NOT NULL DEFAULT 0;
+
+UPDATE personal_access_tokens SET token_version = users.token_version
+FROM users WHERE users.user_id = personal_access_tokens.user_id;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,6 @@
+BEGIN;
+
+-- the tokens cannot be recovered from their hashes, the links stay broken
+ALTER TABLE list_shares RENAME COLUMN token_hash TO token;
+
+COMMIT;
\ No newline at end of file
@@ -0,0 +1,8 @@
+BEGIN;
+
+-- share tokens are bearer secrets, only their SHA-256 is kept
+ALTER TABLE list_shares RENAME COLUMN token TO token_hash;
+
+UPDATE list_shares SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex');
+
+COMMIT;
\ No newline at end of file
@@ -1,26 +1,59 @@
 package utils
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/json"
 	"encoding/pem"
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/google/uuid"
 
 	"github.com/f4mk/travel/backend/travel-api/config"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/keystore"
 )
 
-func GenerateKey(cfg *config.Config) {
-	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+// GenerateKey writes a new key pair of the algorithm, RS256, ES256 or EdDSA,
+// with its metadata. The key signs once activateIn has passed, until then it
+// is only published, so that verifiers pick it up before the first token
+// signed with it.
+func GenerateKey(cfg *config.Config, alg string, activateIn time.Duration) {
+	var privateKey crypto.Signer
+	var err error
+	switch alg {
+	case keystore.AlgorithmRS256:
+		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
+	case keystore.AlgorithmES256:
+		privateKey, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case keystore.AlgorithmEdDSA:
+		_, privateKey, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		panic(fmt.Errorf("unsupported algorithm %q, want RS256, ES256 or EdDSA", alg))
+	}
 	if err != nil {
 		panic(fmt.Errorf("cannot generate private key: %w", err))
 	}
+	privateBytes, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		panic(fmt.Errorf("marshaling private key: %w", err))
+	}
 
 	filename := uuid.New().String()
+	// the metadata goes first, the keystore could load the key in between
+	now := time.Now().UTC()
+	writeMetadata(cfg, filename, keystore.Metadata{
+		Created:   now,
+		NotBefore: now.Add(activateIn),
+	})
+
 	privateFile, err := os.Create(filepath.Join(cfg.Auth.KeyPath, filename+".pem"))
 	if err != nil {
 		panic(fmt.Errorf("creating private file: %w", err))
@@ -30,7 +63,7 @@ func GenerateKey(cfg *config.Config) {
 	// Construct a PEM block for the private key.
 	privateBlock := pem.Block{
 		Type:  "PRIVATE KEY",
-		Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		Bytes: privateBytes,
 	}
 
 	// Write the private key to the private key file.
@@ -46,7 +79,7 @@ func GenerateKey(cfg *config.Config) {
 	defer publicFile.Close()
 
 	// Marshal the public key from the private key to PKIX.
-	asn1Bytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	asn1Bytes, err := x509.MarshalPKIXPublicKey(privateKey.Public())
 	if err != nil {
 		panic(fmt.Errorf("marshaling public key: %w", err))
 	}
@@ -62,5 +95,49 @@ func GenerateKey(cfg *config.Config) {
 		panic(fmt.Errorf("encoding to public file: %w", err))
 	}
 
-	fmt.Println("private and public key files generated")
+	fmt.Printf("%s private and public key files generated\n", alg)
+	fmt.Println(filename)
+}
+
+// RetireKey sets the time after which the key neither signs nor verifies.
+// It should be later than the time the next key signs plus the longest token
+// lifetime, or tokens signed with the key are rejected before they expire.
+func RetireKey(cfg *config.Config, kid string, retireIn time.Duration) {
+	md := keystore.Metadata{}
+	data, err := os.ReadFile(filepath.Join(cfg.Auth.KeyPath, kid+".json"))
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(data, &md); err != nil {
+			panic(fmt.Errorf("parsing metadata file: %w", err))
+		}
+	case os.IsNotExist(err):
+		info, err := os.Stat(filepath.Join(cfg.Auth.KeyPath, kid+".pem"))
+		if err != nil {
+			panic(fmt.Errorf("reading private file: %w", err))
+		}
+		md.Created = info.ModTime().UTC()
+		md.NotBefore = md.Created
+	default:
+		panic(fmt.Errorf("reading metadata file: %w", err))
+	}
+	retireAfter := time.Now().UTC().Add(retireIn)
+	md.RetireAfter = &retireAfter
+	writeMetadata(cfg, kid, md)
+
+	fmt.Printf("key %s retires after %s\n", kid, retireAfter.Format(time.RFC3339))
+}
+
+func writeMetadata(cfg *config.Config, kid string, md keystore.Metadata) {
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		panic(fmt.Errorf("encoding metadata: %w", err))
+	}
+	// the keystore reloads on change, write the whole file at once
+	tmp := filepath.Join(cfg.Auth.KeyPath, "."+kid+".json.tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		panic(fmt.Errorf("writing metadata file: %w", err))
+	}
+	if err := os.Rename(tmp, filepath.Join(cfg.Auth.KeyPath, kid+".json")); err != nil {
+		panic(fmt.Errorf("writing metadata file: %w", err))
+	}
 }
@@ -1,7 +1,6 @@
 package utils
 
 import (
-	"crypto/rsa"
 	"fmt"
 	"io"
 	"io/fs"
@@ -12,13 +11,14 @@ import (
 	"time"
 
 	"github.com/f4mk/travel/backend/travel-api/config"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/keystore"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 func GenerateAllTokens(cfg *config.Config, roles []string) ([]map[string]string, error) {
 
 	tokensSlice := make([]map[string]string, len(roles))
-	keys := make(map[string]*rsa.PrivateKey)
+	keys := make(map[string]keystore.Key)
 	fsys := os.DirFS(cfg.Auth.KeyPath)
 
 	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
@@ -48,12 +48,15 @@ func GenerateAllTokens(cfg *config.Config, roles []string) ([]map[string]string,
 			return fmt.Errorf("reading auth private key: %w", err)
 		}
 
-		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		privateKey, alg, err := keystore.ParsePrivateKeyPEM(privatePEM)
 		if err != nil {
 			return fmt.Errorf("parsing auth private key: %w", err)
 		}
 
-		keys[strings.TrimSuffix(dirEntry.Name(), ".pem")] = privateKey
+		keys[strings.TrimSuffix(dirEntry.Name(), ".pem")] = keystore.Key{
+			Private:   privateKey,
+			Algorithm: alg,
+		}
 
 		return nil
 	}
@@ -95,10 +98,10 @@ func GenerateAllTokens(cfg *config.Config, roles []string) ([]map[string]string,
 		}
 
 		for key, privateKey := range keys {
-			method := jwt.GetSigningMethod("RS256")
+			method := jwt.GetSigningMethod(privateKey.Algorithm)
 			token := jwt.NewWithClaims(method, claims)
 			token.Header["kid"] = key
-			str, err := token.SignedString(privateKey)
+			str, err := token.SignedString(privateKey.Private)
 			if err != nil {
 				return nil, fmt.Errorf("cannot sign token: %w", err)
 			}
@@ -128,7 +131,7 @@ func GenerateToken(cfg *config.Config, kid string, roles []string) (map[string]s
 		return nil, fmt.Errorf("reading auth private key: %w", err)
 	}
 
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	privateKey, alg, err := keystore.ParsePrivateKeyPEM(privatePEM)
 	if err != nil {
 		return nil, fmt.Errorf("parsing auth private key: %w", err)
 	}
@@ -163,7 +166,7 @@ func GenerateToken(cfg *config.Config, kid string, roles []string) (map[string]s
 			TokenVersion: 0,
 		}
 
-		method := jwt.GetSigningMethod("RS256")
+		method := jwt.GetSigningMethod(alg)
 		token := jwt.NewWithClaims(method, claims)
 		token.Header["kid"] = kid
 		str, err := token.SignedString(privateKey)
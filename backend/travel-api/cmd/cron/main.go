@@ -23,6 +23,8 @@ const batchSize = 100
 
 var db *sqlx.DB
 
+var auditRetention time.Duration
+
 func main() {
 
 	cfg, err := config.New(configPath)
@@ -43,6 +45,7 @@ func main() {
 		log.Fatalln(err)
 	}
 	defer db.Close()
+	auditRetention = cfg.Audit.Retention
 
 	c := cron.New()
 
@@ -70,6 +73,30 @@ func main() {
 		return
 	}
 
+	_, err = c.AddFunc("0 4 * * *", removeExpiredAuditEvents)
+	if err != nil {
+		fmt.Println("error scheduling removeExpiredAuditEvents task:", err)
+		return
+	}
+
+	_, err = c.AddFunc("0 5 * * *", removeExpiredEmailChangeTokens)
+	if err != nil {
+		fmt.Println("error scheduling removeExpiredEmailChangeTokens task:", err)
+		return
+	}
+
+	_, err = c.AddFunc("0 6 * * *", removeExpiredMagicLinkTokens)
+	if err != nil {
+		fmt.Println("error scheduling removeExpiredMagicLinkTokens task:", err)
+		return
+	}
+
+	_, err = c.AddFunc("0 7 * * *", removeExpiredDataExports)
+	if err != nil {
+		fmt.Println("error scheduling removeExpiredDataExports task:", err)
+		return
+	}
+
 	c.Start()
 	fmt.Println("cron has starter")
 	select {}
@@ -162,6 +189,64 @@ func removeExpiredVerificationTokens() {
 	}
 }
 
+func removeExpiredEmailChangeTokens() {
+	for {
+		q := `
+		DELETE FROM email_change_tokens
+		WHERE token_id IN 
+				(SELECT token_id FROM email_change_tokens
+				WHERE expires_at < $1
+				LIMIT $2);`
+		result, err := db.Exec(q, time.Now(), batchSize)
+		if err != nil {
+			fmt.Println("error removing email_change_tokens records:", err)
+			return
+		}
+		removed, err := result.RowsAffected()
+		if err != nil {
+			fmt.Println("error getting email_change_tokens rows affected:", err)
+			return
+		}
+
+		fmt.Println("cron removed entries from email_change_tokens:", removed)
+
+		if removed < batchSize {
+			break
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+func removeExpiredMagicLinkTokens() {
+	for {
+		q := `
+		DELETE FROM magic_link_tokens
+		WHERE token_id IN 
+				(SELECT token_id FROM magic_link_tokens
+				WHERE expires_at < $1
+				LIMIT $2);`
+		result, err := db.Exec(q, time.Now(), batchSize)
+		if err != nil {
+			fmt.Println("error removing magic_link_tokens records:", err)
+			return
+		}
+		removed, err := result.RowsAffected()
+		if err != nil {
+			fmt.Println("error getting magic_link_tokens rows affected:", err)
+			return
+		}
+
+		fmt.Println("cron removed entries from magic_link_tokens:", removed)
+
+		if removed < batchSize {
+			break
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
 func removeUncommitedImages() {
 	for {
 		dayBefore := time.Now().Add(-24 * time.Hour)
@@ -191,3 +276,66 @@ func removeUncommitedImages() {
 		time.Sleep(2 * time.Second)
 	}
 }
+
+func removeExpiredAuditEvents() {
+	for {
+		expired := time.Now().UTC().Add(-auditRetention)
+		q := `
+		DELETE FROM audit_events
+		WHERE event_id IN 
+				(SELECT event_id FROM audit_events
+				WHERE date_created < $1
+				LIMIT $2);`
+		result, err := db.Exec(q, expired, batchSize)
+		if err != nil {
+			fmt.Println("error removing audit_events records:", err)
+			return
+		}
+		removed, err := result.RowsAffected()
+		if err != nil {
+			fmt.Println("error getting audit_events rows affected:", err)
+			return
+		}
+
+		fmt.Println("cron removed entries from audit_events:", removed)
+
+		if removed < batchSize {
+			break
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
+
+// removeExpiredDataExports removes the exports with expired download links
+// and the ones never built, the archives expire in the bucket by themselves.
+func removeExpiredDataExports() {
+	for {
+		now := time.Now().UTC()
+		q := `
+		DELETE FROM data_exports
+		WHERE export_id IN 
+				(SELECT export_id FROM data_exports
+				WHERE expires_at < $1
+				OR (status <> 'ready' AND date_created < $2)
+				LIMIT $3);`
+		result, err := db.Exec(q, now, now.Add(-24*time.Hour), batchSize)
+		if err != nil {
+			fmt.Println("error removing data_exports records:", err)
+			return
+		}
+		removed, err := result.RowsAffected()
+		if err != nil {
+			fmt.Println("error getting data_exports rows affected:", err)
+			return
+		}
+
+		fmt.Println("cron removed entries from data_exports:", removed)
+
+		if removed < batchSize {
+			break
+		}
+
+		time.Sleep(2 * time.Second)
+	}
+}
@@ -19,6 +19,7 @@ type API struct {
 	RequestTimeout  time.Duration `env:"API_REQUEST_TIMEOUT" envDefault:"5s"`
 	KeyFile         string        `env:"API_KEY_FILE,required"`
 	RateLimit       int           `env:"API_RATE_LIMIT,required"`
+	TrustedProxies  []string      `env:"API_TRUSTED_PROXIES" envSeparator:","`
 }
 
 type Service struct {
@@ -37,9 +38,15 @@ type Metrics struct {
 }
 
 type Auth struct {
-	KeyPath         string        `env:"AUTH_KEY_PATH,required"`
-	AuthDuration    time.Duration `env:"AUTH_AUTH_DURATION,required"`
-	RefreshDuration time.Duration `env:"AUTH_REFRESH_DURATION,required"`
+	KeyPath              string        `env:"AUTH_KEY_PATH,required"`
+	KeyReloadInterval    time.Duration `env:"AUTH_KEY_RELOAD_INTERVAL" envDefault:"1m"`
+	PolicyReloadInterval time.Duration `env:"AUTH_POLICY_RELOAD_INTERVAL" envDefault:"1m"`
+	AuthDuration         time.Duration `env:"AUTH_AUTH_DURATION,required"`
+	RefreshDuration      time.Duration `env:"AUTH_REFRESH_DURATION,required"`
+	LoginMaxAttempts     int           `env:"AUTH_LOGIN_MAX_ATTEMPTS" envDefault:"10"`
+	LoginMaxIPAttempts   int           `env:"AUTH_LOGIN_MAX_IP_ATTEMPTS" envDefault:"100"`
+	LoginDelay           time.Duration `env:"AUTH_LOGIN_DELAY" envDefault:"1s"`
+	LoginLockDuration    time.Duration `env:"AUTH_LOGIN_LOCK_DURATION" envDefault:"15m"`
 }
 
 type Cache struct {
@@ -78,6 +85,36 @@ type ImageConverter struct {
 	MaxWriteConns int           `env:"IMAGINARY_MAX_WR_CONNS,required"`
 }
 
+// Password holds the argon2id params of new password hashes, Memory is in KiB.
+// Changing them upgrades the hashes of the users as they log in.
+type Password struct {
+	Memory      uint32 `env:"PASSWORD_ARGON2_MEMORY" envDefault:"19456"`
+	Iterations  uint32 `env:"PASSWORD_ARGON2_ITERATIONS" envDefault:"2"`
+	Parallelism uint8  `env:"PASSWORD_ARGON2_PARALLELISM" envDefault:"1"`
+	SaltLength  uint32 `env:"PASSWORD_ARGON2_SALT_LENGTH" envDefault:"16"`
+	KeyLength   uint32 `env:"PASSWORD_ARGON2_KEY_LENGTH" envDefault:"32"`
+}
+
+// PasswordPolicy.MinScore is the lowest accepted strength score from 0 to 4.
+// BreachedPath is a file of SHA-1 hashes of breached passwords, no breach
+// check is made if it is empty.
+type PasswordPolicy struct {
+	MinScore     int    `env:"PASSWORD_MIN_SCORE" envDefault:"3"`
+	BreachedPath string `env:"PASSWORD_BREACHED_PATH"`
+}
+
+// Audit.Retention is how long audit events are kept before the cron removes them.
+type Audit struct {
+	Retention time.Duration `env:"AUDIT_RETENTION" envDefault:"8760h"`
+}
+
+// Export.LinkTTL is how long the download link of a data export works,
+// the archives are removed from the bucket after that.
+type Export struct {
+	BucketName string        `env:"MINIO_EXPORT_BUCKET_NAME" envDefault:"exports"`
+	LinkTTL    time.Duration `env:"EXPORT_LINK_TTL" envDefault:"72h"`
+}
+
 type Log struct {
 	LogLevel int `env:"LOG_LEVEL,required"`
 }
@@ -114,6 +151,10 @@ type Config struct {
 	Telemetry      Telemetry
 	ImageServer    ImageServer
 	ImageConverter ImageConverter
+	Audit          Audit
+	Export         Export
+	Password       Password
+	PasswordPolicy PasswordPolicy
 }
 
 func New(configPath string) (*Config, error) {
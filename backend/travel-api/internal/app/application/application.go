@@ -13,27 +13,37 @@ import (
 	"github.com/f4mk/travel/backend/travel-api/config"
 	"github.com/f4mk/travel/backend/travel-api/internal/app/controller/api"
 	"github.com/f4mk/travel/backend/travel-api/internal/app/controller/debug"
+	"github.com/f4mk/travel/backend/travel-api/internal/app/controller/export"
 	"github.com/f4mk/travel/backend/travel-api/internal/app/controller/mail"
+	auditProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/audit"
 	authProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/auth"
+	exportProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/export"
 	imageProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/image"
 	listProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/list"
 	mailProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/mail"
 	userProvider "github.com/f4mk/travel/backend/travel-api/internal/app/provider/user"
+	auditService "github.com/f4mk/travel/backend/travel-api/internal/app/service/audit"
 	authService "github.com/f4mk/travel/backend/travel-api/internal/app/service/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/app/service/check"
+	exportService "github.com/f4mk/travel/backend/travel-api/internal/app/service/export"
 	imageService "github.com/f4mk/travel/backend/travel-api/internal/app/service/image"
 	listService "github.com/f4mk/travel/backend/travel-api/internal/app/service/list"
 	mailService "github.com/f4mk/travel/backend/travel-api/internal/app/service/mail"
 	userService "github.com/f4mk/travel/backend/travel-api/internal/app/service/user"
+	auditUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/audit"
 	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	exportUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/export"
 	imageUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/image"
 	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
 	mailUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/mail"
 	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/keystore"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/lockout"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/middleware"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/password"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/tracer"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 	"github.com/mailjet/mailjet-apiv3-go/v3"
@@ -97,6 +107,25 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 		return ErrCreateQueue
 	}
 	defer mq.Close()
+
+	exportQ, err := cm.NewChannel(mb.ChConfig{
+		QName:   "exportDataQ",
+		WithDLQ: true,
+	})
+	if err != nil {
+		log.Err(err).Msg(ErrCreateQueue.Error())
+		return ErrCreateQueue
+	}
+	defer exportQ.Close()
+
+	ex, err := cm.NewExchange(mb.ExConfig{
+		Name: "listEventsX",
+	})
+	if err != nil {
+		log.Err(err).Msg(ErrCreateExchange.Error())
+		return ErrCreateExchange
+	}
+	defer ex.Close()
 	// -------------------------------------------------------------------------
 	// Starting Mail service
 	mailClient := mailjet.NewMailjetClient(cfg.MailService.PublicKey, cfg.MailService.PrivateKey)
@@ -125,7 +154,9 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 		return ErrCreateKeyStore
 	}
 
-	activeKids := ks.CollectKeyIDs()
+	// picks up new and retired keys without a restart
+	go ks.Watch(log, cfg.Auth.KeyReloadInterval)
+	defer ks.Close()
 
 	// creating cache
 	redisHost := utils.GetHost(cfg.Cache.HostName, cfg.Cache.Port)
@@ -151,12 +182,22 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 		redis.Close()
 	}()
 
+	// role permissions, changes made on other instances are picked up periodically
+	policy, err := auth.NewPolicy(db, log)
+	if err != nil {
+		log.Err(err).Msg(ErrCreatePolicy.Error())
+		return ErrCreatePolicy
+	}
+	go policy.Watch(cfg.Auth.PolicyReloadInterval)
+	defer policy.Close()
+
 	authCfg := auth.Config{
-		ActiveKIDs:      activeKids,
 		KeyLookup:       ks,
+		Policy:          policy,
 		Cache:           redis,
 		DB:              db,
 		Log:             log,
+		Issuer:          cfg.Service.ServiceName,
 		AuthDuration:    cfg.Auth.AuthDuration,
 		RefreshDuration: cfg.Auth.RefreshDuration,
 	}
@@ -226,6 +267,12 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 		middleware.Metrics(),
 		middleware.Panics(log),
 	)
+	proxies, err := web.ParseTrustedProxies(cfg.API.TrustedProxies)
+	if err != nil {
+		log.Err(err).Msg(ErrTrustedProxies.Error())
+		return ErrTrustedProxies
+	}
+	app.TrustProxies(proxies)
 
 	imgCfg := imageProvider.ServerConfig{
 		Log:        log,
@@ -254,19 +301,87 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 	userStorer := userProvider.NewStorer(log, db)
 	authStorer := authProvider.NewStorer(log, db)
 	listStorer := listProvider.NewStorer(log, db)
+	auditStorer := auditProvider.NewStorer(log, db)
+	auditLog := audit.New(db, log)
 
-	imageCore := imageUsecase.NewCore(log, imageServer, imageStorer, imageCoverter)
+	imageCore := imageUsecase.NewCore(log, imageServer, imageStorer, imageCoverter, auditLog)
 	// TODO: proper semaphore limit
 	imageService := imageService.NewService(log, auth, imageCore, 128)
 
-	userCore := userUsecase.NewCore(log, userStorer)
+	passwordHasher := password.New(password.Argon2idParams{
+		Memory:      cfg.Password.Memory,
+		Iterations:  cfg.Password.Iterations,
+		Parallelism: cfg.Password.Parallelism,
+		SaltLength:  cfg.Password.SaltLength,
+		KeyLength:   cfg.Password.KeyLength,
+	})
+
+	passwordPolicy, err := password.NewPolicy(password.PolicyConfig{
+		MinScore:     cfg.PasswordPolicy.MinScore,
+		BreachedPath: cfg.PasswordPolicy.BreachedPath,
+	})
+	if err != nil {
+		log.Err(err).Msg("loading password policy")
+		return err
+	}
+
+	userCore := userUsecase.NewCore(log, userStorer, passwordHasher, passwordPolicy, auth, auditLog)
 	userService := userService.NewService(log, auth, userCore, mq)
 
-	authCore := authUsecase.NewCore(log, authStorer)
-	authService := authService.NewService(log, auth, authCore, mq)
+	authCore := authUsecase.NewCore(log, authStorer, passwordHasher, passwordPolicy, auth, auditLog)
+	loginLockout := lockout.New(lockout.Config{
+		Cache:         redis,
+		Log:           log,
+		MaxAttempts:   cfg.Auth.LoginMaxAttempts,
+		MaxIPAttempts: cfg.Auth.LoginMaxIPAttempts,
+		Delay:         cfg.Auth.LoginDelay,
+		LockDuration:  cfg.Auth.LoginLockDuration,
+	})
+	authService := authService.NewService(log, auth, authCore, mq, loginLockout)
 
-	listCore := listUsecase.NewCore(log, listStorer)
-	listService := listService.NewService(log, listCore)
+	listBroker := listProvider.NewBroker(log, ex)
+	brokerInit := make(chan error, 1)
+	go func() {
+		if err := listBroker.Run(brokerInit); err != nil {
+			log.Err(err).Msg(ErrRunBroker.Error())
+		}
+	}()
+	if err := <-brokerInit; err != nil {
+		log.Err(err).Msg(ErrRunBroker.Error())
+		return ErrRunBroker
+	}
+
+	listCore := listUsecase.NewCore(log, listStorer, listBroker, auditLog)
+	listService := listService.NewService(log, listCore, mq)
+
+	auditCore := auditUsecase.NewCore(log, auditStorer)
+	auditService := auditService.NewService(log, auditCore)
+
+	exportCtx, exportCancel := context.WithTimeout(context.Background(), cfg.API.ShutdownTimeout)
+	exportServer, err := exportProvider.NewServer(exportCtx, exportProvider.ServerConfig{
+		Log:        log,
+		Host:       utils.GetHost(cfg.ImageServer.HostName, cfg.ImageServer.Port),
+		AccessKey:  cfg.ImageServer.AccessKey,
+		SecretKey:  cfg.ImageServer.SecretKey,
+		BucketName: cfg.Export.BucketName,
+		Expiry:     cfg.Export.LinkTTL,
+	})
+	exportCancel()
+	if err != nil {
+		log.Err(err).Msg("starting export server")
+		return err
+	}
+	exportStorer := exportProvider.NewStorer(log, db)
+	exportCore := exportUsecase.NewCore(log, exportStorer, exportServer, imageServer, cfg.Export.LinkTTL, auditLog)
+	exportService := exportService.NewService(log, exportCore, exportQ, mq)
+	exportAgent, err := export.New(export.Config{
+		Log:           log,
+		ExportService: exportService,
+	})
+	if err != nil {
+		log.Err(err).Msg(ErrCreateExportAgent.Error())
+		return ErrCreateExportAgent
+	}
 
 	userCon := api.NewUserController(log, userService, auth, cfg.API.RateLimit)
 	userCon.RegisterRoutes(app)
@@ -280,6 +395,12 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 	imageCon := api.NewImageController(log, imageService, auth, cfg.API.RateLimit)
 	imageCon.RegisterRoutes(app)
 
+	adminCon := api.NewAdminController(log, userService, listService, authService, auditService, auth)
+	adminCon.RegisterRoutes(app)
+
+	exportCon := api.NewExportController(log, exportService, auth, cfg.API.RateLimit)
+	exportCon.RegisterRoutes(app)
+
 	h2s := &http2.Server{}
 
 	api := &http.Server{
@@ -317,6 +438,9 @@ func Run(build string, log *zerolog.Logger, cfg *config.Config) error {
 		defer cancel()
 
 		mailAgent.Shutdown(ctx)
+		exportAgent.Shutdown(ctx)
+		// ends the event streams, they would hold the server shutdown
+		listBroker.Close()
 
 		if err := api.Shutdown(ctx); err != nil {
 			api.Close()
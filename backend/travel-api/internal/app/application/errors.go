@@ -3,15 +3,20 @@ package application
 import "errors"
 
 var (
-	ErrInitConnDB       = errors.New("api: error initializing connection to db")
-	ErrCreateKeyStore   = errors.New("api: error creating keystore")
-	ErrCreateBroker     = errors.New("api: error creating message broker")
-	ErrCreateQueue      = errors.New("api: error creating message queue")
-	ErrCreateMailServer = errors.New("api: error creating mail server")
-	ErrConnRedis        = errors.New("api: error connecting to redis")
-	ErrConatructAuth    = errors.New("api: error constructing auth")
-	ErrRunDebug         = errors.New("debug: error running debug server")
-	ErrRunServer        = errors.New("api: error running http2 server")
-	ErrStartServer      = errors.New("api: error starting http2 server")
-	ErrGracefulShutdown = errors.New("api: error gracefully shutdown http2 server")
+	ErrInitConnDB        = errors.New("api: error initializing connection to db")
+	ErrCreateKeyStore    = errors.New("api: error creating keystore")
+	ErrCreatePolicy      = errors.New("api: error loading role permissions")
+	ErrCreateBroker      = errors.New("api: error creating message broker")
+	ErrCreateQueue       = errors.New("api: error creating message queue")
+	ErrCreateExchange    = errors.New("api: error creating message exchange")
+	ErrRunBroker         = errors.New("api: error running list events broker")
+	ErrCreateMailServer  = errors.New("api: error creating mail server")
+	ErrCreateExportAgent = errors.New("api: error creating export agent")
+	ErrConnRedis         = errors.New("api: error connecting to redis")
+	ErrConatructAuth     = errors.New("api: error constructing auth")
+	ErrTrustedProxies    = errors.New("api: error parsing trusted proxies")
+	ErrRunDebug          = errors.New("debug: error running debug server")
+	ErrRunServer         = errors.New("api: error running http2 server")
+	ErrStartServer       = errors.New("api: error starting http2 server")
+	ErrGracefulShutdown  = errors.New("api: error gracefully shutdown http2 server")
 )
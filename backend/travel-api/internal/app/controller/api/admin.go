@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+
+	auditService "github.com/f4mk/travel/backend/travel-api/internal/app/service/audit"
+	authService "github.com/f4mk/travel/backend/travel-api/internal/app/service/auth"
+	listService "github.com/f4mk/travel/backend/travel-api/internal/app/service/list"
+	userService "github.com/f4mk/travel/backend/travel-api/internal/app/service/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/middleware"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+type AdminController struct {
+	Log          *zerolog.Logger
+	UserService  *userService.Service
+	ListService  *listService.Service
+	AuthService  *authService.Service
+	AuditService *auditService.Service
+	Auth         *auth.Auth
+}
+
+func NewAdminController(
+	l *zerolog.Logger,
+	us *userService.Service,
+	ls *listService.Service,
+	as *authService.Service,
+	aus *auditService.Service,
+	a *auth.Auth,
+) *AdminController {
+	return &AdminController{
+		Log:          l,
+		UserService:  us,
+		ListService:  ls,
+		AuthService:  as,
+		AuditService: aus,
+		Auth:         a,
+	}
+}
+
+// RegisterRoutes registers the /admin routes, each of them requires
+// the permission of the action. No scopes, personal access tokens
+// cannot be used for administration.
+func (ac *AdminController) RegisterRoutes(app *web.App) {
+	require := func(perms ...string) []web.Middleware {
+		return []web.Middleware{middleware.Authenticate(ac.Auth), middleware.RequirePermission(perms...)}
+	}
+
+	app.Handle(http.MethodGet, "/admin/users", ac.UserService.GetUsers, require(auth.PermUserReadAny)...)
+	app.Handle(http.MethodGet, "/admin/users/:id", ac.UserService.GetUserAdmin, require(auth.PermUserReadAny)...)
+	app.Handle(http.MethodDelete, "/admin/users/:id", ac.UserService.DeleteUserAdmin, require(auth.PermUserBan)...)
+	app.Handle(http.MethodPost, "/admin/users/:id/restore", ac.UserService.RestoreUser, require(auth.PermUserBan)...)
+	app.Handle(http.MethodPost, "/admin/users/:id/activate", ac.UserService.ActivateUser, require(auth.PermUserBan)...)
+	app.Handle(http.MethodPost, "/admin/users/:id/deactivate", ac.UserService.DeactivateUser, require(auth.PermUserBan)...)
+	app.Handle(http.MethodPost, "/admin/users/:id/logout", ac.AuthService.ForceLogout, require(auth.PermUserBan)...)
+	app.Handle(http.MethodPut, "/admin/users/:id/roles", ac.UserService.UpdateUserRoles, require(auth.PermUserRoleAssign)...)
+	app.Handle(
+		http.MethodGet,
+		"/admin/users/:id/lists",
+		ac.ListService.GetUserLists,
+		require(auth.PermUserReadAny, auth.PermListReadAny)...,
+	)
+
+	// the list handlers authorize the permissions to act on any list
+	app.Handle(http.MethodDelete, "/admin/lists/:listID", ac.ListService.DeleteList, require(auth.PermListManageAny)...)
+	app.Handle(
+		http.MethodDelete,
+		"/admin/lists/:listID/items/:itemID",
+		ac.ListService.DeleteItem,
+		require(auth.PermListManageAny)...,
+	)
+
+	app.Handle(http.MethodGet, "/admin/roles", ac.AuthService.GetRoles, require(auth.PermRoleManage)...)
+	app.Handle(http.MethodPut, "/admin/roles/:role", ac.AuthService.UpdateRole, require(auth.PermRoleManage)...)
+	app.Handle(http.MethodDelete, "/admin/roles/:role", ac.AuthService.DeleteRole, require(auth.PermRoleManage)...)
+
+	app.Handle(http.MethodGet, "/admin/audit", ac.AuditService.GetEvents, require(auth.PermAuditRead)...)
+}
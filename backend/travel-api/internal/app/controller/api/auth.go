@@ -33,7 +33,36 @@ func NewAuthController(
 
 func (ac *AuthController) RegisterRoutes(app *web.App) {
 	// TODO: login takes too long
-	app.Handle(http.MethodPost, "/auth/login", ac.AuthService.Login)
+	app.Handle(
+		http.MethodPost,
+		"/auth/login",
+		ac.AuthService.Login,
+		middleware.RateLimit(ac.Log, ac.RateLimit),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/login/2fa",
+		ac.AuthService.LoginTwoFactor,
+		middleware.RateLimit(ac.Log, ac.RateLimit),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/magic-link",
+		ac.AuthService.MagicLink,
+		middleware.RateLimit(ac.Log, ac.RateLimit),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/magic-link/submit",
+		ac.AuthService.MagicLinkSubmit,
+		middleware.RateLimit(ac.Log, ac.RateLimit),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/unlock",
+		ac.AuthService.Unlock,
+		middleware.RateLimit(ac.Log, ac.RateLimit),
+	)
 	app.Handle(
 		http.MethodPost,
 		"/auth/logout",
@@ -76,4 +105,40 @@ func (ac *AuthController) RegisterRoutes(app *web.App) {
 		ac.AuthService.PasswordResetSubmit,
 		middleware.RateLimit(ac.Log, ac.RateLimit),
 	)
+	app.Handle(
+		http.MethodGet,
+		"/auth/sessions",
+		ac.AuthService.GetSessions,
+		middleware.Authenticate(ac.Auth),
+	)
+	app.Handle(
+		http.MethodDelete,
+		"/auth/sessions/:sessionID",
+		ac.AuthService.TerminateSession,
+		middleware.Authenticate(ac.Auth),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/2fa/setup",
+		ac.AuthService.SetupTOTP,
+		middleware.Authenticate(ac.Auth),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/2fa/verify",
+		ac.AuthService.VerifyTOTP,
+		middleware.Authenticate(ac.Auth),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/auth/2fa/disable",
+		ac.AuthService.DisableTOTP,
+		middleware.Authenticate(ac.Auth),
+	)
+	app.Handle(
+		http.MethodGet,
+		"/.well-known/jwks.json",
+		ac.AuthService.JWKS,
+		middleware.RateLimit(ac.Log, ac.RateLimit),
+	)
 }
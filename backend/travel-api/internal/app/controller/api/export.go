@@ -0,0 +1,51 @@
+package api
+
+import (
+	"net/http"
+
+	exportService "github.com/f4mk/travel/backend/travel-api/internal/app/service/export"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/middleware"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+type ExportController struct {
+	Log           *zerolog.Logger
+	ExportService *exportService.Service
+	Auth          *auth.Auth
+	RateLimit     int
+}
+
+func NewExportController(
+	l *zerolog.Logger,
+	es *exportService.Service,
+	a *auth.Auth,
+	rl int,
+) *ExportController {
+	return &ExportController{
+		Log:           l,
+		ExportService: es,
+		Auth:          a,
+		RateLimit:     rl,
+	}
+}
+
+func (ec *ExportController) RegisterRoutes(app *web.App) {
+	// no scopes, personal access tokens cannot export the account
+	app.Handle(
+		http.MethodPost,
+		"/users/me/export",
+		ec.ExportService.RequestExport,
+		middleware.RateLimit(ec.Log, ec.RateLimit),
+		middleware.Authenticate(ec.Auth),
+	)
+	// the link of the letter, the download token stands for the login.
+	// An archive takes longer than the request timeout to send.
+	app.HandleStream(
+		http.MethodGet,
+		"/users/export/download",
+		ec.ExportService.Download,
+		middleware.RateLimit(ec.Log, ec.RateLimit),
+	)
+}
@@ -33,6 +33,12 @@ func NewImageController(
 }
 
 func (ic *ImageController) RegisterRoutes(app *web.App) {
-	app.Handle(http.MethodGet, "/images/:fname", ic.ImageService.Serve, middleware.Authenticate(ic.Auth))
-	app.Handle(http.MethodPost, "/images/upload/:listID", ic.ImageService.Store, middleware.Authenticate(ic.Auth))
+	// scopes personal access tokens need, they go before Authenticate
+	read := middleware.RequireScope(auth.ScopeImagesRead)
+	write := middleware.RequireScope(auth.ScopeImagesWrite)
+
+	app.Handle(http.MethodGet, "/images/:fname", ic.ImageService.Serve, read, middleware.Authenticate(ic.Auth))
+	app.Handle(http.MethodPost, "/images/upload/:listID", ic.ImageService.Store, write, middleware.Authenticate(ic.Auth))
+
+	app.Handle(http.MethodGet, "/public/lists/:slug/images/:fname", ic.ImageService.ServePublic, middleware.RateLimit(ic.Log, ic.RateLimit))
 }
@@ -32,17 +32,44 @@ func NewListController(
 }
 
 func (lc *ListController) RegisterRoutes(app *web.App) {
-	app.Handle(http.MethodGet, "/lists", lc.ListService.GetLists, middleware.Authenticate(lc.Auth))
-	app.Handle(http.MethodPost, "/lists", lc.ListService.CreateList, middleware.Authenticate(lc.Auth))
+	// scopes personal access tokens need, they go before Authenticate
+	read := middleware.RequireScope(auth.ScopeListsRead)
+	write := middleware.RequireScope(auth.ScopeListsWrite)
 
-	app.Handle(http.MethodGet, "/lists/:listID", lc.ListService.GetList, middleware.Authenticate(lc.Auth))
-	app.Handle(http.MethodPut, "/lists/:listID", lc.ListService.UpdateList, middleware.Authenticate(lc.Auth))
-	app.Handle(http.MethodDelete, "/lists/:listID", lc.ListService.DeleteList, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodGet, "/lists", lc.ListService.GetLists, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPost, "/lists", lc.ListService.CreateList, write, middleware.Authenticate(lc.Auth))
 
-	app.Handle(http.MethodGet, "/lists/:listID/items", lc.ListService.GetItems, middleware.Authenticate(lc.Auth))
-	app.Handle(http.MethodPost, "/lists/:listID/items", lc.ListService.CreateItem, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodGet, "/lists/:listID", lc.ListService.GetList, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPut, "/lists/:listID", lc.ListService.UpdateList, write, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodDelete, "/lists/:listID", lc.ListService.DeleteList, write, middleware.Authenticate(lc.Auth))
 
-	app.Handle(http.MethodGet, "/lists/:listID/items/:itemID", lc.ListService.GetItem, middleware.Authenticate(lc.Auth))
-	app.Handle(http.MethodPut, "/lists/:listID/items/:itemID", lc.ListService.UpdateItem, middleware.Authenticate(lc.Auth))
-	app.Handle(http.MethodDelete, "/lists/:listID/items/:itemID", lc.ListService.DeleteItem, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodGet, "/lists/:listID/items", lc.ListService.GetItems, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPost, "/lists/:listID/items", lc.ListService.CreateItem, write, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodGet, "/lists/:listID/items/:itemID", lc.ListService.GetItem, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPut, "/lists/:listID/items/:itemID", lc.ListService.UpdateItem, write, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodDelete, "/lists/:listID/items/:itemID", lc.ListService.DeleteItem, write, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodGet, "/lists/:listID/export", lc.ListService.ExportList, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPost, "/lists/:listID/optimize", lc.ListService.OptimizeRoute, write, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPost, "/lists/:listID/import", lc.ListService.ImportItems, write, middleware.Authenticate(lc.Auth))
+	app.HandleStream(http.MethodGet, "/lists/:listID/events", lc.ListService.StreamEvents, read, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodGet, "/lists/:listID/members", lc.ListService.GetMembers, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPost, "/lists/:listID/members", lc.ListService.AddMember, write, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodPut, "/lists/:listID/members/:userID", lc.ListService.UpdateMember, write, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodDelete, "/lists/:listID/members/:userID", lc.ListService.DeleteMember, write, middleware.Authenticate(lc.Auth))
+
+	// listing the links is part of managing them, so it takes the write scope
+	app.Handle(http.MethodGet, "/lists/:listID/shares", lc.ListService.GetShares, write, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodPost, "/lists/:listID/shares", lc.ListService.CreateShare, write, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodDelete, "/lists/:listID/shares/:shareID", lc.ListService.DeleteShare, write, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodGet, "/items/near", lc.ListService.GetItemsNear, read, middleware.Authenticate(lc.Auth))
+	app.Handle(http.MethodGet, "/items/bbox", lc.ListService.GetItemsInBBox, read, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodGet, "/search", lc.ListService.Search, read, middleware.Authenticate(lc.Auth))
+
+	app.Handle(http.MethodGet, "/public/lists/:slug", lc.ListService.GetPublicList, middleware.RateLimit(lc.Log, lc.RateLimit))
 }
@@ -39,6 +39,21 @@ func (uc *UserController) RegisterRoutes(app *web.App) {
 		middleware.RateLimit(uc.Log, uc.RateLimit),
 	)
 	app.Handle(http.MethodGet, "/users/me", uc.UserService.GetMe, middleware.Authenticate(uc.Auth))
+	// no scopes, personal access tokens cannot manage tokens
+	app.Handle(http.MethodGet, "/users/me/tokens", uc.UserService.GetAccessTokens, middleware.Authenticate(uc.Auth))
+	app.Handle(
+		http.MethodPost,
+		"/users/me/tokens",
+		uc.UserService.CreateAccessToken,
+		middleware.RateLimit(uc.Log, uc.RateLimit),
+		middleware.Authenticate(uc.Auth),
+	)
+	app.Handle(
+		http.MethodDelete,
+		"/users/me/tokens/:tokenID",
+		uc.UserService.DeleteAccessToken,
+		middleware.Authenticate(uc.Auth),
+	)
 	app.Handle(http.MethodGet, "/users/:id", uc.UserService.GetUser, middleware.Authenticate(uc.Auth))
 	app.Handle(
 		http.MethodPut,
@@ -59,4 +74,18 @@ func (uc *UserController) RegisterRoutes(app *web.App) {
 		uc.UserService.VerifyUser,
 		middleware.RateLimit(uc.Log, uc.RateLimit),
 	)
+	// no scopes, personal access tokens cannot change the login
+	app.Handle(
+		http.MethodPost,
+		"/users/me/email",
+		uc.UserService.ChangeEmail,
+		middleware.RateLimit(uc.Log, uc.RateLimit),
+		middleware.Authenticate(uc.Auth),
+	)
+	app.Handle(
+		http.MethodPost,
+		"/users/email/confirm",
+		uc.UserService.ConfirmEmail,
+		middleware.RateLimit(uc.Log, uc.RateLimit),
+	)
 }
@@ -0,0 +1,63 @@
+package export
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/service/export"
+	"github.com/rs/zerolog"
+)
+
+type Config struct {
+	Log           *zerolog.Logger
+	ExportService *export.Service
+}
+
+type Agent struct {
+	service  *export.Service
+	log      *zerolog.Logger
+	shutdown context.CancelFunc
+}
+
+func New(cfg Config) (*Agent, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ea := Agent{
+		service:  cfg.ExportService,
+		log:      cfg.Log,
+		shutdown: cancel,
+	}
+
+	errMsgCh := make(chan export.ServeError, 1)
+	errServiceCh := make(chan error)
+	go ea.service.Serve(ctx, errMsgCh, errServiceCh)
+
+	go func() {
+		for errMsg := range errMsgCh {
+			ea.log.Err(errMsg.Error).Msg("error processing message in export agent")
+		}
+	}()
+	err, ok := <-errServiceCh
+	if ok {
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+	return &ea, nil
+}
+
+func (ea *Agent) Shutdown(ctx context.Context) {
+	ea.log.Warn().Msg("shutting down export agent")
+	ea.shutdown()
+	doneCh := make(chan struct{})
+	go func() {
+		<-ea.service.Stop()
+		close(doneCh)
+	}()
+
+	select {
+	case <-doneCh:
+	case <-ctx.Done():
+		ea.log.Error().Msg("error graceful shutdown export service")
+	}
+}
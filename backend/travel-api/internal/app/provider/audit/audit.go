@@ -0,0 +1,95 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	auditUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+)
+
+type Storer struct {
+	repo *sqlx.DB
+	log  *zerolog.Logger
+}
+
+func NewStorer(l *zerolog.Logger, r *sqlx.DB) *Storer {
+	return &Storer{repo: r, log: l}
+}
+
+func (s *Storer) QueryEvents(ctx context.Context, eq auditUsecase.EventsQuery) (auditUsecase.EventsPage, error) {
+	ctx, span := web.AddSpan(ctx, "provider.audit.query-events")
+	defer span.End()
+	where := []string{"TRUE"}
+	args := []any{}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	eqFilters := []struct {
+		column string
+		value  *string
+	}{
+		{"actor_id", eq.ActorID},
+		{"action", eq.Action},
+		{"target_type", eq.TargetType},
+		{"target_id", eq.TargetID},
+		{"trace_id", eq.TraceID},
+	}
+	for _, f := range eqFilters {
+		if f.value != nil {
+			where = append(where, fmt.Sprintf("%s = %s", f.column, arg(*f.value)))
+		}
+	}
+	if eq.From != nil {
+		where = append(where, fmt.Sprintf("date_created >= %s", arg(eq.From.UTC())))
+	}
+	if eq.To != nil {
+		where = append(where, fmt.Sprintf("date_created < %s", arg(eq.To.UTC())))
+	}
+	conds := strings.Join(where, " AND ")
+
+	res := auditUsecase.EventsPage{Events: []auditUsecase.Event{}}
+	qCount := fmt.Sprintf(`SELECT COUNT(*) FROM audit_events WHERE %s;`, conds)
+	if err := s.repo.GetContext(ctx, &res.Total, qCount, args...); err != nil {
+		return auditUsecase.EventsPage{}, err
+	}
+	q := fmt.Sprintf(`SELECT * FROM audit_events WHERE %s ORDER BY date_created DESC, event_id LIMIT %s OFFSET %s;`,
+		conds, arg(eq.PageSize), arg((eq.Page-1)*eq.PageSize))
+	events := []StorerEvent{}
+	if err := s.repo.SelectContext(ctx, &events, q, args...); err != nil {
+		return auditUsecase.EventsPage{}, err
+	}
+	for _, e := range events {
+		ev := auditUsecase.Event{
+			ID:          e.ID,
+			ActorID:     e.ActorID,
+			Action:      e.Action,
+			TargetType:  e.TargetType,
+			TargetID:    e.TargetID,
+			TraceID:     e.TraceID,
+			IP:          e.IP,
+			DateCreated: e.DateCreated,
+		}
+		if err := unmarshal(e.Before, &ev.Before); err != nil {
+			return auditUsecase.EventsPage{}, err
+		}
+		if err := unmarshal(e.After, &ev.After); err != nil {
+			return auditUsecase.EventsPage{}, err
+		}
+		res.Events = append(res.Events, ev)
+	}
+	return res, nil
+}
+
+// unmarshal leaves the fields nil for a NULL column.
+func unmarshal(data []byte, f *map[string]any) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(data, f)
+}
@@ -0,0 +1,16 @@
+package audit
+
+import "time"
+
+type StorerEvent struct {
+	ID          string    `db:"event_id"`
+	ActorID     *string   `db:"actor_id"`
+	Action      string    `db:"action"`
+	TargetType  string    `db:"target_type"`
+	TargetID    string    `db:"target_id"`
+	TraceID     string    `db:"trace_id"`
+	IP          string    `db:"ip"`
+	Before      []byte    `db:"before"`
+	After       []byte    `db:"after"`
+	DateCreated time.Time `db:"date_created"`
+}
@@ -149,3 +149,11 @@ func (s *Storer) DeleteToken(ctx context.Context, t authUsecase.DeleteToken) err
 	_, err := s.repo.NamedExecContext(ctx, q, token)
 	return err
 }
+
+func (s *Storer) UpdatePasswordHash(ctx context.Context, userID string, hash []byte) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.update-password-hash")
+	defer span.End()
+	q := `UPDATE users SET password_hash = $1 WHERE user_id = $2;`
+	_, err := s.repo.ExecContext(ctx, q, hash, userID)
+	return err
+}
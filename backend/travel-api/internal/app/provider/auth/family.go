@@ -0,0 +1,109 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Storer) QueryTokenFamily(ctx context.Context, familyID string) (authUsecase.TokenFamily, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.query-token-family")
+	defer span.End()
+	family := StorerTokenFamily{}
+	q := `SELECT * FROM token_families WHERE family_id = $1;`
+	if err := s.repo.GetContext(ctx, &family, q, familyID); err != nil {
+		return authUsecase.TokenFamily{}, err
+	}
+	res := authUsecase.TokenFamily{
+		ID:             family.ID,
+		UserID:         family.UserID,
+		CurrentTokenID: family.CurrentTokenID,
+		ExpiresAt:      family.ExpiresAt,
+		DateCreated:    family.DateCreated,
+		DateUpdated:    family.DateUpdated,
+		RevokedAt:      family.RevokedAt,
+	}
+	return res, nil
+}
+
+// RotateToken swaps the current token of a live family and records the used one,
+// it returns sql.ErrNoRows if the token is not the current one.
+func (s *Storer) RotateToken(ctx context.Context, rt authUsecase.RotateToken) (err error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.rotate-token")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	token := StorerRotateToken{
+		FamilyID:     rt.FamilyID,
+		TokenID:      rt.TokenID,
+		ExpiresAt:    rt.ExpiresAt,
+		NewTokenID:   rt.NewTokenID,
+		NewExpiresAt: rt.NewExpiresAt,
+		UsedAt:       rt.UsedAt,
+		UserAgent:    rt.UserAgent,
+		IP:           rt.IP,
+	}
+	tx, err := s.repo.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				s.log.Err(rErr).Str("TraceID", tID).Msg("failed to rollback after error")
+			}
+		}
+	}()
+	qFamily := `UPDATE token_families SET
+	current_token_id = :new_token_id,
+	expires_at = :new_expires_at,
+	date_updated = :used_at
+	WHERE family_id = :family_id
+	AND current_token_id = :token_id
+	AND revoked_at IS NULL;`
+	qUsed := `INSERT INTO used_refresh_tokens (token_id, family_id, expires_at, used_at)
+	VALUES (:token_id, :family_id, :expires_at, :used_at);`
+	qSession := `UPDATE sessions SET
+	last_seen = :used_at, user_agent = :user_agent, ip = :ip
+	WHERE session_id = :family_id;`
+	res, err := tx.NamedExecContext(ctx, qFamily, token)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		err = sql.ErrNoRows
+		return err
+	}
+	if _, err = tx.NamedExecContext(ctx, qUsed, token); err != nil {
+		return err
+	}
+	if _, err = tx.NamedExecContext(ctx, qSession, token); err != nil {
+		return err
+	}
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storer) RevokeTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.revoke-token-family")
+	defer span.End()
+	q := `UPDATE token_families SET revoked_at = $1, date_updated = $1
+	WHERE family_id = $2 AND revoked_at IS NULL;`
+	_, err := s.repo.ExecContext(ctx, q, revokedAt, familyID)
+	return err
+}
+
+func (s *Storer) RevokeTokenFamiliesByUserID(ctx context.Context, userID string, revokedAt time.Time) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.revoke-token-families-by-user-id")
+	defer span.End()
+	q := `UPDATE token_families SET revoked_at = $1, date_updated = $1
+	WHERE user_id = $2 AND revoked_at IS NULL;`
+	_, err := s.repo.ExecContext(ctx, q, revokedAt, userID)
+	return err
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"context"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Storer) StoreMagicLinkToken(ctx context.Context, mt authUsecase.MagicLinkToken) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.store-magic-link-token")
+	defer span.End()
+	token := StorerMagicLinkToken{
+		TokenID:   mt.TokenID,
+		UserID:    mt.UserID,
+		IssuedAt:  mt.IssuedAt,
+		ExpiresAt: mt.ExpiresAt,
+	}
+	q := `INSERT INTO magic_link_tokens (token_id, user_id, expires_at, issued_at)
+	VALUES (:token_id, :user_id, :expires_at, :issued_at);
+	`
+	_, err := s.repo.NamedExecContext(ctx, q, token)
+	return err
+}
+
+func (s *Storer) DeleteMagicLinkTokensByUserID(ctx context.Context, uID string) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.delete-magic-link-tokens-by-user-id")
+	defer span.End()
+	q := `DELETE FROM magic_link_tokens WHERE user_id = $1;`
+	_, err := s.repo.ExecContext(ctx, q, uID)
+	return err
+}
+
+// ConsumeMagicLinkToken deletes the token and returns it, so that of
+// concurrent uses only one gets the token. It returns sql.ErrNoRows
+// if there is no such token.
+func (s *Storer) ConsumeMagicLinkToken(ctx context.Context, tokenID string) (authUsecase.MagicLinkToken, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.consume-magic-link-token")
+	defer span.End()
+	token := StorerMagicLinkToken{}
+	q := `DELETE FROM magic_link_tokens WHERE token_id = $1 RETURNING *;`
+	if err := s.repo.GetContext(ctx, &token, q, tokenID); err != nil {
+		return authUsecase.MagicLinkToken{}, err
+	}
+	res := authUsecase.MagicLinkToken{
+		TokenID:   token.TokenID,
+		UserID:    token.UserID,
+		IssuedAt:  token.IssuedAt,
+		ExpiresAt: token.ExpiresAt,
+	}
+	return res, nil
+}
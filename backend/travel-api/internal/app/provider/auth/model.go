@@ -25,6 +25,14 @@ type StorerResetToken struct {
 	IssuedAt  time.Time `db:"issued_at"`
 	ExpiresAt time.Time `db:"expires_at"`
 }
+
+type StorerMagicLinkToken struct {
+	TokenID   string    `db:"token_id"`
+	UserID    string    `db:"user_id"`
+	IssuedAt  time.Time `db:"issued_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
 type StorerDeleteToken struct {
 	TokenID      string    `db:"token_id"`
 	Subject      string    `db:"subject"`
@@ -33,3 +41,63 @@ type StorerDeleteToken struct {
 	ExpiresAt    time.Time `db:"expires_at"`
 	RevokedAt    time.Time `db:"revoked_at"`
 }
+type StorerTokenFamily struct {
+	ID             string     `db:"family_id"`
+	UserID         string     `db:"user_id"`
+	CurrentTokenID string     `db:"current_token_id"`
+	ExpiresAt      time.Time  `db:"expires_at"`
+	DateCreated    time.Time  `db:"date_created"`
+	DateUpdated    time.Time  `db:"date_updated"`
+	RevokedAt      *time.Time `db:"revoked_at"`
+}
+type StorerRotateToken struct {
+	FamilyID     string    `db:"family_id"`
+	TokenID      string    `db:"token_id"`
+	ExpiresAt    time.Time `db:"expires_at"`
+	NewTokenID   string    `db:"new_token_id"`
+	NewExpiresAt time.Time `db:"new_expires_at"`
+	UsedAt       time.Time `db:"used_at"`
+	UserAgent    string    `db:"user_agent"`
+	IP           string    `db:"ip"`
+}
+type StorerSession struct {
+	ID          string    `db:"session_id"`
+	UserID      string    `db:"user_id"`
+	DeviceName  string    `db:"device_name"`
+	UserAgent   string    `db:"user_agent"`
+	IP          string    `db:"ip"`
+	DateCreated time.Time `db:"date_created"`
+	LastSeen    time.Time `db:"last_seen"`
+}
+type rowSession struct {
+	StorerSession
+	ExpiresAt time.Time  `db:"expires_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+type StorerTOTP struct {
+	UserID      string    `db:"user_id"`
+	Secret      string    `db:"secret"`
+	Enabled     bool      `db:"enabled"`
+	LastStep    int64     `db:"last_step"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+type StorerRecoveryCode struct {
+	CodeHash    string     `db:"code_hash"`
+	UserID      string     `db:"user_id"`
+	DateCreated time.Time  `db:"date_created"`
+	DateUsed    *time.Time `db:"date_used"`
+}
+
+type StorerRole struct {
+	Name        string         `db:"role"`
+	Description string         `db:"description"`
+	Permissions pq.StringArray `db:"permissions"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+type StorerRolePermission struct {
+	Role       string `db:"role"`
+	Permission string `db:"permission"`
+}
@@ -0,0 +1,142 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const selectRoles = `SELECT
+	roles.role,
+	roles.description,
+	COALESCE(
+		array_agg(role_permissions.permission ORDER BY role_permissions.permission)
+			FILTER (WHERE role_permissions.permission IS NOT NULL),
+		'{}'
+	) AS permissions,
+	roles.date_created,
+	roles.date_updated
+FROM roles
+LEFT JOIN role_permissions ON role_permissions.role = roles.role`
+
+func (s *Storer) QueryRoles(ctx context.Context) ([]authUsecase.Role, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.query-roles")
+	defer span.End()
+	rs := []StorerRole{}
+	q := selectRoles + `
+	GROUP BY roles.role
+	ORDER BY roles.role;`
+	if err := s.repo.SelectContext(ctx, &rs, q); err != nil {
+		return nil, err
+	}
+	res := make([]authUsecase.Role, 0, len(rs))
+	for _, r := range rs {
+		res = append(res, fromStorerRole(r))
+	}
+	return res, nil
+}
+
+func (s *Storer) QueryRole(ctx context.Context, name string) (authUsecase.Role, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.query-role")
+	defer span.End()
+	r := StorerRole{}
+	q := selectRoles + `
+	WHERE roles.role = $1
+	GROUP BY roles.role;`
+	if err := s.repo.GetContext(ctx, &r, q, name); err != nil {
+		return authUsecase.Role{}, err
+	}
+	return fromStorerRole(r), nil
+}
+
+// UpsertRole creates the role or updates it, its permissions are replaced.
+func (s *Storer) UpsertRole(ctx context.Context, r authUsecase.Role) (err error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.upsert-role")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	role := StorerRole{
+		Name:        r.Name,
+		Description: r.Description,
+		DateCreated: r.DateCreated,
+		DateUpdated: r.DateUpdated,
+	}
+	perms := make([]StorerRolePermission, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		perms = append(perms, StorerRolePermission{Role: r.Name, Permission: p})
+	}
+	tx, err := s.repo.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				s.log.Err(rErr).Str("TraceID", tID).Msg("failed to rollback after error")
+			}
+		}
+	}()
+	qRole := `INSERT INTO roles (role, description, date_created, date_updated)
+	VALUES (:role, :description, :date_created, :date_updated)
+	ON CONFLICT (role) DO UPDATE SET
+		description = EXCLUDED.description,
+		date_updated = EXCLUDED.date_updated;`
+	qDelete := `DELETE FROM role_permissions WHERE role = $1;`
+	qPerms := `INSERT INTO role_permissions (role, permission)
+	VALUES (:role, :permission);`
+	if _, err = tx.NamedExecContext(ctx, qRole, role); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, qDelete, r.Name); err != nil {
+		return err
+	}
+	if len(perms) > 0 {
+		if _, err = tx.NamedExecContext(ctx, qPerms, perms); err != nil {
+			return err
+		}
+	}
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storer) RoleInUse(ctx context.Context, name string) (bool, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.role-in-use")
+	defer span.End()
+	var inUse bool
+	q := `SELECT EXISTS (SELECT 1 FROM users WHERE $1 = ANY(roles));`
+	if err := s.repo.GetContext(ctx, &inUse, q, name); err != nil {
+		return false, err
+	}
+	return inUse, nil
+}
+
+// DeleteRole deletes the role with its permissions,
+// it returns sql.ErrNoRows if there is no such role.
+func (s *Storer) DeleteRole(ctx context.Context, name string) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.delete-role")
+	defer span.End()
+	q := `DELETE FROM roles WHERE role = $1;`
+	res, err := s.repo.ExecContext(ctx, q, name)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func fromStorerRole(r StorerRole) authUsecase.Role {
+	return authUsecase.Role{
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: r.Permissions,
+		DateCreated: r.DateCreated,
+		DateUpdated: r.DateUpdated,
+	}
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const sessionColumns = `sessions.session_id, sessions.user_id, sessions.device_name,
+	sessions.user_agent, sessions.ip, sessions.date_created, sessions.last_seen,
+	token_families.expires_at, token_families.revoked_at`
+
+func (s *Storer) CreateSession(ctx context.Context, tf authUsecase.TokenFamily, ss authUsecase.Session) (err error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.create-session")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	family := StorerTokenFamily{
+		ID:             tf.ID,
+		UserID:         tf.UserID,
+		CurrentTokenID: tf.CurrentTokenID,
+		ExpiresAt:      tf.ExpiresAt,
+		DateCreated:    tf.DateCreated,
+		DateUpdated:    tf.DateUpdated,
+		RevokedAt:      tf.RevokedAt,
+	}
+	session := StorerSession{
+		ID:          ss.ID,
+		UserID:      ss.UserID,
+		DeviceName:  ss.DeviceName,
+		UserAgent:   ss.UserAgent,
+		IP:          ss.IP,
+		DateCreated: ss.DateCreated,
+		LastSeen:    ss.LastSeen,
+	}
+	tx, err := s.repo.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				s.log.Err(rErr).Str("TraceID", tID).Msg("failed to rollback after error")
+			}
+		}
+	}()
+	qFamily := `INSERT INTO token_families (family_id, user_id, current_token_id, expires_at, date_created, date_updated, revoked_at)
+	VALUES (:family_id, :user_id, :current_token_id, :expires_at, :date_created, :date_updated, :revoked_at);`
+	qSession := `INSERT INTO sessions (session_id, user_id, device_name, user_agent, ip, date_created, last_seen)
+	VALUES (:session_id, :user_id, :device_name, :user_agent, :ip, :date_created, :last_seen);`
+	if _, err = tx.NamedExecContext(ctx, qFamily, family); err != nil {
+		return err
+	}
+	if _, err = tx.NamedExecContext(ctx, qSession, session); err != nil {
+		return err
+	}
+	err = tx.Commit()
+	return err
+}
+
+func (s *Storer) QuerySessions(ctx context.Context, userID string, now time.Time) ([]authUsecase.Session, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.query-sessions")
+	defer span.End()
+	rows := []rowSession{}
+	q := `SELECT ` + sessionColumns + ` FROM sessions
+	INNER JOIN token_families ON token_families.family_id = sessions.session_id
+	WHERE sessions.user_id = $1
+	AND token_families.revoked_at IS NULL
+	AND token_families.expires_at > $2
+	ORDER BY sessions.last_seen DESC;`
+	if err := s.repo.SelectContext(ctx, &rows, q, userID, now); err != nil {
+		return nil, err
+	}
+	res := make([]authUsecase.Session, 0, len(rows))
+	for _, row := range rows {
+		res = append(res, fromRowSession(row))
+	}
+	return res, nil
+}
+
+func (s *Storer) QuerySession(ctx context.Context, sessionID string) (authUsecase.Session, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.query-session")
+	defer span.End()
+	row := rowSession{}
+	q := `SELECT ` + sessionColumns + ` FROM sessions
+	INNER JOIN token_families ON token_families.family_id = sessions.session_id
+	WHERE sessions.session_id = $1;`
+	if err := s.repo.GetContext(ctx, &row, q, sessionID); err != nil {
+		return authUsecase.Session{}, err
+	}
+	return fromRowSession(row), nil
+}
+
+func fromRowSession(row rowSession) authUsecase.Session {
+	return authUsecase.Session{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		DeviceName:  row.DeviceName,
+		UserAgent:   row.UserAgent,
+		IP:          row.IP,
+		DateCreated: row.DateCreated,
+		LastSeen:    row.LastSeen,
+		ExpiresAt:   row.ExpiresAt,
+		RevokedAt:   row.RevokedAt,
+	}
+}
@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Storer) QueryTOTP(ctx context.Context, userID string) (authUsecase.TOTP, error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.query-totp")
+	defer span.End()
+	t := StorerTOTP{}
+	q := `SELECT * FROM user_totp WHERE user_id = $1;`
+	if err := s.repo.GetContext(ctx, &t, q, userID); err != nil {
+		return authUsecase.TOTP{}, err
+	}
+	res := authUsecase.TOTP{
+		UserID:      t.UserID,
+		Secret:      t.Secret,
+		Enabled:     t.Enabled,
+		LastStep:    t.LastStep,
+		DateCreated: t.DateCreated,
+		DateUpdated: t.DateUpdated,
+	}
+	return res, nil
+}
+
+// UpsertTOTP stores a pending secret, an enabled one is left untouched.
+func (s *Storer) UpsertTOTP(ctx context.Context, t authUsecase.TOTP) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.upsert-totp")
+	defer span.End()
+	totp := StorerTOTP{
+		UserID:      t.UserID,
+		Secret:      t.Secret,
+		Enabled:     t.Enabled,
+		LastStep:    t.LastStep,
+		DateCreated: t.DateCreated,
+		DateUpdated: t.DateUpdated,
+	}
+	q := `INSERT INTO user_totp (user_id, secret, enabled, last_step, date_created, date_updated)
+	VALUES (:user_id, :secret, :enabled, :last_step, :date_created, :date_updated)
+	ON CONFLICT (user_id) DO UPDATE SET
+	secret = EXCLUDED.secret,
+	last_step = EXCLUDED.last_step,
+	date_updated = EXCLUDED.date_updated
+	WHERE user_totp.enabled = false;`
+	_, err := s.repo.NamedExecContext(ctx, q, totp)
+	return err
+}
+
+// EnableTOTP enables a pending secret and replaces the recovery codes of the user,
+// it returns sql.ErrNoRows if there is no pending secret.
+func (s *Storer) EnableTOTP(ctx context.Context, t authUsecase.TOTP, rcs []authUsecase.RecoveryCode) (err error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.enable-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	totp := StorerTOTP{
+		UserID:      t.UserID,
+		Secret:      t.Secret,
+		Enabled:     t.Enabled,
+		LastStep:    t.LastStep,
+		DateCreated: t.DateCreated,
+		DateUpdated: t.DateUpdated,
+	}
+	codes := make([]StorerRecoveryCode, 0, len(rcs))
+	for _, rc := range rcs {
+		codes = append(codes, StorerRecoveryCode{
+			CodeHash:    rc.CodeHash,
+			UserID:      rc.UserID,
+			DateCreated: rc.DateCreated,
+			DateUsed:    rc.DateUsed,
+		})
+	}
+	tx, err := s.repo.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				s.log.Err(rErr).Str("TraceID", tID).Msg("failed to rollback after error")
+			}
+		}
+	}()
+	qTOTP := `UPDATE user_totp SET
+	enabled = :enabled,
+	last_step = :last_step,
+	date_updated = :date_updated
+	WHERE user_id = :user_id
+	AND secret = :secret
+	AND enabled = false;`
+	qDelete := `DELETE FROM totp_recovery_codes WHERE user_id = $1;`
+	qCodes := `INSERT INTO totp_recovery_codes (code_hash, user_id, date_created, date_used)
+	VALUES (:code_hash, :user_id, :date_created, :date_used);`
+	res, err := tx.NamedExecContext(ctx, qTOTP, totp)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		err = sql.ErrNoRows
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, qDelete, t.UserID); err != nil {
+		return err
+	}
+	if len(codes) > 0 {
+		if _, err = tx.NamedExecContext(ctx, qCodes, codes); err != nil {
+			return err
+		}
+	}
+	err = tx.Commit()
+	return err
+}
+
+// UpdateTOTPStep moves the last accepted step forward,
+// it returns sql.ErrNoRows if the step has been accepted already.
+func (s *Storer) UpdateTOTPStep(ctx context.Context, userID string, step int64, updatedAt time.Time) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.update-totp-step")
+	defer span.End()
+	q := `UPDATE user_totp SET last_step = $1, date_updated = $2
+	WHERE user_id = $3 AND enabled = true AND last_step < $1;`
+	res, err := s.repo.ExecContext(ctx, q, step, updatedAt, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// UseRecoveryCode marks the code as used,
+// it returns sql.ErrNoRows if the code does not exist or has been used already.
+func (s *Storer) UseRecoveryCode(ctx context.Context, userID string, codeHash string, usedAt time.Time) error {
+	ctx, span := web.AddSpan(ctx, "provider.auth.use-recovery-code")
+	defer span.End()
+	q := `UPDATE totp_recovery_codes SET date_used = $1
+	WHERE code_hash = $2 AND user_id = $3 AND date_used IS NULL;`
+	res, err := s.repo.ExecContext(ctx, q, usedAt, codeHash, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *Storer) DeleteTOTP(ctx context.Context, userID string) (err error) {
+	ctx, span := web.AddSpan(ctx, "provider.auth.delete-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	tx, err := s.repo.Beginx()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				s.log.Err(rErr).Str("TraceID", tID).Msg("failed to rollback after error")
+			}
+		}
+	}()
+	qCodes := `DELETE FROM totp_recovery_codes WHERE user_id = $1;`
+	qTOTP := `DELETE FROM user_totp WHERE user_id = $1;`
+	if _, err = tx.ExecContext(ctx, qCodes, userID); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, qTOTP, userID); err != nil {
+		return err
+	}
+	err = tx.Commit()
+	return err
+}
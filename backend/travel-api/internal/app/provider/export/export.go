@@ -0,0 +1,223 @@
+package export
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	exportUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/export"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/images"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+)
+
+type Storer struct {
+	repo *sqlx.DB
+	log  *zerolog.Logger
+}
+
+func NewStorer(l *zerolog.Logger, r *sqlx.DB) *Storer {
+	return &Storer{repo: r, log: l}
+}
+
+func (s *Storer) CreateExport(ctx context.Context, e exportUsecase.Export) error {
+	ctx, span := web.AddSpan(ctx, "provider.export.create-export")
+	defer span.End()
+	q := `INSERT INTO data_exports (export_id, user_id, status, token_id, date_created, expires_at)
+	VALUES (:export_id, :user_id, :status, :token_id, :date_created, :expires_at);
+	`
+	_, err := s.repo.NamedExecContext(ctx, q, toStorerExport(e))
+	return err
+}
+
+func (s *Storer) UpdateExport(ctx context.Context, e exportUsecase.Export) error {
+	ctx, span := web.AddSpan(ctx, "provider.export.update-export")
+	defer span.End()
+	q := `UPDATE data_exports
+	SET status = :status, token_id = :token_id, expires_at = :expires_at
+	WHERE export_id = :export_id;
+	`
+	res, err := s.repo.NamedExecContext(ctx, q, toStorerExport(e))
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *Storer) QueryExportByID(ctx context.Context, exportID string) (exportUsecase.Export, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.query-export-by-id")
+	defer span.End()
+	q := `SELECT * FROM data_exports WHERE export_id = $1;`
+	res := StorerExport{}
+	if err := s.repo.GetContext(ctx, &res, q, exportID); err != nil {
+		return exportUsecase.Export{}, err
+	}
+	return fromStorerExport(res), nil
+}
+
+func (s *Storer) QueryExportByTokenID(ctx context.Context, tokenID string) (exportUsecase.Export, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.query-export-by-token-id")
+	defer span.End()
+	q := `SELECT * FROM data_exports WHERE token_id = $1;`
+	res := StorerExport{}
+	if err := s.repo.GetContext(ctx, &res, q, tokenID); err != nil {
+		return exportUsecase.Export{}, err
+	}
+	return fromStorerExport(res), nil
+}
+
+func (s *Storer) HasPendingExport(ctx context.Context, userID string, since time.Time) (bool, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.has-pending-export")
+	defer span.End()
+	q := `SELECT EXISTS (
+		SELECT 1 FROM data_exports
+		WHERE user_id = $1 AND status = $2 AND date_created > $3
+	);`
+	var ok bool
+	if err := s.repo.GetContext(ctx, &ok, q, userID, exportUsecase.StatusPending, since); err != nil {
+		return false, err
+	}
+	return ok, nil
+}
+
+func (s *Storer) QueryProfile(ctx context.Context, userID string) (exportUsecase.Profile, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.query-profile")
+	defer span.End()
+	q := `SELECT user_id, name, email, roles, is_active, date_created, date_updated
+	FROM users WHERE user_id = $1;`
+	res := StorerProfile{}
+	if err := s.repo.GetContext(ctx, &res, q, userID); err != nil {
+		return exportUsecase.Profile{}, err
+	}
+	p := exportUsecase.Profile{
+		ID:          res.ID,
+		Name:        res.Name,
+		Email:       res.Email,
+		Roles:       []string(res.Roles),
+		IsActive:    res.IsActive,
+		DateCreated: res.DateCreated,
+		DateUpdated: res.DateUpdated,
+	}
+	return p, nil
+}
+
+func (s *Storer) QueryLists(ctx context.Context, userID string) ([]exportUsecase.List, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.query-lists")
+	defer span.End()
+	q := `SELECT list_id, list_name, description, private, favorite, completed,
+		date_created, date_updated
+	FROM lists WHERE user_id = $1
+	ORDER BY date_created, list_id;`
+	var res []StorerList
+	if err := s.repo.SelectContext(ctx, &res, q, userID); err != nil {
+		return nil, err
+	}
+	ls := make([]exportUsecase.List, 0, len(res))
+	for _, l := range res {
+		ls = append(ls, exportUsecase.List{
+			ID:          l.ID,
+			Name:        l.Name,
+			Description: l.Description,
+			Private:     l.Private,
+			Favorite:    l.Favorite,
+			Completed:   l.Completed,
+			DateCreated: l.DateCreated,
+			DateUpdated: l.DateUpdated,
+		})
+	}
+	return ls, nil
+}
+
+// QueryItems returns the items of the lists of the user in the list order.
+func (s *Storer) QueryItems(ctx context.Context, userID string) ([]exportUsecase.Item, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.query-items")
+	defer span.End()
+	q := `
+		SELECT items.item_id, items.list_id, items.item_name, items.description,
+			items.address, items.images_id, items.is_visited,
+			items.date_created, items.date_updated,
+			ST_Y(points.location) AS lat, ST_X(points.location) AS lng
+		FROM lists
+		INNER JOIN items ON items.list_id = lists.list_id
+		INNER JOIN points ON points.item_id = items.item_id
+		WHERE lists.user_id = $1
+		ORDER BY lists.list_id, array_position(lists.items, items.item_id) NULLS LAST,
+			items.date_created, items.item_id;
+	`
+	var res []StorerItem
+	if err := s.repo.SelectContext(ctx, &res, q, userID); err != nil {
+		return nil, err
+	}
+	its := make([]exportUsecase.Item, 0, len(res))
+	for _, it := range res {
+		its = append(its, exportUsecase.Item{
+			ID:          it.ID,
+			ListID:      it.ListID,
+			Name:        it.Name,
+			Description: it.Description,
+			Address:     it.Address,
+			Lat:         it.Lat,
+			Lng:         it.Lng,
+			Visited:     it.Visited,
+			ImagesID:    []string(it.ImagesID),
+			DateCreated: it.DateCreated,
+			DateUpdated: it.DateUpdated,
+		})
+	}
+	return its, nil
+}
+
+// QueryImages returns the uploaded images of the user.
+func (s *Storer) QueryImages(ctx context.Context, userID string) ([]exportUsecase.Image, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.query-images")
+	defer span.End()
+	q := `SELECT image_id, list_id, item_id, description, private, date_created
+	FROM images WHERE user_id = $1 AND status = $2
+	ORDER BY date_created, image_id;`
+	var res []StorerImage
+	if err := s.repo.SelectContext(ctx, &res, q, userID, images.Loaded); err != nil {
+		return nil, err
+	}
+	imgs := make([]exportUsecase.Image, 0, len(res))
+	for _, img := range res {
+		imgs = append(imgs, exportUsecase.Image{
+			ID:          img.ID,
+			ListID:      img.ListID,
+			ItemID:      img.ItemID,
+			Description: img.Description,
+			Private:     img.Private,
+			DateCreated: img.DateCreated,
+		})
+	}
+	return imgs, nil
+}
+
+func toStorerExport(e exportUsecase.Export) StorerExport {
+	return StorerExport{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		Status:      string(e.Status),
+		TokenID:     e.TokenID,
+		DateCreated: e.DateCreated,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
+
+func fromStorerExport(e StorerExport) exportUsecase.Export {
+	return exportUsecase.Export{
+		ID:          e.ID,
+		UserID:      e.UserID,
+		Status:      exportUsecase.Status(e.Status),
+		TokenID:     e.TokenID,
+		DateCreated: e.DateCreated,
+		ExpiresAt:   e.ExpiresAt,
+	}
+}
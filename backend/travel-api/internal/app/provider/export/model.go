@@ -0,0 +1,60 @@
+package export
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+type StorerExport struct {
+	ID          string     `db:"export_id"`
+	UserID      string     `db:"user_id"`
+	Status      string     `db:"status"`
+	TokenID     *string    `db:"token_id"`
+	DateCreated time.Time  `db:"date_created"`
+	ExpiresAt   *time.Time `db:"expires_at"`
+}
+
+type StorerProfile struct {
+	ID          string         `db:"user_id"`
+	Name        string         `db:"name"`
+	Email       string         `db:"email"`
+	Roles       pq.StringArray `db:"roles"`
+	IsActive    bool           `db:"is_active"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+type StorerList struct {
+	ID          string    `db:"list_id"`
+	Name        string    `db:"list_name"`
+	Description *string   `db:"description"`
+	Private     bool      `db:"private"`
+	Favorite    bool      `db:"favorite"`
+	Completed   bool      `db:"completed"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+type StorerItem struct {
+	ID          string         `db:"item_id"`
+	ListID      string         `db:"list_id"`
+	Name        string         `db:"item_name"`
+	Description *string        `db:"description"`
+	Address     *string        `db:"address"`
+	Lat         float64        `db:"lat"`
+	Lng         float64        `db:"lng"`
+	ImagesID    pq.StringArray `db:"images_id"`
+	Visited     bool           `db:"is_visited"`
+	DateCreated time.Time      `db:"date_created"`
+	DateUpdated time.Time      `db:"date_updated"`
+}
+
+type StorerImage struct {
+	ID          string    `db:"image_id"`
+	ListID      string    `db:"list_id"`
+	ItemID      *string   `db:"item_id"`
+	Description *string   `db:"description"`
+	Private     bool      `db:"private"`
+	DateCreated time.Time `db:"date_created"`
+}
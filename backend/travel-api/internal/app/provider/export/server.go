@@ -0,0 +1,98 @@
+package export
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/rs/zerolog"
+)
+
+// archivePartSize bounds the memory of an upload, the size of an archive
+// is not known while it is written.
+const archivePartSize = 16 << 20
+
+type Server struct {
+	log         *zerolog.Logger
+	minioClient *minio.Client
+	bucketName  string
+}
+
+// ServerConfig.Expiry is how long the archives are kept, the bucket
+// removes them by itself after that, rounded up to whole days.
+type ServerConfig struct {
+	Log        *zerolog.Logger
+	Host       string
+	AccessKey  string
+	SecretKey  string
+	BucketName string
+	Expiry     time.Duration
+}
+
+// NewServer returns a server keeping the archives in the bucket, the
+// bucket is made if it does not exist.
+func NewServer(ctx context.Context, s ServerConfig) (*Server, error) {
+	minioClient, err := minio.New(s.Host, &minio.Options{
+		Creds:  credentials.NewStaticV4(s.AccessKey, s.SecretKey, ""),
+		Secure: false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ok, err := minioClient.BucketExists(ctx, s.BucketName)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if err := minioClient.MakeBucket(ctx, s.BucketName, minio.MakeBucketOptions{}); err != nil {
+			return nil, err
+		}
+	}
+	days := (s.Expiry + 24*time.Hour - 1) / (24 * time.Hour)
+	lc := lifecycle.NewConfiguration()
+	lc.Rules = []lifecycle.Rule{
+		{
+			ID:     "expire-archives",
+			Status: "Enabled",
+			Expiration: lifecycle.Expiration{
+				Days: lifecycle.ExpirationDays(days),
+			},
+		},
+	}
+	if err := minioClient.SetBucketLifecycle(ctx, s.BucketName, lc); err != nil {
+		return nil, err
+	}
+	return &Server{
+		log:         s.Log,
+		minioClient: minioClient,
+		bucketName:  s.BucketName,
+	}, nil
+}
+
+func (s *Server) SaveArchive(ctx context.Context, exportID string, archive io.Reader) error {
+	ctx, span := web.AddSpan(ctx, "provider.export.server.save-archive")
+	defer span.End()
+	_, err := s.minioClient.PutObject(ctx, s.bucketName, archiveName(exportID), archive, -1, minio.PutObjectOptions{
+		ContentType: "application/zip",
+		PartSize:    archivePartSize,
+	})
+	return err
+}
+
+func (s *Server) ServeArchive(ctx context.Context, exportID string) (io.ReadCloser, error) {
+	ctx, span := web.AddSpan(ctx, "provider.export.server.serve-archive")
+	defer span.End()
+	object, err := s.minioClient.GetObject(ctx, s.bucketName, archiveName(exportID), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return object, nil
+}
+
+func archiveName(exportID string) string {
+	return exportID + ".zip"
+}
@@ -2,8 +2,10 @@ package image
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/image"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/images"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog"
@@ -22,8 +24,14 @@ func (s *Storer) QueryByID(ctx context.Context, imageID string) (image.Image, er
 	ctx, span := web.AddSpan(ctx, "provider.image.storer.query-by-id")
 	defer span.End()
 	img := StorerImage{}
-	q := `SELECT * from images where imageID=$1`
-	if err := s.repo.SelectContext(ctx, &img, q, imageID); err != nil {
+	q := `
+		SELECT image_id, list_id, user_id, item_id, private,
+			COALESCE(description, '') AS description,
+			status, date_created
+		FROM images
+		WHERE image_id = $1;
+	`
+	if err := s.repo.GetContext(ctx, &img, q, imageID); err != nil {
 		return image.Image{}, err
 	}
 
@@ -40,6 +48,68 @@ func (s *Storer) QueryByID(ctx context.Context, imageID string) (image.Image, er
 	return res, nil
 }
 
+func (s *Storer) QueryPublicByID(ctx context.Context, slug string, tokenHash string, imageID string) (image.Image, error) {
+	ctx, span := web.AddSpan(ctx, "provider.image.storer.query-public-by-id")
+	defer span.End()
+	img := StorerImage{}
+	q := `
+		SELECT images.image_id, images.list_id, images.user_id,
+			images.item_id, images.private,
+			COALESCE(images.description, '') AS description,
+			images.status, images.date_created
+		FROM images
+		INNER JOIN lists ON lists.list_id = images.list_id
+		WHERE images.image_id = $1
+		AND images.status = $3
+		AND images.private = FALSE
+		AND (
+			(lists.private = FALSE AND lists.list_id::TEXT = $2)
+			OR EXISTS (
+				SELECT 1 FROM list_shares
+				WHERE list_shares.list_id = lists.list_id
+				AND list_shares.token_hash = $4
+			)
+		);
+	`
+	if err := s.repo.GetContext(ctx, &img, q, imageID, slug, images.Loaded, tokenHash); err != nil {
+		return image.Image{}, err
+	}
+
+	res := image.Image{
+		ID:          img.ID,
+		ListID:      img.ListID,
+		UserID:      img.UserID,
+		ItemID:      img.ItemID,
+		Private:     img.Private,
+		Description: img.Description,
+		Status:      img.Status,
+		DateCreated: img.DateCreated,
+	}
+	return res, nil
+}
+
+// QueryRole returns the role of the user on the list, empty if the user
+// is not a member.
+func (s *Storer) QueryRole(ctx context.Context, userID string, listID string) (image.Role, error) {
+	ctx, span := web.AddSpan(ctx, "provider.image.storer.query-role")
+	defer span.End()
+	q := `
+		SELECT
+			CASE WHEN lists.user_id = $1 THEN 'owner'
+			ELSE list_members.role::TEXT
+			END AS role
+		FROM lists
+		LEFT JOIN list_members ON list_members.list_id = lists.list_id
+			AND list_members.user_id = $1
+		WHERE lists.list_id = $2;
+	`
+	var role sql.NullString
+	if err := s.repo.GetContext(ctx, &role, q, userID, listID); err != nil {
+		return "", err
+	}
+	return image.Role(role.String), nil
+}
+
 func (s *Storer) Create(ctx context.Context, images []image.Image) (err error) {
 	ctx, span := web.AddSpan(ctx, "provider.image.storer.create")
 	defer span.End()
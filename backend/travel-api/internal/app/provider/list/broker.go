@@ -0,0 +1,131 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/f4mk/travel/backend/pkg/mb"
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind
+// before it is dropped.
+const subscriberBuffer = 32
+
+// Broker publishes list events to a fanout exchange shared by all
+// API replicas and dispatches the events it receives back from the
+// exchange to the subscribers of this replica.
+type Broker struct {
+	ex     *mb.Exchange
+	log    *zerolog.Logger
+	mu     sync.Mutex
+	subs   map[string]map[chan list.Event]struct{}
+	closed bool
+}
+
+func NewBroker(l *zerolog.Logger, ex *mb.Exchange) *Broker {
+	return &Broker{
+		ex:   ex,
+		log:  l,
+		subs: make(map[string]map[chan list.Event]struct{}),
+	}
+}
+
+func (b *Broker) Publish(ctx context.Context, e list.Event) error {
+	ctx, span := web.AddSpan(ctx, "provider.list.publish-event")
+	defer span.End()
+	return b.ex.Publish(ctx, BrokerEvent(e))
+}
+
+// Subscribe registers a subscriber to the list events. The returned
+// function unsubscribes and may be called more than once.
+// It reports false once the broker is closed.
+func (b *Broker) Subscribe(listID string) (<-chan list.Event, func(), bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, false
+	}
+	ch := make(chan list.Event, subscriberBuffer)
+	if b.subs[listID] == nil {
+		b.subs[listID] = make(map[chan list.Event]struct{})
+	}
+	b.subs[listID][ch] = struct{}{}
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.drop(listID, ch)
+	}, true
+}
+
+// Run dispatches the events from the exchange until the subscription ends,
+// then closes the broker. The subscription error is sent to errCh, which
+// is closed once subscribed. It always returns a non-nil error.
+func (b *Broker) Run(errCh chan<- error) error {
+	msgs, err := b.ex.Subscribe()
+	if err != nil {
+		b.Close()
+		errCh <- err
+		return err
+	}
+	// init complete
+	close(errCh)
+
+	for m := range msgs {
+		var e BrokerEvent
+		if err := json.Unmarshal(m.Body, &e); err != nil {
+			b.log.Err(err).Msg("events: decode broker event")
+			continue
+		}
+		b.dispatch(list.Event(e))
+	}
+	b.Close()
+	return errors.New("events: exchange subscription closed")
+}
+
+// Close closes all subscribers, new subscribers get a closed channel.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for listID, subs := range b.subs {
+		for ch := range subs {
+			b.drop(listID, ch)
+		}
+	}
+}
+
+// dispatch never blocks on a subscriber, one that cannot keep up is
+// dropped so that it reconnects instead of silently missing events.
+func (b *Broker) dispatch(e list.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subs[e.ListID] {
+		select {
+		case ch <- e:
+		default:
+			b.log.Warn().Msgf("events: dropping slow subscriber of list %s", e.ListID)
+			b.drop(e.ListID, ch)
+		}
+	}
+}
+
+// drop must be called with the lock held.
+func (b *Broker) drop(listID string, ch chan list.Event) {
+	subs, ok := b.subs[listID]
+	if !ok {
+		return
+	}
+	if _, ok := subs[ch]; !ok {
+		return
+	}
+	delete(subs, ch)
+	close(ch)
+	if len(subs) == 0 {
+		delete(b.subs, listID)
+	}
+}
@@ -0,0 +1,110 @@
+package list
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/jmoiron/sqlx"
+)
+
+// visibleTo restricts lists to the ones owned by, shared with or published to user $1.
+const visibleTo = `(
+	lists.user_id = $1
+	OR lists.private = FALSE
+	OR EXISTS (
+		SELECT 1 FROM list_members
+		WHERE list_members.list_id = lists.list_id
+		AND list_members.user_id = $1
+	)
+)`
+
+func (s *Storer) QueryItemsNear(ctx context.Context, nq list.NearQuery) ([]list.GeoItem, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-items-near")
+	defer span.End()
+	q := `
+		SELECT ` + itemColumns + `, points.point_id,
+			ST_Y(points.location) AS lat, ST_X(points.location) AS lng,
+			lists.private,
+			ST_Distance(
+				points.location::geography,
+				ST_SetSRID(ST_MakePoint($2, $3), $4)::geography
+			) AS distance
+		FROM lists
+		INNER JOIN items ON items.list_id = lists.list_id
+		INNER JOIN points ON points.item_id = items.item_id
+		WHERE ST_DWithin(
+			points.location::geography,
+			ST_SetSRID(ST_MakePoint($2, $3), $4)::geography,
+			$5
+		)
+		AND ` + visibleTo + `
+		ORDER BY distance, items.item_id
+		LIMIT $6 OFFSET $7;
+	`
+	rows, err := s.repo.QueryxContext(
+		ctx, q,
+		nq.UserID, nq.Lng, nq.Lat, EPSG, nq.Radius,
+		nq.PageSize, (nq.Page-1)*nq.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return fromRowsToGeoItems(rows)
+}
+
+func (s *Storer) QueryItemsInBBox(ctx context.Context, bq list.BBoxQuery) ([]list.GeoItem, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-items-in-bbox")
+	defer span.End()
+	q := `
+		SELECT ` + itemColumns + `, points.point_id,
+			ST_Y(points.location) AS lat, ST_X(points.location) AS lng,
+			lists.private,
+			ST_Distance(
+				points.location::geography,
+				ST_SetSRID(ST_MakePoint($7, $8), $6)::geography
+			) AS distance
+		FROM lists
+		INNER JOIN items ON items.list_id = lists.list_id
+		INNER JOIN points ON points.item_id = items.item_id
+		WHERE points.location && ST_MakeEnvelope($2, $3, $4, $5, $6)
+		AND ` + visibleTo + `
+		ORDER BY distance, items.item_id
+		LIMIT $9 OFFSET $10;
+	`
+	centerLng := (bq.MinLng + bq.MaxLng) / 2
+	centerLat := (bq.MinLat + bq.MaxLat) / 2
+	rows, err := s.repo.QueryxContext(
+		ctx, q,
+		bq.UserID, bq.MinLng, bq.MinLat, bq.MaxLng, bq.MaxLat, EPSG,
+		centerLng, centerLat,
+		bq.PageSize, (bq.Page-1)*bq.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	return fromRowsToGeoItems(rows)
+}
+
+type rowGeoItem struct {
+	rowItemsByListID
+	Distance float64 `db:"distance"`
+}
+
+func fromRowsToGeoItems(rows *sqlx.Rows) ([]list.GeoItem, error) {
+	is := []list.GeoItem{}
+	for rows.Next() {
+		row := rowGeoItem{}
+		if err := rows.StructScan(&row); err != nil {
+			return nil, err
+		}
+		item := fromRowToItem(row.rowItemsByListID)
+		is = append(is, list.GeoItem{Item: item, Distance: row.Distance})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return is, nil
+}
@@ -0,0 +1,82 @@
+package list
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// CreateItems inserts the items in one transaction. Every item is inserted
+// under its own savepoint, so a failed item is rolled back and reported
+// at its position while the rest of the items are committed.
+func (s *Storer) CreateItems(ctx context.Context, is []list.Item) (errs []error, err error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.create-items")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	tx, err := s.repo.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			if rErr := tx.Rollback(); rErr != nil {
+				s.log.Err(rErr).Str("TraceID", tID).Msg("failed to rollback after error")
+			}
+		}
+	}()
+	qItem := `
+		INSERT INTO items (
+			item_id, list_id, user_id, item_name,
+			description, address,	point,
+			images_id, is_visited,
+			date_created,	date_updated
+		)
+		SELECT 	:item_id, :list_id, :user_id, :item_name,
+						:description, :address, :point,
+						:images_id, :is_visited,
+						:date_created, :date_updated
+		WHERE EXISTS (
+				SELECT 1 FROM lists
+				WHERE lists.list_id = :list_id
+		);
+	`
+	qPoint := `
+		INSERT INTO points (
+			point_id, item_id, location
+		)
+		VALUES (
+			:point_id, :item_id, ST_SetSRID(ST_MakePoint(:lng, :lat), :epsg)
+		);
+	`
+	errs = make([]error, len(is))
+	for n, i := range is {
+		item := populateItem(i)
+		point := StorerPoint{
+			ID:     i.Point.ID,
+			ItemID: i.Point.ItemID,
+			Lat:    i.Point.Lat,
+			Lng:    i.Point.Lng,
+			EPSG:   EPSG,
+		}
+		if _, err = tx.ExecContext(ctx, "SAVEPOINT import_item;"); err != nil {
+			return nil, err
+		}
+		iErr := handleRowsResult(tx.NamedExecContext(ctx, qItem, item))
+		if iErr == nil {
+			iErr = handleRowsResult(tx.NamedExecContext(ctx, qPoint, point))
+		}
+		if iErr != nil {
+			errs[n] = iErr
+			if _, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT import_item;"); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT import_item;"); err != nil {
+			return nil, err
+		}
+	}
+	err = tx.Commit()
+	return errs, err
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/images"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 	"github.com/jmoiron/sqlx"
@@ -24,94 +25,45 @@ func NewStorer(l *zerolog.Logger, r *sqlx.DB) *Storer {
 	return &Storer{repo: r, log: l}
 }
 
-func (s *Storer) QueryListsByUserID(ctx context.Context, uID string) ([]list.List, error) {
-	ctx, span := web.AddSpan(ctx, "provider.list.query-list-by-user-id")
-	defer span.End()
-	res := []StorerList{}
-	q := `SELECT * from lists where user_id=$1`
-	if err := s.repo.SelectContext(ctx, &res, q, uID); err != nil {
-		return []list.List{}, err
-	}
-	ls := []list.List{}
-	for _, l := range res {
-		s := []string(l.ItemsID)
-		ll := list.List{
-			ID:          l.ID,
-			UserID:      l.UserID,
-			Name:        l.Name,
-			Description: l.Description,
-			Private:     l.Private,
-			Favorite:    l.Favorite,
-			Completed:   l.Completed,
-			ItemsID:     s,
-			DateCreated: l.DateCreated,
-			DateUpdated: l.DateUpdated,
-		}
-		ls = append(ls, ll)
-	}
-	return ls, nil
-}
-
-func (s *Storer) QueryListByID(ctx context.Context, uID string, lID string) (list.List, error) {
+func (s *Storer) QueryListByID(ctx context.Context, lID string) (list.List, error) {
 	ctx, span := web.AddSpan(ctx, "provider.list.query-list-by-id")
 	defer span.End()
-	q := `SELECT * FROM lists WHERE list_id = $1 AND user_id = $2;`
+	q := `SELECT ` + listColumns + ` FROM lists WHERE list_id = $1;`
 	res := StorerList{}
-	if err := s.repo.GetContext(ctx, &res, q, lID, uID); err != nil {
+	if err := s.repo.GetContext(ctx, &res, q, lID); err != nil {
 		return list.List{}, err
 	}
-	r := []string(res.ItemsID)
-	l := list.List{
-		ID:          res.ID,
-		UserID:      res.UserID,
-		Name:        res.Name,
-		Description: res.Description,
-		Private:     res.Private,
-		Favorite:    res.Favorite,
-		Completed:   res.Completed,
-		ItemsID:     r,
-		DateCreated: res.DateCreated,
-		DateUpdated: res.DateUpdated,
-	}
-	return l, nil
+	return fromStorerList(res), nil
 }
 
-func (s *Storer) QueryItemsByListID(ctx context.Context, userID string, listID string) ([]list.Item, error) {
+func (s *Storer) QueryItemsByListID(ctx context.Context, listID string) ([]list.Item, error) {
 	ctx, span := web.AddSpan(ctx, "provider.list.query-items-by-list-id")
 	defer span.End()
 	q := `
-		SELECT items.*, points.point_id,
+		SELECT ` + itemColumns + `, points.point_id,
 			ST_Y(points.location) AS lat, ST_X(points.location) AS lng
 		FROM lists
 		INNER JOIN items ON items.list_id = lists.list_id
 		INNER JOIN points ON points.item_id = items.item_id
-		WHERE lists.user_id = $1 AND lists.list_id = $2;
+		WHERE lists.list_id = $1
+		ORDER BY array_position(lists.items, items.item_id) NULLS LAST,
+			items.date_created, items.item_id;
 	`
 
-	rows, err := s.repo.QueryxContext(ctx, q, userID, listID)
+	rows, err := s.repo.QueryxContext(ctx, q, listID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	itemsMap, err := fromRowsToMap(rows)
-	if err != nil {
-		return nil, err
-	}
-
-	items := make([]list.Item, 0, len(itemsMap))
-	for _, item := range itemsMap {
-		items = append(items, *item)
-	}
-
-	return items, nil
+	return fromRowsToItems(rows)
 }
 
 func (s *Storer) QueryItemByID(ctx context.Context, itemID string) (list.Item, error) {
 	ctx, span := web.AddSpan(ctx, "provider.list.query-item-by-id")
 	defer span.End()
 	q := `
-		SELECT items.*, points.point_id,
+		SELECT ` + itemColumns + `, points.point_id,
 			ST_Y(points.location) AS lat, ST_X(points.location) AS lng,
 			lists.private
 		FROM lists
@@ -178,34 +130,36 @@ func (s *Storer) UpdateList(ctx context.Context, l list.List) error {
 			favorite = :favorite,
 			completed = :completed,
 			items = :items,
+			version = version + 1,
 			date_created = :date_created,
 			date_updated = :date_updated
-		WHERE list_id = :list_id;
+		WHERE list_id = :list_id
+		AND version = :version;
 	`
 
 	err := handleRowsResult(s.repo.NamedExecContext(ctx, q, list))
-	if err != nil {
-		return err
+	if errors.Is(err, sql.ErrNoRows) {
+		return versionConflict(ctx, s.repo, qListExists, list.ID)
 	}
-	return nil
-}
-
-func (s *Storer) DeleteListAdmin(ctx context.Context, listID string) error {
-	ctx, span := web.AddSpan(ctx, "provider.list.delete-list-admin")
-	defer span.End()
-	q := `DELETE FROM lists WHERE list_id = $1;`
-	err := handleRowsResult(s.repo.ExecContext(ctx, q, listID))
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *Storer) DeleteList(ctx context.Context, userID string, listID string) error {
+// DeleteList deletes the list, it matches on the version unless it is nil.
+func (s *Storer) DeleteList(ctx context.Context, listID string, version *int) error {
 	ctx, span := web.AddSpan(ctx, "provider.list.delete-list")
 	defer span.End()
-	q := `DELETE FROM lists WHERE user_id = $1 AND list_id = $2;`
-	err := handleRowsResult(s.repo.ExecContext(ctx, q, userID, listID))
+	q := `
+		DELETE FROM lists
+		WHERE list_id = $1
+		AND ($2::integer IS NULL OR version = $2);
+	`
+	err := handleRowsResult(s.repo.ExecContext(ctx, q, listID, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		return versionConflict(ctx, s.repo, qListExists, listID)
+	}
 	if err != nil {
 		return err
 	}
@@ -238,7 +192,7 @@ func (s *Storer) CreateItem(ctx context.Context, i list.Item) (err error) {
 	qImages := `
 		UPDATE images SET
 			item_id = $1,
-			status = $2,
+			status = $2
 		WHERE image_id = $3 
 		AND list_id = $4;
 	`
@@ -256,7 +210,6 @@ func (s *Storer) CreateItem(ctx context.Context, i list.Item) (err error) {
 		WHERE EXISTS (
 				SELECT 1 FROM lists
 				WHERE lists.list_id = :list_id
-				AND lists.user_id = :user_id
 		);
 	`
 	qPoint := `
@@ -273,7 +226,7 @@ func (s *Storer) CreateItem(ctx context.Context, i list.Item) (err error) {
 	}
 
 	for _, imageID := range item.ImagesID {
-		_, err := s.repo.ExecContext(ctx, qImages, item.ID, images.Loaded, imageID, item.ListID)
+		_, err = tx.ExecContext(ctx, qImages, item.ID, images.Loaded, imageID, item.ListID)
 		if err != nil {
 			return err
 		}
@@ -321,9 +274,11 @@ func (s *Storer) UpdateItem(ctx context.Context, i list.Item, toDelete []string)
 			point = :point,
 			images_id = :images_id,
 			is_visited = :is_visited,
+			version = version + 1,
 			date_created = :date_created,
 			date_updated = :date_updated
-		WHERE item_id = :item_id;
+		WHERE item_id = :item_id
+		AND version = :version;
 	`
 	qPoint := `
 		UPDATE points SET
@@ -344,6 +299,9 @@ func (s *Storer) UpdateItem(ctx context.Context, i list.Item, toDelete []string)
 		}
 	}
 	err = handleRowsResult(tx.NamedExecContext(ctx, qItem, item))
+	if errors.Is(err, sql.ErrNoRows) {
+		err = versionConflict(ctx, tx, qItemExists, item.ID)
+	}
 	if err != nil {
 		return err
 	}
@@ -358,11 +316,16 @@ func (s *Storer) UpdateItem(ctx context.Context, i list.Item, toDelete []string)
 	return nil
 }
 
-func (s *Storer) DeleteItemAdmin(ctx context.Context, itemID string) (err error) {
-	ctx, span := web.AddSpan(ctx, "provider.list.delete-item-admin")
+// DeleteItem deletes the item, it matches on the version unless it is nil.
+func (s *Storer) DeleteItem(ctx context.Context, itemID string, version *int) (err error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.delete-item")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	qItem := `DELETE FROM items WHERE item_id = $1;`
+	qItem := `
+		DELETE FROM items
+		WHERE item_id = $1
+		AND ($2::integer IS NULL OR version = $2);
+	`
 	qImages := `UPDATE images SET status = $1 WHERE item_id = $2;`
 	tx, err := s.repo.Beginx()
 	if err != nil {
@@ -375,7 +338,11 @@ func (s *Storer) DeleteItemAdmin(ctx context.Context, itemID string) (err error)
 			}
 		}
 	}()
-	if err := handleRowsResult(tx.ExecContext(ctx, qItem, itemID)); err != nil {
+	err = handleRowsResult(tx.ExecContext(ctx, qItem, itemID, version))
+	if errors.Is(err, sql.ErrNoRows) {
+		err = versionConflict(ctx, tx, qItemExists, itemID)
+	}
+	if err != nil {
 		return err
 	}
 	_, err = tx.ExecContext(ctx, qImages, images.Deleted, itemID)
@@ -389,39 +356,22 @@ func (s *Storer) DeleteItemAdmin(ctx context.Context, itemID string) (err error)
 	return nil
 }
 
-func (s *Storer) DeleteItem(ctx context.Context, userID string, itemID string) error {
-	ctx, span := web.AddSpan(ctx, "provider.list.delete-item")
-	defer span.End()
-	qItem := `
-		DELETE FROM items WHERE item_id = $1 
-		AND EXISTS (
-			SELECT 1
-			FROM lists
-			WHERE lists.user_id = $2
-		);
-	`
-	qImages := `
-		UPDATE images SET status = $1 
-		WHERE item_id = $2 
-		AND user_id = $3;
-		`
-	tx, err := s.repo.Beginx()
-	if err != nil {
-		return err
-	}
-	err = handleRowsResult(tx.ExecContext(ctx, qItem, itemID, userID))
-	if err != nil {
-		return err
-	}
-	_, err = tx.ExecContext(ctx, qImages, images.Deleted, itemID, userID)
-	if err != nil {
+const (
+	qListExists = `SELECT EXISTS (SELECT 1 FROM lists WHERE list_id = $1);`
+	qItemExists = `SELECT EXISTS (SELECT 1 FROM items WHERE item_id = $1);`
+)
+
+// versionConflict tells a stale version from a missing row
+// when a statement matching on the version changed nothing.
+func versionConflict(ctx context.Context, q sqlx.QueryerContext, exists string, id string) error {
+	var ok bool
+	if err := sqlx.GetContext(ctx, q, &ok, exists, id); err != nil {
 		return err
 	}
-	err = tx.Commit()
-	if err != nil {
-		return err
+	if ok {
+		return database.ErrStaleVersion
 	}
-	return nil
+	return sql.ErrNoRows
 }
 
 func handleRowsResult(res sql.Result, err error) error {
@@ -438,6 +388,22 @@ func handleRowsResult(res sql.Result, err error) error {
 	return nil
 }
 
+func fromStorerList(l StorerList) list.List {
+	return list.List{
+		ID:          l.ID,
+		UserID:      l.UserID,
+		Name:        l.Name,
+		Description: l.Description,
+		Private:     l.Private,
+		Favorite:    l.Favorite,
+		Completed:   l.Completed,
+		ItemsID:     []string(l.ItemsID),
+		Version:     l.Version,
+		DateCreated: l.DateCreated,
+		DateUpdated: l.DateUpdated,
+	}
+}
+
 func populateList(l list.List) StorerList {
 	p := pq.StringArray(l.ItemsID)
 	list := StorerList{
@@ -449,6 +415,7 @@ func populateList(l list.List) StorerList {
 		Favorite:    l.Favorite,
 		Completed:   l.Completed,
 		ItemsID:     p,
+		Version:     l.Version,
 		DateCreated: l.DateCreated,
 		DateUpdated: l.DateUpdated,
 	}
@@ -467,6 +434,7 @@ func populateItem(i list.Item) StorerItem {
 		PointID:     i.Point.ID,
 		ImagesID:    im,
 		Visited:     i.Visited,
+		Version:     i.Version,
 		DateCreated: i.DateCreated,
 		DateUpdated: i.DateUpdated,
 	}
@@ -480,41 +448,62 @@ type rowItemsByListID struct {
 }
 
 func fromRowsToMap(rows *sqlx.Rows) (map[string]*list.Item, error) {
-	itemsMap := make(map[string]*list.Item)
+	items, err := fromRowsToItems(rows)
+	if err != nil {
+		return nil, err
+	}
+	itemsMap := make(map[string]*list.Item, len(items))
+	for i := range items {
+		itemsMap[items[i].ID] = &items[i]
+	}
+	return itemsMap, nil
+}
+
+// fromRowsToItems keeps the order of the rows, repeated items are skipped.
+func fromRowsToItems(rows *sqlx.Rows) ([]list.Item, error) {
+	items := []list.Item{}
+	seen := make(map[string]struct{})
 	for rows.Next() {
 		row := rowItemsByListID{}
 		err := rows.StructScan(&row)
 		if err != nil {
 			return nil, err
 		}
-		if _, exists := itemsMap[row.StorerItem.ID]; !exists {
-			p := list.Point{
-				ID:     row.StorerPoint.ID,
-				ItemID: row.StorerItem.ID,
-				Lat:    row.Lat,
-				Lng:    row.Lng,
-			}
-			im := []string(row.ImagesID)
-			itemsMap[row.StorerItem.ID] = &list.Item{
-				ID:          row.StorerItem.ID,
-				ListID:      row.ListID,
-				UserID:      row.UserID,
-				Name:        row.StorerItem.Name,
-				Private:     row.Private,
-				Description: row.Description,
-				Address:     row.Address,
-				Point:       p,
-				ImagesID:    im,
-				Visited:     row.Visited,
-				DateCreated: row.DateCreated,
-				DateUpdated: row.DateUpdated,
-			}
+		if _, exists := seen[row.StorerItem.ID]; exists {
+			continue
 		}
+		seen[row.StorerItem.ID] = struct{}{}
+		items = append(items, fromRowToItem(row))
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	return itemsMap, nil
+	return items, nil
+}
+
+func fromRowToItem(row rowItemsByListID) list.Item {
+	p := list.Point{
+		ID:     row.StorerPoint.ID,
+		ItemID: row.StorerItem.ID,
+		Lat:    row.Lat,
+		Lng:    row.Lng,
+	}
+	im := []string(row.ImagesID)
+	return list.Item{
+		ID:          row.StorerItem.ID,
+		ListID:      row.ListID,
+		UserID:      row.UserID,
+		Name:        row.StorerItem.Name,
+		Private:     row.Private,
+		Description: row.Description,
+		Address:     row.Address,
+		Point:       p,
+		ImagesID:    im,
+		Visited:     row.Visited,
+		Version:     row.Version,
+		DateCreated: row.DateCreated,
+		DateUpdated: row.DateUpdated,
+	}
 }
@@ -0,0 +1,169 @@
+package list
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Storer) QueryRole(ctx context.Context, userID string, listID string) (list.Role, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-role")
+	defer span.End()
+	q := `
+		SELECT
+			CASE WHEN lists.user_id = $1 THEN 'owner'
+			ELSE list_members.role::TEXT
+			END AS role
+		FROM lists
+		LEFT JOIN list_members ON list_members.list_id = lists.list_id
+			AND list_members.user_id = $1
+		WHERE lists.list_id = $2;
+	`
+	var role sql.NullString
+	if err := s.repo.GetContext(ctx, &role, q, userID, listID); err != nil {
+		return "", err
+	}
+	return list.Role(role.String), nil
+}
+
+func (s *Storer) QueryMembers(ctx context.Context, listID string) ([]list.Member, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-members")
+	defer span.End()
+	q := `
+		SELECT list_members.list_id, list_members.user_id,
+			users.name, users.email, list_members.role,
+			list_members.invited_by,
+			list_members.date_created, list_members.date_updated
+		FROM list_members
+		INNER JOIN users ON users.user_id = list_members.user_id
+		WHERE list_members.list_id = $1
+		ORDER BY list_members.date_created;
+	`
+	res := []StorerMember{}
+	if err := s.repo.SelectContext(ctx, &res, q, listID); err != nil {
+		return nil, err
+	}
+	ms := make([]list.Member, 0, len(res))
+	for _, m := range res {
+		ms = append(ms, populateMember(m))
+	}
+	return ms, nil
+}
+
+func (s *Storer) QueryMember(ctx context.Context, listID string, userID string) (list.Member, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-member")
+	defer span.End()
+	q := `
+		SELECT list_members.list_id, list_members.user_id,
+			users.name, users.email, list_members.role,
+			list_members.invited_by,
+			list_members.date_created, list_members.date_updated
+		FROM list_members
+		INNER JOIN users ON users.user_id = list_members.user_id
+		WHERE list_members.list_id = $1 AND list_members.user_id = $2;
+	`
+	res := StorerMember{}
+	if err := s.repo.GetContext(ctx, &res, q, listID, userID); err != nil {
+		return list.Member{}, err
+	}
+	return populateMember(res), nil
+}
+
+func (s *Storer) CreateMember(ctx context.Context, m list.Member) error {
+	ctx, span := web.AddSpan(ctx, "provider.list.create-member")
+	defer span.End()
+	q := `
+		INSERT INTO list_members (
+			list_id, user_id, role, invited_by,
+			date_created, date_updated
+		)
+		VALUES (
+			:list_id, :user_id, :role, :invited_by,
+			:date_created, :date_updated
+		);
+	`
+	return handleRowsResult(s.repo.NamedExecContext(ctx, q, populateStorerMember(m)))
+}
+
+func (s *Storer) UpdateMember(ctx context.Context, m list.Member) error {
+	ctx, span := web.AddSpan(ctx, "provider.list.update-member")
+	defer span.End()
+	q := `
+		UPDATE list_members SET
+			role = :role,
+			date_updated = :date_updated
+		WHERE list_id = :list_id AND user_id = :user_id;
+	`
+	return handleRowsResult(s.repo.NamedExecContext(ctx, q, populateStorerMember(m)))
+}
+
+func (s *Storer) DeleteMember(ctx context.Context, listID string, userID string) error {
+	ctx, span := web.AddSpan(ctx, "provider.list.delete-member")
+	defer span.End()
+	q := `DELETE FROM list_members WHERE list_id = $1 AND user_id = $2;`
+	return handleRowsResult(s.repo.ExecContext(ctx, q, listID, userID))
+}
+
+func (s *Storer) QueryUserByID(ctx context.Context, userID string) (list.User, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-user-by-id")
+	defer span.End()
+	q := `
+		SELECT user_id, name, email, is_active, is_deleted
+		FROM users WHERE user_id = $1;
+	`
+	res := StorerUser{}
+	if err := s.repo.GetContext(ctx, &res, q, userID); err != nil {
+		return list.User{}, err
+	}
+	return populateUser(res), nil
+}
+
+func (s *Storer) QueryUserByEmail(ctx context.Context, email string) (list.User, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-user-by-email")
+	defer span.End()
+	q := `
+		SELECT user_id, name, email, is_active, is_deleted
+		FROM users WHERE email = $1;
+	`
+	res := StorerUser{}
+	if err := s.repo.GetContext(ctx, &res, q, email); err != nil {
+		return list.User{}, err
+	}
+	return populateUser(res), nil
+}
+
+func populateMember(m StorerMember) list.Member {
+	return list.Member{
+		ListID:      m.ListID,
+		UserID:      m.UserID,
+		Name:        m.Name,
+		Email:       m.Email,
+		Role:        list.Role(m.Role),
+		InvitedBy:   m.InvitedBy,
+		DateCreated: m.DateCreated,
+		DateUpdated: m.DateUpdated,
+	}
+}
+
+func populateStorerMember(m list.Member) StorerMember {
+	return StorerMember{
+		ListID:      m.ListID,
+		UserID:      m.UserID,
+		Role:        string(m.Role),
+		InvitedBy:   m.InvitedBy,
+		DateCreated: m.DateCreated,
+		DateUpdated: m.DateUpdated,
+	}
+}
+
+func populateUser(u StorerUser) list.User {
+	return list.User{
+		ID:        u.ID,
+		Name:      u.Name,
+		Email:     u.Email,
+		IsActive:  u.IsActive,
+		IsDeleted: u.IsDeleted,
+	}
+}
@@ -6,6 +6,17 @@ import (
 	"github.com/lib/pq"
 )
 
+// listColumns and itemColumns are selected instead of * to match
+// StorerList and StorerItem, the search vectors are left out.
+const (
+	listColumns = `lists.list_id, lists.user_id, lists.list_name, lists.description,
+		lists.private, lists.favorite, lists.completed, lists.items,
+		lists.version, lists.date_created, lists.date_updated`
+	itemColumns = `items.item_id, items.list_id, items.user_id, items.item_name,
+		items.description, items.address, items.point, items.images_id,
+		items.is_visited, items.version, items.date_created, items.date_updated`
+)
+
 type StorerList struct {
 	ID          string         `db:"list_id"`
 	UserID      string         `db:"user_id"`
@@ -15,6 +26,7 @@ type StorerList struct {
 	Favorite    bool           `db:"favorite"`
 	Completed   bool           `db:"completed"`
 	ItemsID     pq.StringArray `db:"items"`
+	Version     int            `db:"version"`
 	DateCreated time.Time      `db:"date_created"`
 	DateUpdated time.Time      `db:"date_updated"`
 }
@@ -29,6 +41,7 @@ type StorerItem struct {
 	PointID     string         `db:"point"`
 	ImagesID    pq.StringArray `db:"images_id"`
 	Visited     bool           `db:"is_visited"`
+	Version     int            `db:"version"`
 	DateCreated time.Time      `db:"date_created"`
 	DateUpdated time.Time      `db:"date_updated"`
 }
@@ -40,3 +53,42 @@ type StorerPoint struct {
 	Lng    float64 `db:"lng"`
 	EPSG   float64 `db:"epsg"`
 }
+
+type StorerMember struct {
+	ListID      string    `db:"list_id"`
+	UserID      string    `db:"user_id"`
+	Name        string    `db:"name"`
+	Email       string    `db:"email"`
+	Role        string    `db:"role"`
+	InvitedBy   string    `db:"invited_by"`
+	DateCreated time.Time `db:"date_created"`
+	DateUpdated time.Time `db:"date_updated"`
+}
+
+type StorerUser struct {
+	ID        string `db:"user_id"`
+	Name      string `db:"name"`
+	Email     string `db:"email"`
+	IsActive  bool   `db:"is_active"`
+	IsDeleted bool   `db:"is_deleted"`
+}
+
+type StorerShare struct {
+	ID          string    `db:"share_id"`
+	ListID      string    `db:"list_id"`
+	TokenHash   string    `db:"token_hash"`
+	CreatedBy   string    `db:"created_by"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+// BrokerEvent is a list event as it travels through the exchange.
+type BrokerEvent struct {
+	ID      string    `json:"id"`
+	Type    string    `json:"type"`
+	ListID  string    `json:"list_id"`
+	ItemID  *string   `json:"item_id,omitempty"`
+	ImageID *string   `json:"image_id,omitempty"`
+	UserID  string    `json:"user_id"`
+	Version *int      `json:"version,omitempty"`
+	Date    time.Time `json:"date"`
+}
@@ -0,0 +1,176 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// sortKey is an sql expression to sort by and the type its cursor value is cast to.
+type sortKey struct {
+	expr string
+	cast string
+}
+
+var listSortKeys = map[string]sortKey{
+	list.SortDateCreated: {expr: "lists.date_created", cast: "timestamp"},
+	list.SortDateUpdated: {expr: "lists.date_updated", cast: "timestamp"},
+	list.SortName:        {expr: "lists.list_name", cast: "text"},
+}
+
+// items missing from the list order go last
+var itemSortKeys = map[string]sortKey{
+	list.SortDateCreated: {expr: "items.date_created", cast: "timestamp"},
+	list.SortDateUpdated: {expr: "items.date_updated", cast: "timestamp"},
+	list.SortName:        {expr: "items.item_name", cast: "text"},
+	list.SortOrder:       {expr: "COALESCE(array_position(lists.items, items.item_id), 2147483647)", cast: "integer"},
+}
+
+// pageQuery collects conditions and positional arguments of a page query.
+type pageQuery struct {
+	where []string
+	args  []any
+}
+
+func (p *pageQuery) arg(v any) string {
+	p.args = append(p.args, v)
+	return fmt.Sprintf("$%d", len(p.args))
+}
+
+func (p *pageQuery) filter(col string, v *bool) {
+	if v != nil {
+		p.where = append(p.where, fmt.Sprintf("%s = %s", col, p.arg(*v)))
+	}
+}
+
+func (p *pageQuery) conditions() string {
+	return strings.Join(p.where, " AND ")
+}
+
+// keyset adds the condition selecting the rows after the cursor
+// and returns the order by clause matching it.
+func (p *pageQuery) keyset(key sortKey, idCol string, pg list.Page) string {
+	cmp, dir := ">", "ASC"
+	if pg.Desc {
+		cmp, dir = "<", "DESC"
+	}
+	if pg.Cursor != nil {
+		p.where = append(p.where, fmt.Sprintf(
+			"(%s, %s) %s (%s::%s, %s::uuid)",
+			key.expr, idCol, cmp,
+			p.arg(pg.Cursor.Value), key.cast, p.arg(pg.Cursor.ID),
+		))
+	}
+	return fmt.Sprintf("%s %s, %s %s", key.expr, dir, idCol, dir)
+}
+
+type rowListPage struct {
+	StorerList
+	SortValue string `db:"sort_value"`
+}
+
+type rowItemPage struct {
+	rowItemsByListID
+	SortValue string `db:"sort_value"`
+}
+
+func (s *Storer) QueryListsByUserID(ctx context.Context, lq list.ListsQuery) (list.ListsPage, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-lists-by-user-id")
+	defer span.End()
+	key, ok := listSortKeys[lq.Page.Sort]
+	if !ok {
+		return list.ListsPage{}, fmt.Errorf("unknown sort key %q", lq.Page.Sort)
+	}
+	p := pageQuery{}
+	uID := p.arg(lq.UserID)
+	p.where = append(p.where, fmt.Sprintf(`(
+			lists.user_id = %[1]s
+			OR EXISTS (
+				SELECT 1 FROM list_members
+				WHERE list_members.list_id = lists.list_id
+				AND list_members.user_id = %[1]s
+			)
+		)`, uID))
+	p.filter("lists.favorite", lq.Favorite)
+	p.filter("lists.completed", lq.Completed)
+	p.filter("lists.private", lq.Private)
+
+	res := list.ListsPage{Lists: []list.List{}}
+	qCount := fmt.Sprintf(`SELECT COUNT(*) FROM lists WHERE %s;`, p.conditions())
+	if err := s.repo.GetContext(ctx, &res.Total, qCount, p.args...); err != nil {
+		return list.ListsPage{}, err
+	}
+
+	order := p.keyset(key, "lists.list_id", lq.Page)
+	q := fmt.Sprintf(`
+		SELECT %s, (%s)::text AS sort_value
+		FROM lists
+		WHERE %s
+		ORDER BY %s
+		LIMIT %s;
+	`, listColumns, key.expr, p.conditions(), order, p.arg(lq.Page.Limit+1))
+	rows := []rowListPage{}
+	if err := s.repo.SelectContext(ctx, &rows, q, p.args...); err != nil {
+		return list.ListsPage{}, err
+	}
+	if len(rows) > lq.Page.Limit {
+		rows = rows[:lq.Page.Limit]
+		last := rows[len(rows)-1]
+		res.NextCursor = &list.Cursor{Value: last.SortValue, ID: last.ID}
+	}
+	for _, row := range rows {
+		res.Lists = append(res.Lists, fromStorerList(row.StorerList))
+	}
+	return res, nil
+}
+
+func (s *Storer) QueryItemsPage(ctx context.Context, iq list.ItemsQuery) (list.ItemsPage, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-items-page")
+	defer span.End()
+	key, ok := itemSortKeys[iq.Page.Sort]
+	if !ok {
+		return list.ItemsPage{}, fmt.Errorf("unknown sort key %q", iq.Page.Sort)
+	}
+	p := pageQuery{}
+	p.where = append(p.where, fmt.Sprintf("lists.list_id = %s", p.arg(iq.ListID)))
+	p.filter("items.is_visited", iq.Visited)
+
+	res := list.ItemsPage{Items: []list.Item{}}
+	qCount := fmt.Sprintf(`
+		SELECT COUNT(*) FROM lists
+		INNER JOIN items ON items.list_id = lists.list_id
+		WHERE %s;
+	`, p.conditions())
+	if err := s.repo.GetContext(ctx, &res.Total, qCount, p.args...); err != nil {
+		return list.ItemsPage{}, err
+	}
+
+	order := p.keyset(key, "items.item_id", iq.Page)
+	q := fmt.Sprintf(`
+		SELECT %s, points.point_id,
+			ST_Y(points.location) AS lat, ST_X(points.location) AS lng,
+			(%s)::text AS sort_value
+		FROM lists
+		INNER JOIN items ON items.list_id = lists.list_id
+		INNER JOIN points ON points.item_id = items.item_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT %s;
+	`, itemColumns, key.expr, p.conditions(), order, p.arg(iq.Page.Limit+1))
+	rows := []rowItemPage{}
+	if err := s.repo.SelectContext(ctx, &rows, q, p.args...); err != nil {
+		return list.ItemsPage{}, err
+	}
+	if len(rows) > iq.Page.Limit {
+		rows = rows[:iq.Page.Limit]
+		last := rows[len(rows)-1]
+		res.NextCursor = &list.Cursor{Value: last.SortValue, ID: last.StorerItem.ID}
+	}
+	for _, row := range rows {
+		res.Items = append(res.Items, fromRowToItem(row.rowItemsByListID))
+	}
+	return res, nil
+}
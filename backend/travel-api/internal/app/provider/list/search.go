@@ -0,0 +1,72 @@
+package list
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// headlineOptions wraps matched words in the usecase markers.
+var headlineOptions = fmt.Sprintf(
+	"StartSel=%s, StopSel=%s, MaxWords=20, MinWords=5, MaxFragments=2",
+	list.SearchStartSel, list.SearchStopSel,
+)
+
+type rowSearchMatch struct {
+	ListID   string  `db:"list_id"`
+	ListName string  `db:"list_name"`
+	ItemID   *string `db:"item_id"`
+	ItemName *string `db:"item_name"`
+	Snippet  string  `db:"snippet"`
+	Rank     float64 `db:"rank"`
+}
+
+// Search ranks matching lists and items together, snippets are built
+// only for the best matches that fit the limit.
+func (s *Storer) Search(ctx context.Context, sq list.SearchQuery) ([]list.SearchMatch, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.search")
+	defer span.End()
+	q := `
+		SELECT m.list_id, m.list_name, m.item_id, m.item_name, m.rank,
+			ts_headline('simple', m.document, websearch_to_tsquery('simple', $2), $4) AS snippet
+		FROM (
+			SELECT lists.list_id, lists.list_name,
+				NULL::uuid AS item_id, NULL::text AS item_name,
+				concat_ws(' ', lists.list_name, lists.description) AS document,
+				ts_rank(lists.search, websearch_to_tsquery('simple', $2)) AS rank
+			FROM lists
+			WHERE lists.search @@ websearch_to_tsquery('simple', $2)
+			AND ` + visibleTo + `
+			UNION ALL
+			SELECT lists.list_id, lists.list_name,
+				items.item_id, items.item_name,
+				concat_ws(' ', items.item_name, items.description, items.address) AS document,
+				ts_rank(items.search, websearch_to_tsquery('simple', $2)) AS rank
+			FROM lists
+			INNER JOIN items ON items.list_id = lists.list_id
+			WHERE items.search @@ websearch_to_tsquery('simple', $2)
+			AND ` + visibleTo + `
+			ORDER BY rank DESC, list_id, item_id NULLS FIRST
+			LIMIT $3
+		) m
+		ORDER BY m.rank DESC, m.list_id, m.item_id NULLS FIRST;
+	`
+	rows := []rowSearchMatch{}
+	if err := s.repo.SelectContext(ctx, &rows, q, sq.UserID, sq.Query, sq.Limit, headlineOptions); err != nil {
+		return nil, err
+	}
+	ms := make([]list.SearchMatch, 0, len(rows))
+	for _, r := range rows {
+		ms = append(ms, list.SearchMatch{
+			ListID:   r.ListID,
+			ListName: r.ListName,
+			ItemID:   r.ItemID,
+			ItemName: r.ItemName,
+			Snippet:  r.Snippet,
+			Rank:     r.Rank,
+		})
+	}
+	return ms, nil
+}
@@ -0,0 +1,74 @@
+package list
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Storer) QueryPublicListBySlug(ctx context.Context, slug string, tokenHash string) (list.List, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-public-list-by-slug")
+	defer span.End()
+	q := `
+		SELECT ` + listColumns + ` FROM lists
+		WHERE (lists.private = FALSE AND lists.list_id::TEXT = $1)
+		OR lists.list_id = (
+			SELECT list_shares.list_id FROM list_shares
+			WHERE list_shares.token_hash = $2
+		);
+	`
+	res := StorerList{}
+	if err := s.repo.GetContext(ctx, &res, q, slug, tokenHash); err != nil {
+		return list.List{}, err
+	}
+	return fromStorerList(res), nil
+}
+
+func (s *Storer) QueryShares(ctx context.Context, listID string) ([]list.Share, error) {
+	ctx, span := web.AddSpan(ctx, "provider.list.query-shares")
+	defer span.End()
+	q := `SELECT * FROM list_shares WHERE list_id = $1 ORDER BY date_created;`
+	res := []StorerShare{}
+	if err := s.repo.SelectContext(ctx, &res, q, listID); err != nil {
+		return nil, err
+	}
+	ss := make([]list.Share, 0, len(res))
+	for _, sh := range res {
+		ss = append(ss, list.Share{
+			ID:          sh.ID,
+			ListID:      sh.ListID,
+			CreatedBy:   sh.CreatedBy,
+			DateCreated: sh.DateCreated,
+		})
+	}
+	return ss, nil
+}
+
+func (s *Storer) CreateShare(ctx context.Context, sh list.Share) error {
+	ctx, span := web.AddSpan(ctx, "provider.list.create-share")
+	defer span.End()
+	share := StorerShare{
+		ID:          sh.ID,
+		ListID:      sh.ListID,
+		TokenHash:   sh.TokenHash,
+		CreatedBy:   sh.CreatedBy,
+		DateCreated: sh.DateCreated,
+	}
+	q := `
+		INSERT INTO list_shares (
+			share_id, list_id, token_hash, created_by, date_created
+		)
+		VALUES (
+			:share_id, :list_id, :token_hash, :created_by, :date_created
+		);
+	`
+	return handleRowsResult(s.repo.NamedExecContext(ctx, q, share))
+}
+
+func (s *Storer) DeleteShare(ctx context.Context, listID string, shareID string) error {
+	ctx, span := web.AddSpan(ctx, "provider.list.delete-share")
+	defer span.End()
+	q := `DELETE FROM list_shares WHERE list_id = $1 AND share_id = $2;`
+	return handleRowsResult(s.repo.ExecContext(ctx, q, listID, shareID))
+}
@@ -140,3 +140,135 @@ func (s *Sender) SendRegisterEmail(ctx context.Context, l mailUsecase.Letter) er
 	s.log.Info().Str("TraceID", tID).Msg("email sent successfully")
 	return nil
 }
+
+func (s *Sender) SendInviteEmail(ctx context.Context, l mailUsecase.Letter) error {
+	tID := web.GetTraceID(ctx)
+	ctx, span := web.AddSpan(ctx, "provider.mail.send-invite-email", attribute.String("TraceID", tID))
+	defer span.End()
+	u := &url.URL{
+		Scheme: "https",
+		Host:   s.dName,
+		Path:   "/lists/" + url.PathEscape(l.ListID),
+	}
+	return s.send(ctx, l, u.String())
+}
+
+func (s *Sender) SendUnlockEmail(ctx context.Context, l mailUsecase.Letter) error {
+	tID := web.GetTraceID(ctx)
+	ctx, span := web.AddSpan(ctx, "provider.mail.send-unlock-email", attribute.String("TraceID", tID))
+	defer span.End()
+	q := make(url.Values)
+	q.Set("token", l.Token)
+	u := &url.URL{
+		Scheme:   "https",
+		Host:     s.dName,
+		Path:     "/login/unlock",
+		RawQuery: q.Encode(),
+	}
+	return s.send(ctx, l, u.String())
+}
+
+func (s *Sender) SendEmailConfirmEmail(ctx context.Context, l mailUsecase.Letter) error {
+	tID := web.GetTraceID(ctx)
+	ctx, span := web.AddSpan(ctx, "provider.mail.send-email-confirm-email", attribute.String("TraceID", tID))
+	defer span.End()
+	q := make(url.Values)
+	q.Set("token", l.Token)
+	u := &url.URL{
+		Scheme:   "https",
+		Host:     s.dName,
+		Path:     "/user/email/confirm",
+		RawQuery: q.Encode(),
+	}
+	return s.send(ctx, l, u.String())
+}
+
+func (s *Sender) SendEmailNoticeEmail(ctx context.Context, l mailUsecase.Letter) error {
+	tID := web.GetTraceID(ctx)
+	ctx, span := web.AddSpan(ctx, "provider.mail.send-email-notice-email", attribute.String("TraceID", tID))
+	defer span.End()
+	u := &url.URL{
+		Scheme: "https",
+		Host:   s.dName,
+		Path:   "/password/reset",
+	}
+	return s.send(ctx, l, u.String())
+}
+
+func (s *Sender) SendMagicLinkEmail(ctx context.Context, l mailUsecase.Letter) error {
+	tID := web.GetTraceID(ctx)
+	ctx, span := web.AddSpan(ctx, "provider.mail.send-magic-link-email", attribute.String("TraceID", tID))
+	defer span.End()
+	q := make(url.Values)
+	q.Set("token", l.Token)
+	u := &url.URL{
+		Scheme:   "https",
+		Host:     s.dName,
+		Path:     "/login/magic",
+		RawQuery: q.Encode(),
+	}
+	return s.send(ctx, l, u.String())
+}
+
+func (s *Sender) SendDataExportEmail(ctx context.Context, l mailUsecase.Letter) error {
+	tID := web.GetTraceID(ctx)
+	ctx, span := web.AddSpan(ctx, "provider.mail.send-data-export-email", attribute.String("TraceID", tID))
+	defer span.End()
+	q := make(url.Values)
+	q.Set("token", l.Token)
+	// the archive is downloaded from the api directly
+	u := &url.URL{
+		Scheme:   "https",
+		Host:     s.dName,
+		Path:     "/api/users/export/download",
+		RawQuery: q.Encode(),
+	}
+	return s.send(ctx, l, u.String())
+}
+
+func (s *Sender) send(ctx context.Context, l mailUsecase.Letter, link string) error {
+	tID := web.GetTraceID(ctx)
+	tmpl, err := template.ParseFS(letterTmpl, "letter_template.html")
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg("error parsing letter template from file")
+		return err
+	}
+	letter := Letter{
+		To:      l.To,
+		Name:    l.Name,
+		Subject: l.Subject,
+		Header:  l.Header,
+		Body:    l.Body,
+		Link:    link,
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, letter); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg("error executing template")
+		return err
+	}
+	messagesInfo := []mailjet.InfoMessagesV31{
+		{
+			From: &mailjet.RecipientV31{
+				Email: "noreply@traillyst.com",
+				Name:  "Traillyst",
+			},
+			To: &mailjet.RecipientsV31{
+				mailjet.RecipientV31{
+					Email: l.To,
+					Name:  l.Name,
+				},
+			},
+			Subject:  l.Subject,
+			TextPart: l.Header + "\n" + l.Body + "\n" + link,
+			HTMLPart: buf.String(),
+		},
+	}
+
+	messages := mailjet.MessagesV31{Info: messagesInfo}
+	if _, err := s.mail.SendMailV31(&messages); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg("error sending email")
+		return err
+	}
+	s.log.Info().Str("TraceID", tID).Msg("email sent successfully")
+	return nil
+}
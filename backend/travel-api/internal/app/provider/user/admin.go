@@ -0,0 +1,114 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/lib/pq"
+)
+
+func (s *Storer) QueryAll(ctx context.Context, uq userUsecase.UsersQuery) (userUsecase.UsersPage, error) {
+	ctx, span := web.AddSpan(ctx, "provider.user.query-all")
+	defer span.End()
+	where := []string{"TRUE"}
+	args := []any{}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if uq.Search != "" {
+		// escape the LIKE wildcards, the search matches literally
+		pattern := "%" + strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(uq.Search) + "%"
+		p := arg(pattern)
+		where = append(where, fmt.Sprintf("(name ILIKE %[1]s OR email ILIKE %[1]s)", p))
+	}
+	if uq.IsActive != nil {
+		where = append(where, fmt.Sprintf("is_active = %s", arg(*uq.IsActive)))
+	}
+	if uq.IsDeleted != nil {
+		where = append(where, fmt.Sprintf("is_deleted = %s", arg(*uq.IsDeleted)))
+	}
+	conds := strings.Join(where, " AND ")
+
+	res := userUsecase.UsersPage{Users: []userUsecase.User{}}
+	qCount := fmt.Sprintf(`SELECT COUNT(*) FROM users WHERE %s;`, conds)
+	if err := s.repo.GetContext(ctx, &res.Total, qCount, args...); err != nil {
+		return userUsecase.UsersPage{}, err
+	}
+	q := fmt.Sprintf(`SELECT * FROM users WHERE %s ORDER BY date_created DESC, user_id LIMIT %s OFFSET %s;`,
+		conds, arg(uq.PageSize), arg((uq.Page-1)*uq.PageSize))
+	users := []StorerUser{}
+	if err := s.repo.SelectContext(ctx, &users, q, args...); err != nil {
+		return userUsecase.UsersPage{}, err
+	}
+	for _, user := range users {
+		res.Users = append(res.Users, fromStorerUser(user))
+	}
+	return res, nil
+}
+
+// UpdateAccount stores the account state an admin manages,
+// it returns sql.ErrNoRows if there is no such user.
+func (s *Storer) UpdateAccount(ctx context.Context, u userUsecase.User) error {
+	ctx, span := web.AddSpan(ctx, "provider.user.update-account")
+	defer span.End()
+	user := StorerUser{
+		ID:           u.ID,
+		IsActive:     u.IsActive,
+		IsDeleted:    u.IsDeleted,
+		TokenVersion: u.TokenVersion,
+		Roles:        u.Roles,
+		DateUpdated:  u.DateUpdated,
+	}
+	q := `UPDATE users SET 
+					is_active = :is_active, 
+					is_deleted = :is_deleted, 
+					roles = :roles, 
+					token_version = :token_version,
+					date_updated = :date_updated
+				WHERE user_id = :user_id;`
+	res, err := s.repo.NamedExecContext(ctx, q, user)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RolesExist reports whether all the roles are defined.
+func (s *Storer) RolesExist(ctx context.Context, roles []string) (bool, error) {
+	ctx, span := web.AddSpan(ctx, "provider.user.roles-exist")
+	defer span.End()
+	var missing int
+	q := `SELECT COUNT(*) FROM unnest($1::text[]) AS r(role)
+	WHERE NOT EXISTS (SELECT 1 FROM roles WHERE roles.role = r.role);`
+	if err := s.repo.GetContext(ctx, &missing, q, pq.StringArray(roles)); err != nil {
+		return false, err
+	}
+	return missing == 0, nil
+}
+
+func fromStorerUser(user StorerUser) userUsecase.User {
+	return userUsecase.User{
+		ID:           user.ID,
+		Name:         user.Name,
+		Email:        user.Email,
+		IsActive:     user.IsActive,
+		IsDeleted:    user.IsDeleted,
+		TokenVersion: user.TokenVersion,
+		Roles:        user.Roles,
+		PasswordHash: user.PasswordHash,
+		DateCreated:  user.DateCreated,
+		DateUpdated:  user.DateUpdated,
+	}
+}
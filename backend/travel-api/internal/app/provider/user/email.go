@@ -0,0 +1,82 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+
+	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// UpdateEmail stores the confirmed email, it returns sql.ErrNoRows
+// if there is no such user.
+func (s *Storer) UpdateEmail(ctx context.Context, u userUsecase.User) error {
+	ctx, span := web.AddSpan(ctx, "provider.user.update-email")
+	defer span.End()
+	user := StorerUser{
+		ID:           u.ID,
+		Email:        u.Email,
+		TokenVersion: u.TokenVersion,
+		DateUpdated:  u.DateUpdated,
+	}
+	q := `UPDATE users SET 
+					email = :email, 
+					token_version = :token_version,
+					date_updated = :date_updated
+				WHERE user_id = :user_id;`
+	res, err := s.repo.NamedExecContext(ctx, q, user)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (s *Storer) QueryEmailChangeToken(ctx context.Context, tokenID string) (userUsecase.VerifyToken, error) {
+	ctx, span := web.AddSpan(ctx, "provider.user.query-email-change-token")
+	defer span.End()
+	token := StorerToken{}
+	q := `SELECT * from email_change_tokens WHERE token_id = $1;`
+	if err := s.repo.GetContext(ctx, &token, q, tokenID); err != nil {
+		return userUsecase.VerifyToken{}, err
+	}
+	res := userUsecase.VerifyToken{
+		TokenID:   token.TokenID,
+		UserID:    token.UserID,
+		Email:     token.Email,
+		ExpiresAt: token.ExpiresAt,
+		IssuedAt:  token.IssuedAt,
+	}
+	return res, nil
+}
+
+func (s *Storer) StoreEmailChangeToken(ctx context.Context, vt userUsecase.VerifyToken) error {
+	ctx, span := web.AddSpan(ctx, "provider.user.store-email-change-token")
+	defer span.End()
+	t := StorerToken{
+		TokenID:   vt.TokenID,
+		UserID:    vt.UserID,
+		Email:     vt.Email,
+		ExpiresAt: vt.ExpiresAt,
+		IssuedAt:  vt.IssuedAt,
+	}
+	q := `INSERT INTO email_change_tokens (token_id, user_id, email, expires_at, issued_at)
+	VALUES (:token_id, :user_id, :email, :expires_at, :issued_at);
+	`
+	_, err := s.repo.NamedExecContext(ctx, q, t)
+	return err
+}
+
+func (s *Storer) DeleteEmailChangeTokensByUserID(ctx context.Context, uID string) error {
+	ctx, span := web.AddSpan(ctx, "provider.user.delete-email-change-tokens-by-user-id")
+	defer span.End()
+	q := `DELETE from email_change_tokens WHERE user_id = $1;`
+	_, err := s.repo.ExecContext(ctx, q, uID)
+	return err
+}
@@ -26,3 +26,15 @@ type StorerToken struct {
 	ExpiresAt time.Time `db:"expires_at"`
 	IssuedAt  time.Time `db:"issued_at"`
 }
+
+type StorerAccessToken struct {
+	TokenID      string         `db:"token_id"`
+	UserID       string         `db:"user_id"`
+	Name         string         `db:"name"`
+	TokenHash    string         `db:"token_hash"`
+	Scopes       pq.StringArray `db:"scopes"`
+	ExpiresAt    *time.Time     `db:"expires_at"`
+	LastUsedAt   *time.Time     `db:"last_used_at"`
+	DateCreated  time.Time      `db:"date_created"`
+	TokenVersion int32          `db:"token_version"`
+}
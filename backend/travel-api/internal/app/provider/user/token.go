@@ -0,0 +1,77 @@
+package user
+
+import (
+	"context"
+	"database/sql"
+
+	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// CreateAccessToken stores the token with the current token version
+// of the user, the token is rejected once the version changes.
+func (s *Storer) CreateAccessToken(ctx context.Context, at userUsecase.AccessToken) error {
+	ctx, span := web.AddSpan(ctx, "provider.user.create-access-token")
+	defer span.End()
+	t := StorerAccessToken{
+		TokenID:     at.ID,
+		UserID:      at.UserID,
+		Name:        at.Name,
+		TokenHash:   at.TokenHash,
+		Scopes:      at.Scopes,
+		ExpiresAt:   at.ExpiresAt,
+		LastUsedAt:  at.LastUsedAt,
+		DateCreated: at.DateCreated,
+	}
+	q := `INSERT INTO personal_access_tokens
+	(token_id, user_id, name, token_hash, scopes, expires_at, last_used_at, date_created, token_version)
+	SELECT :token_id, :user_id, :name, :token_hash, :scopes, :expires_at, :last_used_at, :date_created,
+		users.token_version
+	FROM users WHERE users.user_id = :user_id;`
+	_, err := s.repo.NamedExecContext(ctx, q, t)
+	return err
+}
+
+func (s *Storer) QueryAccessTokens(ctx context.Context, userID string) ([]userUsecase.AccessToken, error) {
+	ctx, span := web.AddSpan(ctx, "provider.user.query-access-tokens")
+	defer span.End()
+	ts := []StorerAccessToken{}
+	q := `SELECT * FROM personal_access_tokens WHERE user_id = $1 ORDER BY date_created DESC;`
+	if err := s.repo.SelectContext(ctx, &ts, q, userID); err != nil {
+		return nil, err
+	}
+	res := make([]userUsecase.AccessToken, 0, len(ts))
+	for _, t := range ts {
+		res = append(res, userUsecase.AccessToken{
+			ID:          t.TokenID,
+			UserID:      t.UserID,
+			Name:        t.Name,
+			TokenHash:   t.TokenHash,
+			Scopes:      t.Scopes,
+			ExpiresAt:   t.ExpiresAt,
+			LastUsedAt:  t.LastUsedAt,
+			DateCreated: t.DateCreated,
+		})
+	}
+	return res, nil
+}
+
+// DeleteAccessToken deletes the token of the user,
+// it returns sql.ErrNoRows if the user has no such token.
+func (s *Storer) DeleteAccessToken(ctx context.Context, userID string, tokenID string) error {
+	ctx, span := web.AddSpan(ctx, "provider.user.delete-access-token")
+	defer span.End()
+	q := `DELETE FROM personal_access_tokens WHERE token_id = $1 AND user_id = $2;`
+	res, err := s.repo.ExecContext(ctx, q, tokenID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -18,32 +18,6 @@ func NewStorer(l *zerolog.Logger, r *sqlx.DB) *Storer {
 	return &Storer{repo: r, log: l}
 }
 
-func (s *Storer) QueryAll(ctx context.Context) ([]userUsecase.User, error) {
-	ctx, span := web.AddSpan(ctx, "provider.user.query-all")
-	defer span.End()
-	users := []StorerUser{}
-	q := `SELECT * from users;`
-	if err := s.repo.SelectContext(ctx, &users, q); err != nil {
-		return []userUsecase.User{}, err
-	}
-	var res []userUsecase.User
-	for _, user := range res {
-		res = append(res, userUsecase.User{
-			ID:           user.ID,
-			Name:         user.Name,
-			Email:        user.Email,
-			IsActive:     user.IsActive,
-			IsDeleted:    user.IsDeleted,
-			TokenVersion: user.TokenVersion,
-			Roles:        user.Roles,
-			PasswordHash: user.PasswordHash,
-			DateCreated:  user.DateCreated,
-			DateUpdated:  user.DateUpdated,
-		})
-	}
-	return res, nil
-}
-
 func (s *Storer) Create(ctx context.Context, u userUsecase.User) error {
 	ctx, span := web.AddSpan(ctx, "provider.user.create")
 	defer span.End()
@@ -0,0 +1,8 @@
+package audit
+
+import "errors"
+
+var (
+	ErrGetEventsValidate = errors.New("error get audit events parsing query")
+	ErrGetEventsBusiness = errors.New("error get audit events from business layer")
+)
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	auditUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+type Service struct {
+	core *auditUsecase.Core
+	log  *zerolog.Logger
+}
+
+func NewService(l *zerolog.Logger, c *auditUsecase.Core) *Service {
+	return &Service{
+		core: c,
+		log:  l,
+	}
+}
+
+// GetEvents returns a page of the audit events matching the filters.
+func (s *Service) GetEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.audit.get-events")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetAdminAuditParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetEventsValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	q := auditUsecase.EventsQuery{
+		ActorID:    p.ActorID,
+		Action:     p.Action,
+		TargetType: p.TargetType,
+		TargetID:   p.TargetID,
+		TraceID:    p.TraceID,
+		From:       p.From,
+		To:         p.To,
+	}
+	if p.Page != nil {
+		q.Page = *p.Page
+	}
+	if p.PageSize != nil {
+		q.PageSize = *p.PageSize
+	}
+	res, err := s.core.QueryEvents(ctx, q)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetEventsBusiness.Error())
+		return fmt.Errorf(
+			"cannot get audit events: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ep := AuditEventsPageResponse{
+		Events: make([]AuditEventResponse, 0, len(res.Events)),
+		Total:  res.Total,
+	}
+	for _, e := range res.Events {
+		er := AuditEventResponse{
+			ID:          e.ID,
+			ActorID:     e.ActorID,
+			Action:      e.Action,
+			TargetType:  e.TargetType,
+			TargetID:    e.TargetID,
+			TraceID:     e.TraceID,
+			IP:          e.IP,
+			DateCreated: e.DateCreated,
+		}
+		if e.Before != nil {
+			er.Before = &e.Before
+		}
+		if e.After != nil {
+			er.After = &e.After
+		}
+		ep.Events = append(ep.Events, er)
+	}
+	return web.Respond(ctx, w, ep, http.StatusOK)
+}
@@ -0,0 +1,17 @@
+package audit
+
+import (
+	"errors"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (p GetAdminAuditParams) Validate() error {
+	if err := web.Check(p); err != nil {
+		return err
+	}
+	if p.From != nil && p.To != nil && !p.From.Before(*p.To) {
+		return web.NewFieldsError("to", errors.New("must be after from"))
+	}
+	return nil
+}
@@ -8,12 +8,46 @@ var (
 	ErrLoginGenAuthToken      = errors.New("error login generating auth token")
 	ErrLoginGenRefreshToken   = errors.New("error login generating refresh token")
 	ErrLoginStoreTokenVersion = errors.New("error login storing token version")
+	ErrLoginCreateSession     = errors.New("error login creating session")
+	ErrLoginGenChallengeToken = errors.New("error login generating challenge token")
+	ErrLoginCheckLockout      = errors.New("error login checking failed attempts")
+	ErrLoginFailLockout       = errors.New("error login counting failed attempt")
+	ErrLoginResetLockout      = errors.New("error login resetting failed attempts")
+	ErrLoginUnlockRequest     = errors.New("error login unlock request from business layer")
+	ErrLoginUnlockSendMessage = errors.New("error login unlock send message")
+
+	ErrUnlockDecode  = errors.New("error unlock parsing user input")
+	ErrUnlockLockout = errors.New("error unlock lifting lock")
+
+	ErrGetRolesBusiness       = errors.New("error get roles from business layer")
+	ErrUpdateRoleValidateName = errors.New("error update role validate name")
+	ErrUpdateRoleDecode       = errors.New("error update role parsing user input")
+	ErrUpdateRoleBusiness     = errors.New("error update role from business layer")
+	ErrUpdateRoleReloadPolicy = errors.New("error update role reloading permissions")
+	ErrDeleteRoleValidateName = errors.New("error delete role validate name")
+	ErrDeleteRoleBusiness     = errors.New("error delete role from business layer")
+	ErrDeleteRoleReloadPolicy = errors.New("error delete role reloading permissions")
+
+	ErrLoginTwoFactorDecode          = errors.New("error login two-factor parsing user input")
+	ErrLoginTwoFactorValidateToken   = errors.New("error login two-factor validating challenge token")
+	ErrLoginTwoFactorBusiness        = errors.New("error login two-factor from business layer")
+	ErrLoginTwoFactorRevokeChallenge = errors.New("error login two-factor revoking challenge token")
+
+	ErrSetupTOTPDecode     = errors.New("error setup totp parsing user input")
+	ErrSetupTOTPBusiness   = errors.New("error setup totp from business layer")
+	ErrVerifyTOTPDecode    = errors.New("error verify totp parsing user input")
+	ErrVerifyTOTPBusiness  = errors.New("error verify totp from business layer")
+	ErrDisableTOTPDecode   = errors.New("error disable totp parsing user input")
+	ErrDisableTOTPBusiness = errors.New("error disable totp from business layer")
 
 	ErrLogoutDecode               = errors.New("error logout parsing user input")
 	ErrLogoutReadRefreshToken     = errors.New("error logout reading refresh token")
 	ErrLogoutValidateRefreshToken = errors.New("error logout validating refresh token")
 	ErrLogoutBusiness             = errors.New("error logout from business layer")
 	ErrLogoutRevokeToken          = errors.New("error logout revoking token")
+	ErrLogoutRevokeFamily         = errors.New("error logout revoking token family")
+	ErrForceLogoutValidateUUID    = errors.New("error force logout validate user uuid")
+	ErrForceLogoutBusiness        = errors.New("error force logout from business layer")
 
 	ErrChangePassDecode               = errors.New("error change password parsing user input")
 	ErrChangePassReadRefreshToken     = errors.New("error change password reading refresh token")
@@ -27,7 +61,22 @@ var (
 	ErrValidateResetPasswordDecode = errors.New("error validate reset password parsing user input")
 	ErrValidateResetPassword       = errors.New("error validate reset password validating reset token")
 
+	ErrMagicLinkDecode         = errors.New("error magic link parsing user input")
+	ErrMagicLinkBusiness       = errors.New("error magic link from business layer")
+	ErrMagicLinkSendMessage    = errors.New("error magic link send message")
+	ErrMagicLinkSubmitDecode   = errors.New("error magic link submit parsing user input")
+	ErrMagicLinkSubmitBusiness = errors.New("error magic link submit from business layer")
+
 	ErrRefreshValidateRefreshToken = errors.New("error refresh validating refresh token")
 	ErrRefreshReadRefreshToken     = errors.New("error refresh reading refresh token")
 	ErrRefreshGenAuthToken         = errors.New("error refresh renerating refresh token")
+	ErrRefreshGenRefreshToken      = errors.New("error refresh generating refresh token")
+	ErrRefreshTokenUse             = errors.New("error refresh token is not a refresh token")
+	ErrRefreshRotateToken          = errors.New("error refresh rotating refresh token")
+	ErrRefreshRevokeFamily         = errors.New("error refresh revoking token family")
+
+	ErrGetSessionsBusiness      = errors.New("error query sessions from business layer")
+	ErrTerminateSessionBusiness = errors.New("error terminate session from business layer")
+	ErrTerminateSessionRevoke   = errors.New("error terminate session revoking token family")
+	ErrSessionValidateUUID      = errors.New("error session validate session uuid")
 )
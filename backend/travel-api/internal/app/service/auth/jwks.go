@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// JWKS serves the public keys for other services to verify our tokens with.
+// A new key is published before it signs, so a short cache is enough.
+func (s *Service) JWKS(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.jwks")
+	defer span.End()
+	keys := s.auth.JWKS()
+	res := JWKSResponse{Keys: make([]JWK, 0, len(keys))}
+	for _, k := range keys {
+		res.Keys = append(res.Keys, JWK{
+			Kty: k.Kty,
+			Use: k.Use,
+			Alg: k.Alg,
+			Kid: k.Kid,
+			N:   optionalString(k.N),
+			E:   optionalString(k.E),
+			Crv: optionalString(k.Crv),
+			X:   optionalString(k.X),
+			Y:   optionalString(k.Y),
+		})
+	}
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	return web.Respond(ctx, w, res, http.StatusOK)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/messages"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// MagicLink emails a single-use login link. The response is the same
+// whether a link was sent or not, to not spoil if the user exists.
+func (s *Service) MagicLink(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.magic-link")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ml := MagicLink{}
+	if err := web.Decode(r, &ml); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMagicLinkDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	res, err := s.core.MagicLinkRequest(ctx, strings.ToLower(ml.Email))
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMagicLinkBusiness.Error())
+		if errors.Is(err, web.ErrNotFound) {
+			return web.Respond(ctx, w, struct{}{}, http.StatusAccepted)
+		}
+		return fmt.Errorf(
+			"cannot send magic link: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	m := messages.Message{
+		ID:    tID,
+		Email: res.Email,
+		Name:  res.Name,
+		Token: res.LoginToken,
+		Type:  messages.MagicLink,
+	}
+	if err := s.mq.Publish(ctx, m); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMagicLinkSendMessage.Error())
+		return fmt.Errorf("cannot send message: %w", err)
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusAccepted)
+}
+
+// MagicLinkSubmit exchanges the token of a login link for the tokens
+// of a new session, just like Login does for the password.
+func (s *Service) MagicLinkSubmit(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.magic-link-submit")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	sm := SubmitMagicLink{}
+	if err := web.Decode(r, &sm); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMagicLinkSubmitDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	res, err := s.core.MagicLinkLogin(ctx, sm.Token)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMagicLinkSubmitBusiness.Error())
+		return web.GetResponseErrorFromBusiness(err)
+	}
+	return s.completeLogin(ctx, w, r, res, derefString(sm.DeviceName))
+}
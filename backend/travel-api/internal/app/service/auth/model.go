@@ -19,6 +19,15 @@ type ChangePassword struct {
 	PasswordOld string `json:"password_old" validate:"required"`
 }
 
+// DisableTOTP defines model for DisableTOTP.
+type DisableTOTP struct {
+	// Code code of the authenticator app or an unused recovery code
+	Code string `json:"code" validate:"required,max=32"`
+
+	// Password user password
+	Password string `json:"password" validate:"required"`
+}
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
 	// Error error message
@@ -26,8 +35,64 @@ type ErrorResponse struct {
 	Fields *map[string]string `json:"fields,omitempty"`
 }
 
+// JWK defines model for JWK.
+type JWK struct {
+	// Alg signing algorithm
+	Alg string `json:"alg"`
+
+	// Crv curve of the EC or OKP key
+	Crv *string `json:"crv,omitempty"`
+
+	// E base64url encoded exponent of the RSA key
+	E *string `json:"e,omitempty"`
+
+	// Kid key id, the kid header of the tokens signed with the key
+	Kid string `json:"kid"`
+
+	// Kty key type, RSA, EC or OKP
+	Kty string `json:"kty"`
+
+	// N base64url encoded modulus of the RSA key
+	N *string `json:"n,omitempty"`
+
+	// Use public key use
+	Use string `json:"use"`
+
+	// X base64url encoded x coordinate of the EC key or the OKP public key
+	X *string `json:"x,omitempty"`
+
+	// Y base64url encoded y coordinate of the EC key
+	Y *string `json:"y,omitempty"`
+}
+
+// JWKSResponse defines model for JWKSResponse.
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
+
+// LoginChallengeResponse defines model for LoginChallengeResponse.
+type LoginChallengeResponse struct {
+	// ChallengeToken short-lived token of the password step
+	ChallengeToken string `json:"challenge_token"`
+}
+
+// LoginTwoFactor defines model for LoginTwoFactor.
+type LoginTwoFactor struct {
+	// ChallengeToken challenge token returned by /auth/login
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+
+	// Code code of the authenticator app or an unused recovery code
+	Code string `json:"code" validate:"required,max=32"`
+
+	// DeviceName name of the device shown in the sessions, the user agent by default
+	DeviceName *string `json:"device_name,omitempty" validate:"omitempty,max=100"`
+}
+
 // LoginUser defines model for LoginUser.
 type LoginUser struct {
+	// DeviceName name of the device shown in the sessions, the user agent by default
+	DeviceName *string `json:"device_name,omitempty" validate:"omitempty,max=100"`
+
 	// Email user email
 	Email string `json:"email" validate:"required,email"`
 
@@ -35,6 +100,12 @@ type LoginUser struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// MagicLink defines model for MagicLink.
+type MagicLink struct {
+	// Email user email
+	Email string `json:"email" validate:"required,email"`
+}
+
 // NewPassword defines model for NewPassword.
 type NewPassword struct {
 	// Password user new password
@@ -50,6 +121,12 @@ type OldPassword struct {
 	PasswordOld string `json:"password_old" validate:"required"`
 }
 
+// RecoveryCodesResponse defines model for RecoveryCodesResponse.
+type RecoveryCodesResponse struct {
+	// RecoveryCodes one-time codes to log in without the authenticator app
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 // ResetPassword defines model for ResetPassword.
 type ResetPassword struct {
 	// Email user email
@@ -62,6 +139,57 @@ type ResetToken struct {
 	Token string `json:"token" validate:"required"`
 }
 
+// RoleResponse defines model for RoleResponse.
+type RoleResponse struct {
+	// DateCreated creation date
+	DateCreated time.Time `json:"date_created"`
+
+	// DateUpdated date of the last update
+	DateUpdated time.Time `json:"date_updated"`
+
+	// Description what the role is for
+	Description string `json:"description"`
+
+	// Name name of the role
+	Name string `json:"name"`
+
+	// Permissions permissions the role grants
+	Permissions []string `json:"permissions"`
+}
+
+// SessionResponse defines model for SessionResponse.
+type SessionResponse struct {
+	// Current the session of the request
+	Current bool `json:"current"`
+
+	// DateCreated login date
+	DateCreated time.Time `json:"date_created"`
+
+	// DeviceName device name given at login
+	DeviceName string `json:"device_name"`
+
+	// Id session id
+	ID string `json:"id"`
+
+	// Ip client ip of the last login or refresh
+	IP string `json:"ip"`
+
+	// LastSeen date of the last login or refresh
+	LastSeen time.Time `json:"last_seen"`
+
+	// UserAgent user agent of the last login or refresh
+	UserAgent string `json:"user_agent"`
+}
+
+// SubmitMagicLink defines model for SubmitMagicLink.
+type SubmitMagicLink struct {
+	// DeviceName name of the device shown in the sessions, the user agent by default
+	DeviceName *string `json:"device_name,omitempty" validate:"omitempty,max=100"`
+
+	// Token token of the login link
+	Token string `json:"token" validate:"required"`
+}
+
 // SubmitResetPassword defines model for SubmitResetPassword.
 type SubmitResetPassword struct {
 	// Password user new password
@@ -74,6 +202,30 @@ type SubmitResetPassword struct {
 	Token string `json:"token" validate:"required"`
 }
 
+// TOTPSetupResponse defines model for TOTPSetupResponse.
+type TOTPSetupResponse struct {
+	// Secret base32 encoded secret
+	Secret string `json:"secret"`
+
+	// Url otpauth url of the secret, usually shown as a QR code
+	URL string `json:"url"`
+}
+
+// UnlockLogin defines model for UnlockLogin.
+type UnlockLogin struct {
+	// Token unlock token sent by email when the account was locked
+	Token string `json:"token" validate:"required,max=64"`
+}
+
+// UpdateRole defines model for UpdateRole.
+type UpdateRole struct {
+	// Description what the role is for
+	Description string `json:"description" validate:"required,max=200"`
+
+	// Permissions permissions the role grants: list.read.any, list.write.any, list.manage.any, image.read.any, user.read.any, user.write.any, user.ban, user.role.assign, role.manage, audit.read
+	Permissions []string `json:"permissions" validate:"unique,dive,oneof=list.read.any list.write.any list.manage.any image.read.any user.read.any user.write.any user.ban user.role.assign role.manage audit.read"`
+}
+
 // UserResponse defines model for UserResponse.
 type UserResponse struct {
 	// DateCreated date created
@@ -89,6 +241,15 @@ type UserResponse struct {
 	Name string `json:"name"`
 }
 
+// VerifyTOTP defines model for VerifyTOTP.
+type VerifyTOTP struct {
+	// Code code of the authenticator app
+	Code string `json:"code" validate:"required,len=6,numeric"`
+}
+
+// PostAuth2faSetupJSONBody defines parameters for PostAuth2faSetup.
+type PostAuth2faSetupJSONBody = map[string]interface{}
+
 // PostAuthLogoutJSONBody defines parameters for PostAuthLogout.
 type PostAuthLogoutJSONBody = map[string]interface{}
 
@@ -101,15 +262,36 @@ type PostAuthRefreshJSONBody = map[string]interface{}
 // PostAuthValidateJSONBody defines parameters for PostAuthValidate.
 type PostAuthValidateJSONBody = map[string]interface{}
 
+// PutAdminRolesRoleJSONRequestBody defines body for PutAdminRolesRole for application/json ContentType.
+type PutAdminRolesRoleJSONRequestBody = UpdateRole
+
+// PostAuth2faDisableJSONRequestBody defines body for PostAuth2faDisable for application/json ContentType.
+type PostAuth2faDisableJSONRequestBody = DisableTOTP
+
+// PostAuth2faSetupJSONRequestBody defines body for PostAuth2faSetup for application/json ContentType.
+type PostAuth2faSetupJSONRequestBody = PostAuth2faSetupJSONBody
+
+// PostAuth2faVerifyJSONRequestBody defines body for PostAuth2faVerify for application/json ContentType.
+type PostAuth2faVerifyJSONRequestBody = VerifyTOTP
+
 // PostAuthLoginJSONRequestBody defines body for PostAuthLogin for application/json ContentType.
 type PostAuthLoginJSONRequestBody = LoginUser
 
+// PostAuthLogin2faJSONRequestBody defines body for PostAuthLogin2fa for application/json ContentType.
+type PostAuthLogin2faJSONRequestBody = LoginTwoFactor
+
 // PostAuthLogoutJSONRequestBody defines body for PostAuthLogout for application/json ContentType.
 type PostAuthLogoutJSONRequestBody = PostAuthLogoutJSONBody
 
 // PostAuthLogoutAllJSONRequestBody defines body for PostAuthLogoutAll for application/json ContentType.
 type PostAuthLogoutAllJSONRequestBody = PostAuthLogoutAllJSONBody
 
+// PostAuthMagicLinkJSONRequestBody defines body for PostAuthMagicLink for application/json ContentType.
+type PostAuthMagicLinkJSONRequestBody = MagicLink
+
+// PostAuthMagicLinkSubmitJSONRequestBody defines body for PostAuthMagicLinkSubmit for application/json ContentType.
+type PostAuthMagicLinkSubmitJSONRequestBody = SubmitMagicLink
+
 // PostAuthPasswordChangeJSONRequestBody defines body for PostAuthPasswordChange for application/json ContentType.
 type PostAuthPasswordChangeJSONRequestBody = ChangePassword
 
@@ -122,5 +304,8 @@ type PostAuthPasswordResetSubmitJSONRequestBody = SubmitResetPassword
 // PostAuthRefreshJSONRequestBody defines body for PostAuthRefresh for application/json ContentType.
 type PostAuthRefreshJSONRequestBody = PostAuthRefreshJSONBody
 
+// PostAuthUnlockJSONRequestBody defines body for PostAuthUnlock for application/json ContentType.
+type PostAuthUnlockJSONRequestBody = UnlockLogin
+
 // PostAuthValidateJSONRequestBody defines body for PostAuthValidate for application/json ContentType.
 type PostAuthValidateJSONRequestBody = PostAuthValidateJSONBody
@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+var roleName = regexp.MustCompile(`^[A-Z][A-Z_]{0,31}$`)
+
+var errRoleName = errors.New("role must be upper case letters and underscores")
+
+func (s *Service) GetRoles(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.get-roles")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	res, err := s.core.GetRoles(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetRolesBusiness.Error())
+		return fmt.Errorf(
+			"cannot get roles: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	rs := make([]RoleResponse, 0, len(res))
+	for _, role := range res {
+		rs = append(rs, populateRoleResponse(role))
+	}
+	return web.Respond(ctx, w, rs, http.StatusOK)
+}
+
+// UpdateRole creates or updates a role, the permissions apply to the users
+// holding it from their next request on.
+func (s *Service) UpdateRole(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.update-role")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	name := web.Param(r, "role")
+	if !roleName.MatchString(name) {
+		s.log.Error().Str("TraceID", tID).Msg(ErrUpdateRoleValidateName.Error())
+		return web.NewRequestError(
+			errRoleName,
+			http.StatusBadRequest,
+		)
+	}
+	ur := UpdateRole{}
+	if err := web.Decode(r, &ur); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUpdateRoleDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	res, err := s.core.UpdateRole(ctx, authUsecase.UpdateRole{
+		Name:        name,
+		Description: ur.Description,
+		Permissions: ur.Permissions,
+	})
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUpdateRoleBusiness.Error())
+		return fmt.Errorf(
+			"cannot update role: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	if err := s.auth.ReloadPolicy(ctx); err != nil {
+		// the periodic reload picks the change up
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUpdateRoleReloadPolicy.Error())
+	}
+	return web.Respond(ctx, w, populateRoleResponse(res), http.StatusOK)
+}
+
+// DeleteRole deletes a role that no user holds.
+func (s *Service) DeleteRole(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.delete-role")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	name := web.Param(r, "role")
+	if !roleName.MatchString(name) {
+		s.log.Error().Str("TraceID", tID).Msg(ErrDeleteRoleValidateName.Error())
+		return web.NewRequestError(
+			errRoleName,
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.core.DeleteRole(ctx, name); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteRoleBusiness.Error())
+		return fmt.Errorf(
+			"cannot delete role: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	if err := s.auth.ReloadPolicy(ctx); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteRoleReloadPolicy.Error())
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusOK)
+}
+
+func populateRoleResponse(r authUsecase.Role) RoleResponse {
+	perms := r.Permissions
+	if perms == nil {
+		perms = []string{}
+	}
+	return RoleResponse{
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: perms,
+		DateCreated: r.DateCreated,
+		DateUpdated: r.DateUpdated,
+	}
+}
@@ -12,6 +12,7 @@ import (
 	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	authPkg "github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/lockout"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/messages"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 
@@ -19,10 +20,11 @@ import (
 )
 
 type Service struct {
-	log  *zerolog.Logger
-	auth *authPkg.Auth
-	core *authUsecase.Core
-	mq   *queue.Channel
+	log     *zerolog.Logger
+	auth    *authPkg.Auth
+	core    *authUsecase.Core
+	mq      *queue.Channel
+	lockout *lockout.Lockout
 }
 
 func NewService(
@@ -30,13 +32,15 @@ func NewService(
 	a *authPkg.Auth,
 	c *authUsecase.Core,
 	mq *queue.Channel,
+	lo *lockout.Lockout,
 ) *Service {
 
 	return &Service{
-		log:  l,
-		auth: a,
-		core: c,
-		mq:   mq,
+		log:     l,
+		auth:    a,
+		core:    c,
+		mq:      mq,
+		lockout: lo,
 	}
 }
 
@@ -56,11 +60,130 @@ func (s *Service) Login(ctx context.Context, w http.ResponseWriter, r *http.Requ
 		Email:    strings.ToLower(lu.Email),
 		Password: lu.Password,
 	}
+	ip := web.GetClientIP(ctx)
+	wait, err := s.lockout.Check(ctx, au.Email, ip)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginCheckLockout.Error())
+		return ErrLoginCheckLockout
+	}
+	if wait > 0 {
+		s.log.Warn().Str("TraceID", tID).Msgf("auth: login: %s", lockout.ErrLocked.Error())
+		return web.NewTooManyRequestsError(lockout.ErrLocked, wait)
+	}
 	res, err := s.core.Login(ctx, au)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginBusiness.Error())
+		if errors.Is(err, web.ErrAuthFailed) {
+			s.failLogin(ctx, au.Email, ip)
+		}
 		return web.GetResponseErrorFromBusiness(err)
 	}
+	if err := s.lockout.Succeed(ctx, au.Email); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginResetLockout.Error())
+	}
+	return s.completeLogin(ctx, w, r, res, derefString(lu.DeviceName))
+}
+
+// completeLogin starts a session for the authenticated user, or answers
+// with a challenge token if the user has to pass the second factor first.
+func (s *Service) completeLogin(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	res authUsecase.AuthenticatedUser,
+	deviceName string,
+) error {
+	tID := web.GetTraceID(ctx)
+	if res.TwoFactor {
+		c := authPkg.Claims{}
+		c.Subject = res.UserID
+		challengeToken, err := s.auth.GenerateChallengeToken(ctx, c)
+		if err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginGenChallengeToken.Error())
+			return ErrLoginGenChallengeToken
+		}
+		return web.Respond(ctx, w, LoginChallengeResponse{ChallengeToken: challengeToken}, http.StatusAccepted)
+	}
+	if err := s.startSession(ctx, w, r, res, deviceName); err != nil {
+		return err
+	}
+	u := UserResponse{
+		Name:        res.Name,
+		ID:          res.UserID,
+		Email:       res.Email,
+		DateCreated: res.DateCreated,
+	}
+	return web.Respond(ctx, w, u, http.StatusCreated)
+}
+
+// failLogin counts the failed attempt and sends the unlock letter when it
+// locked the account. The response stays the wrong credentials one, the
+// lock shows from the next attempt on.
+func (s *Service) failLogin(ctx context.Context, email string, ip string) {
+	tID := web.GetTraceID(ctx)
+	f, err := s.lockout.Fail(ctx, email, ip)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginFailLockout.Error())
+		return
+	}
+	if !f.Locked {
+		return
+	}
+	s.log.Warn().Str("TraceID", tID).Msgf("auth: login: %s: account locked", lockout.ErrLocked.Error())
+	res, err := s.core.UnlockRequest(ctx, email, f.UnlockToken)
+	if err != nil {
+		// nobody to tell if the email is not a user
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginUnlockRequest.Error())
+		return
+	}
+	m := messages.Message{
+		ID:    tID,
+		Email: res.Email,
+		Name:  res.Name,
+		Token: res.UnlockToken,
+		Type:  messages.LoginUnlock,
+	}
+	if err := s.mq.Publish(ctx, m); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginUnlockSendMessage.Error())
+	}
+}
+
+// Unlock lifts the lock of an account with the token of the unlock letter.
+func (s *Service) Unlock(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.unlock")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ul := UnlockLogin{}
+	if err := web.Decode(r, &ul); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUnlockDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.lockout.Unlock(ctx, ul.Token); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUnlockLockout.Error())
+		if errors.Is(err, lockout.ErrInvalidUnlockToken) {
+			return web.NewRequestError(
+				err,
+				http.StatusForbidden,
+			)
+		}
+		return ErrUnlockLockout
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusCreated)
+}
+
+// startSession issues the access and refresh tokens of a new session of the
+// authenticated user.
+func (s *Service) startSession(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	res authUsecase.AuthenticatedUser,
+	deviceName string,
+) error {
+	tID := web.GetTraceID(ctx)
 	if err := s.auth.StoreUserTokenVersion(ctx, res.UserID, res.TokenVersion); err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginStoreTokenVersion.Error())
 		return ErrLoginStoreTokenVersion
@@ -68,32 +191,34 @@ func (s *Service) Login(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	c := authPkg.Claims{}
 	c.Subject = res.UserID
 	c.Roles = res.Roles
+	// the login starts a new refresh token family
+	newRefreshToken, err := s.auth.GenerateRefreshToken(ctx, c)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginGenRefreshToken.Error())
+		return ErrLoginGenRefreshToken
+	}
+	ns := authUsecase.NewSession{
+		FamilyID:   newRefreshToken.FamilyID,
+		UserID:     res.UserID,
+		TokenID:    newRefreshToken.TokenID,
+		ExpiresAt:  newRefreshToken.ExpiresAt,
+		DeviceName: deviceName,
+		UserAgent:  r.UserAgent(),
+		IP:         web.GetClientIP(ctx),
+	}
+	if _, err := s.core.CreateSession(ctx, ns); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginCreateSession.Error())
+		return ErrLoginCreateSession
+	}
+	c.Family = newRefreshToken.FamilyID
 	newAuthToken, err := s.auth.GenerateToken(ctx, c, s.auth.AuthDuration)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginGenAuthToken.Error())
 		return ErrLoginGenAuthToken
 	}
-	newRefreshToken, err := s.auth.GenerateToken(ctx, c, s.auth.RefreshDuration)
-	if err != nil {
-		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginGenRefreshToken.Error())
-		return ErrLoginGenRefreshToken
-	}
 	w.Header().Set("Authorization", "Bearer "+newAuthToken)
-	http.SetCookie(w, &http.Cookie{
-		Name:     "refresh_token",
-		Value:    newRefreshToken,
-		Path:     "/",
-		HttpOnly: true,
-		Secure:   true,
-		SameSite: http.SameSiteStrictMode,
-	})
-	u := UserResponse{
-		Name:        res.Name,
-		ID:          res.UserID,
-		Email:       res.Email,
-		DateCreated: res.DateCreated,
-	}
-	return web.Respond(ctx, w, u, http.StatusCreated)
+	setRefreshCookie(w, newRefreshToken.Token)
+	return nil
 }
 
 func (s *Service) Logout(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -116,6 +241,7 @@ func (s *Service) Logout(ctx context.Context, w http.ResponseWriter, r *http.Req
 	}
 	dt := authUsecase.DeleteToken{
 		TokenID:      c.ID,
+		FamilyID:     c.Family,
 		Subject:      c.Subject,
 		TokenVersion: c.TokenVersion,
 		IssuedAt:     c.IssuedAt.UTC(),
@@ -142,6 +268,17 @@ func (s *Service) Logout(ctx context.Context, w http.ResponseWriter, r *http.Req
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrLogoutRevokeToken.Error())
 		return ErrLogoutRevokeToken
 	}
+	if dt.FamilyID != "" {
+		if err := s.auth.MarkFamilyAsRevoked(ctx, authPkg.FamilyParams{
+			FamilyID:  dt.FamilyID,
+			UserID:    dt.Subject,
+			ExpiresAt: dt.RevokedAt.Add(s.auth.RefreshDuration),
+			RevokedAt: dt.RevokedAt,
+		}); err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrLogoutRevokeFamily.Error())
+			return ErrLogoutRevokeFamily
+		}
+	}
 	return web.Respond(ctx, w, struct{}{}, http.StatusCreated)
 }
 
@@ -206,7 +343,7 @@ func (s *Service) Refresh(ctx context.Context, w http.ResponseWriter, r *http.Re
 			http.StatusUnauthorized,
 		)
 	}
-	newClaims, err := s.auth.ValidateToken(ctx, refreshToken.Value)
+	rc, err := s.auth.ValidateToken(ctx, refreshToken.Value)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrRefreshValidateRefreshToken.Error())
 		return web.NewRequestError(
@@ -214,6 +351,59 @@ func (s *Service) Refresh(ctx context.Context, w http.ResponseWriter, r *http.Re
 			http.StatusUnauthorized,
 		)
 	}
+	if rc.Use != authPkg.UseRefresh || rc.Family == "" {
+		s.log.Error().Str("TraceID", tID).Msg(ErrRefreshTokenUse.Error())
+		return web.NewRequestError(
+			web.ErrAuthFailed,
+			http.StatusUnauthorized,
+		)
+	}
+	newClaims := authPkg.Claims{}
+	newClaims.Subject = rc.Subject
+	newClaims.Roles = rc.Roles
+	newClaims.Family = rc.Family
+	newRefreshToken, err := s.auth.GenerateRefreshToken(ctx, newClaims)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrRefreshGenRefreshToken.Error())
+		return ErrRefreshGenRefreshToken
+	}
+	rt := authUsecase.RotateToken{
+		FamilyID:     rc.Family,
+		UserID:       rc.Subject,
+		TokenID:      rc.ID,
+		ExpiresAt:    rc.ExpiresAt.UTC(),
+		NewTokenID:   newRefreshToken.TokenID,
+		NewExpiresAt: newRefreshToken.ExpiresAt,
+		UsedAt:       time.Now().UTC(),
+		UserAgent:    r.UserAgent(),
+		IP:           web.GetClientIP(ctx),
+	}
+	if err := s.core.RotateRefreshToken(ctx, rt); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrRefreshRotateToken.Error())
+		switch {
+		case errors.Is(err, auth.ErrRefreshTokenReused):
+			// the token was stolen or replayed, every token of the family goes
+			if err := s.auth.MarkFamilyAsRevoked(ctx, authPkg.FamilyParams{
+				FamilyID:  rt.FamilyID,
+				UserID:    rt.UserID,
+				ExpiresAt: rt.UsedAt.Add(s.auth.RefreshDuration),
+				RevokedAt: rt.UsedAt,
+			}); err != nil {
+				s.log.Err(err).Str("TraceID", tID).Msg(ErrRefreshRevokeFamily.Error())
+			}
+		case errors.Is(err, auth.ErrRevokedTokenFamily), errors.Is(err, web.ErrAuthFailed):
+		default:
+			return fmt.Errorf(
+				"cannot rotate refresh token: %w",
+				web.GetResponseErrorFromBusiness(err),
+			)
+		}
+		clearSession(w)
+		return web.NewRequestError(
+			web.ErrAuthFailed,
+			http.StatusUnauthorized,
+		)
+	}
 	newAuthToken, err := s.auth.GenerateToken(ctx, newClaims, s.auth.AuthDuration)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrRefreshGenAuthToken.Error())
@@ -242,6 +432,7 @@ func (s *Service) Refresh(ctx context.Context, w http.ResponseWriter, r *http.Re
 
 	}
 	w.Header().Set("Authorization", "Bearer "+newAuthToken)
+	setRefreshCookie(w, newRefreshToken.Token)
 	return web.Respond(ctx, w, struct{}{}, http.StatusCreated)
 }
 
@@ -348,7 +539,7 @@ func (s *Service) PasswordResetSubmit(ctx context.Context, w http.ResponseWriter
 				http.StatusForbidden,
 			)
 		}
-		return fmt.Errorf("cannot update password: %w", err)
+		return fmt.Errorf("cannot update password: %w", web.GetResponseErrorFromBusiness(err))
 	}
 	if err := s.auth.StoreUserTokenVersion(ctx, u.ID, u.TokenVersion); err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginStoreTokenVersion.Error())
@@ -394,6 +585,45 @@ func (s *Service) LogoutAll(ctx context.Context, w http.ResponseWriter, r *http.
 	return web.Respond(ctx, w, struct{}{}, http.StatusCreated)
 }
 
+// ForceLogout logs the user in the path out everywhere on behalf of an admin.
+func (s *Service) ForceLogout(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.force-logout")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	id := web.Param(r, "id")
+	if err := web.ValidateUUID(id); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrForceLogoutValidateUUID.Error())
+		return web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	tv, err := s.core.ForceLogout(ctx, id)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrForceLogoutBusiness.Error())
+		return fmt.Errorf(
+			"cannot logout user: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	if err := s.auth.StoreUserTokenVersion(ctx, id, tv); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginStoreTokenVersion.Error())
+		return ErrLoginStoreTokenVersion
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusOK)
+}
+
+func setRefreshCookie(w http.ResponseWriter, token string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     "refresh_token",
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
 func clearSession(w http.ResponseWriter) {
 	w.Header().Del("Authorization")
 	http.SetCookie(w, &http.Cookie{
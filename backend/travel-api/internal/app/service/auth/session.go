@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	authPkg "github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) GetSessions(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.get-sessions")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	c, err := authPkg.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(authPkg.ErrGetClaims.Error())
+		return authPkg.ErrGetClaims
+	}
+	res, err := s.core.GetSessions(ctx, c.Subject)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetSessionsBusiness.Error())
+		return fmt.Errorf(
+			"cannot get sessions: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ss := make([]SessionResponse, 0, len(res))
+	for _, session := range res {
+		ss = append(ss, populateSessionResponse(session, c.Family))
+	}
+	return web.Respond(ctx, w, ss, http.StatusOK)
+}
+
+// TerminateSession signs the session out, its access and refresh tokens
+// are rejected from the next request on.
+func (s *Service) TerminateSession(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.terminate-session")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	c, err := authPkg.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(authPkg.ErrGetClaims.Error())
+		return authPkg.ErrGetClaims
+	}
+	sessionID := web.Param(r, "sessionID")
+	if err := web.ValidateUUID(sessionID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSessionValidateUUID.Error())
+		return web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	res, err := s.core.TerminateSession(ctx, c.Subject, sessionID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrTerminateSessionBusiness.Error())
+		return fmt.Errorf(
+			"cannot terminate session: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	if err := s.auth.MarkFamilyAsRevoked(ctx, authPkg.FamilyParams{
+		FamilyID:  res.ID,
+		UserID:    res.UserID,
+		ExpiresAt: res.ExpiresAt,
+		RevokedAt: *res.RevokedAt,
+	}); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrTerminateSessionRevoke.Error())
+		return ErrTerminateSessionRevoke
+	}
+	if res.ID == c.Family {
+		clearSession(w)
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusOK)
+}
+
+func populateSessionResponse(s authUsecase.Session, current string) SessionResponse {
+	return SessionResponse{
+		ID:          s.ID,
+		DeviceName:  s.DeviceName,
+		UserAgent:   s.UserAgent,
+		IP:          s.IP,
+		Current:     s.ID == current,
+		DateCreated: s.DateCreated,
+		LastSeen:    s.LastSeen,
+	}
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
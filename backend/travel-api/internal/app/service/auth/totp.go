@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	authUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/auth"
+	authPkg "github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/lockout"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/totp"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// LoginTwoFactor exchanges the challenge token of the password step and a
+// code for the tokens of a new session. The challenge token is single use.
+func (s *Service) LoginTwoFactor(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.login-two-factor")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	lt := LoginTwoFactor{}
+	if err := web.Decode(r, &lt); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginTwoFactorDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	c, err := s.auth.ValidateToken(ctx, lt.ChallengeToken)
+	if err != nil || c.Use != authPkg.UseChallenge {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginTwoFactorValidateToken.Error())
+		return web.NewRequestError(
+			authPkg.ErrInvalidChallengeToken,
+			http.StatusUnauthorized,
+		)
+	}
+	// codes are counted per user, the challenge token can be fetched again
+	// with the password, so counting per token would not slow anything down
+	ip := web.GetClientIP(ctx)
+	wait, err := s.lockout.Check(ctx, c.Subject, ip)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginCheckLockout.Error())
+		return ErrLoginCheckLockout
+	}
+	if wait > 0 {
+		s.log.Warn().Str("TraceID", tID).Msgf("auth: login two factor: %s", lockout.ErrLocked.Error())
+		return web.NewTooManyRequestsError(lockout.ErrLocked, wait)
+	}
+	res, err := s.core.VerifyLoginCode(ctx, c.Subject, lt.Code)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginTwoFactorBusiness.Error())
+		if errors.Is(err, web.ErrAuthFailed) {
+			// the unlock letter is sent by the password step only, a lock
+			// of the second factor lasts its whole duration
+			if _, err := s.lockout.Fail(ctx, c.Subject, ip); err != nil {
+				s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginFailLockout.Error())
+			}
+		}
+		return web.GetResponseErrorFromBusiness(err)
+	}
+	if err := s.lockout.Succeed(ctx, c.Subject); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginResetLockout.Error())
+	}
+	if err := s.auth.MarkTokenAsRevoked(ctx, authPkg.TokenParams{
+		TokenID:      c.ID,
+		Subject:      c.Subject,
+		TokenVersion: c.TokenVersion,
+		IssuedAt:     c.IssuedAt.UTC(),
+		ExpiresAt:    c.ExpiresAt.UTC(),
+		RevokedAt:    time.Now().UTC(),
+	}); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrLoginTwoFactorRevokeChallenge.Error())
+		return ErrLoginTwoFactorRevokeChallenge
+	}
+	if err := s.startSession(ctx, w, r, res, derefString(lt.DeviceName)); err != nil {
+		return err
+	}
+	u := UserResponse{
+		Name:        res.Name,
+		ID:          res.UserID,
+		Email:       res.Email,
+		DateCreated: res.DateCreated,
+	}
+	return web.Respond(ctx, w, u, http.StatusCreated)
+}
+
+// SetupTOTP starts the enrollment of an authenticator app, two-factor
+// authentication is enabled once VerifyTOTP accepts a code of the secret.
+func (s *Service) SetupTOTP(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.setup-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	e := struct{}{}
+	if err := web.Decode(r, &e); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSetupTOTPDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	c, err := authPkg.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(authPkg.ErrGetClaims.Error())
+		return authPkg.ErrGetClaims
+	}
+	res, err := s.core.SetupTOTP(ctx, c.Subject)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSetupTOTPBusiness.Error())
+		return fmt.Errorf(
+			"cannot setup totp: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ts := TOTPSetupResponse{
+		Secret: res.Secret,
+		URL:    totp.URL(s.auth.Issuer, res.Email, res.Secret),
+	}
+	return web.Respond(ctx, w, ts, http.StatusCreated)
+}
+
+func (s *Service) VerifyTOTP(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.verify-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	vt := VerifyTOTP{}
+	if err := web.Decode(r, &vt); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrVerifyTOTPDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	c, err := authPkg.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(authPkg.ErrGetClaims.Error())
+		return authPkg.ErrGetClaims
+	}
+	codes, err := s.core.EnableTOTP(ctx, c.Subject, vt.Code)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrVerifyTOTPBusiness.Error())
+		return fmt.Errorf(
+			"cannot verify totp: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, RecoveryCodesResponse{RecoveryCodes: codes}, http.StatusCreated)
+}
+
+func (s *Service) DisableTOTP(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.auth.disable-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	dt := DisableTOTP{}
+	if err := web.Decode(r, &dt); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDisableTOTPDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	c, err := authPkg.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(authPkg.ErrGetClaims.Error())
+		return authPkg.ErrGetClaims
+	}
+	d := authUsecase.DisableTOTP{
+		UserID:   c.Subject,
+		Password: dt.Password,
+		Code:     dt.Code,
+	}
+	if err := s.core.DisableTOTP(ctx, d); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDisableTOTPBusiness.Error())
+		return fmt.Errorf(
+			"cannot disable totp: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusCreated)
+}
@@ -17,3 +17,31 @@ func (cp ResetPassword) Validate() error {
 func (cp SubmitResetPassword) Validate() error {
 	return web.Check(cp)
 }
+
+func (lt LoginTwoFactor) Validate() error {
+	return web.Check(lt)
+}
+
+func (vt VerifyTOTP) Validate() error {
+	return web.Check(vt)
+}
+
+func (dt DisableTOTP) Validate() error {
+	return web.Check(dt)
+}
+
+func (ul UnlockLogin) Validate() error {
+	return web.Check(ul)
+}
+
+func (ur UpdateRole) Validate() error {
+	return web.Check(ur)
+}
+
+func (ml MagicLink) Validate() error {
+	return web.Check(ml)
+}
+
+func (sm SubmitMagicLink) Validate() error {
+	return web.Check(sm)
+}
@@ -0,0 +1,18 @@
+package export
+
+import "errors"
+
+var (
+	ErrRequestExportBusiness    = errors.New("error request export from business layer")
+	ErrRequestExportSendMessage = errors.New("error request export sending message")
+	ErrDownloadValidate         = errors.New("error download export parsing query")
+	ErrDownloadBusiness         = errors.New("error download export from business layer")
+	ErrDownloadDeadline         = errors.New("error download export extending write deadline")
+	ErrParseMessage             = errors.New("error export service parsing message")
+	ErrBuildExport              = errors.New("error building export")
+	ErrFailExport               = errors.New("error marking export as failed")
+	ErrSendMessage              = errors.New("error sending export ready message")
+	ErrAckMessage               = errors.New("error acknowledging message")
+	ErrNackMessage              = errors.New("error queueing message to dlq")
+	ErrChanFull                 = errors.New("message error channel is full, might be an error")
+)
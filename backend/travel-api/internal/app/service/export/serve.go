@@ -0,0 +1,134 @@
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	queue "github.com/f4mk/travel/backend/pkg/mb"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/messages"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type ServeError struct {
+	Error   error
+	Payload []byte
+}
+
+// Serve builds the exports from the export queue one at a time until the
+// context is done. A failed export is marked so and sent to the dlq.
+func (s *Service) Serve(ctx context.Context, errMsgCh chan<- ServeError, errServiceCh chan<- error) {
+	rx, err := s.exportQ.Consume()
+	if err != nil {
+		errServiceCh <- err
+		return
+	}
+	// init complete
+	close(errServiceCh)
+
+	for {
+		select {
+		case msg, ok := <-rx:
+			if !ok {
+				close(s.doneCh)
+				s.log.Warn().Msg("rx channel got closed, returning from export service")
+				return
+			}
+			s.handle(ctx, msg, errMsgCh)
+
+		case <-ctx.Done():
+			s.log.Warn().Msg("shutting down export service due to ctx done")
+			close(s.doneCh)
+			return
+		}
+	}
+}
+
+func (s *Service) Stop() <-chan struct{} {
+	s.log.Warn().Msg("stopping export service")
+	return s.doneCh
+}
+
+func (s *Service) handle(ctx context.Context, msg queue.Message, errMsgCh chan<- ServeError) {
+	m := messages.Export{}
+	err := json.Unmarshal(msg.Body, &m)
+	tID := m.ID
+	ctx, span := web.AddSpan(ctx, "service.export.serve", attribute.String("TraceID", tID))
+	defer span.End()
+	v := web.Values{
+		TraceID: tID,
+		Now:     time.Now().UTC(),
+	}
+	ctx = web.SetValues(ctx, &v)
+
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf("error decoding message: %s", ErrParseMessage.Error())
+		// makes no sense to requeue due to invalid json
+		if err := msg.Nack(false, false); err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrNackMessage.Error())
+		}
+		if err := sendError(errMsgCh, fmt.Errorf("%s: %w", ErrParseMessage.Error(), err), msg.Body); err != nil {
+			s.log.Warn().Str("TraceID", tID).Msg(ErrChanFull.Error())
+		}
+		return
+	}
+
+	if err := s.build(ctx, m.ExportID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrBuildExport.Error())
+		// an export that is not pending was built or failed before
+		if !errors.Is(err, web.ErrNotFound) {
+			if err := s.core.FailExport(ctx, m.ExportID); err != nil {
+				s.log.Err(err).Str("TraceID", tID).Msg(ErrFailExport.Error())
+			}
+		}
+		if err := msg.Nack(false, false); err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrNackMessage.Error())
+		}
+		if err := sendError(errMsgCh, fmt.Errorf("%s: %w", ErrBuildExport.Error(), err), msg.Body); err != nil {
+			s.log.Warn().Str("TraceID", tID).Msg(ErrChanFull.Error())
+		}
+		return
+	}
+	if err := msg.Ack(false); err != nil {
+		// nothing really can do here, the export was already built
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrAckMessage.Error())
+	}
+}
+
+// build builds the export and emails its download link. The export is
+// marked ready only once the email is queued, so that a failed email
+// fails the export instead of leaving a link nobody received.
+func (s *Service) build(ctx context.Context, exportID string) error {
+	tID := web.GetTraceID(ctx)
+	res, err := s.core.BuildExport(ctx, exportID)
+	if err != nil {
+		return err
+	}
+	m := messages.Message{
+		ID:    tID,
+		Email: res.Email,
+		Name:  res.Name,
+		Token: res.DownloadToken,
+		Type:  messages.DataExportReady,
+	}
+	if err := s.mailQ.Publish(ctx, m); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSendMessage.Error())
+		return fmt.Errorf("cannot send message: %w", err)
+	}
+	return s.core.CompleteExport(ctx, exportID, res.DownloadToken)
+}
+
+func sendError(errCh chan<- ServeError, errMsg error, payload []byte) error {
+	select {
+	case errCh <- ServeError{
+		Error:   errMsg,
+		Payload: payload,
+	}:
+		return nil
+	default:
+		return ErrChanFull
+	}
+}
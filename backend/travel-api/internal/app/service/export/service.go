@@ -0,0 +1,117 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	queue "github.com/f4mk/travel/backend/pkg/mb"
+	exportUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/export"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/messages"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+// downloadTimeout bounds the time to send an archive, the server write
+// timeout is too short for it.
+const downloadTimeout = time.Hour
+
+// Service takes the export requests and builds the archives from the
+// export queue, the download links are sent through the mail queue.
+type Service struct {
+	core   *exportUsecase.Core
+	log    *zerolog.Logger
+	doneCh chan struct{}
+	// exportQ holds the exports to build
+	exportQ *queue.Channel
+	// mailQ sends the letters
+	mailQ *queue.Channel
+}
+
+func NewService(l *zerolog.Logger, c *exportUsecase.Core, exportQ *queue.Channel, mailQ *queue.Channel) *Service {
+	return &Service{
+		core:    c,
+		log:     l,
+		doneCh:  make(chan struct{}),
+		exportQ: exportQ,
+		mailQ:   mailQ,
+	}
+}
+
+// RequestExport queues the export of all the data of the user,
+// the download link is emailed once the archive is built.
+func (s *Service) RequestExport(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.export.request-export")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	e, err := s.core.RequestExport(ctx, claims.Subject)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrRequestExportBusiness.Error())
+		return fmt.Errorf(
+			"cannot request export: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	m := messages.Export{
+		ID:       tID,
+		ExportID: e.ID,
+	}
+	if err := s.exportQ.Publish(ctx, m); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrRequestExportSendMessage.Error())
+		// the export would block new requests while pending
+		if err := s.core.FailExport(ctx, e.ID); err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrFailExport.Error())
+		}
+		return fmt.Errorf("cannot send message: %w", err)
+	}
+	res := ExportResponse{
+		ID:          e.ID,
+		DateCreated: e.DateCreated,
+	}
+	return web.Respond(ctx, w, res, http.StatusAccepted)
+}
+
+// Download returns the archive of the download token sent by email.
+func (s *Service) Download(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.export.download")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetUsersExportDownloadParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDownloadValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	e, rc, err := s.core.GetArchive(ctx, p.Token)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDownloadBusiness.Error())
+		if errors.Is(err, web.ErrNotFound) {
+			// return forbidden to not spoil if token even exists
+			return web.NewRequestError(
+				web.ErrForbidden,
+				http.StatusForbidden,
+			)
+		}
+		return fmt.Errorf("cannot download export: %w", err)
+	}
+	defer rc.Close()
+	rctl := http.NewResponseController(w)
+	if err := rctl.SetWriteDeadline(time.Now().Add(downloadTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDownloadDeadline.Error())
+		return fmt.Errorf("cannot download export: %w", err)
+	}
+	fname := fmt.Sprintf("traillyst-%s.zip", e.DateCreated.Format("2006-01-02"))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fname}))
+	return web.RespondRaw(ctx, w, rc, http.StatusOK, "application/zip")
+}
@@ -3,9 +3,10 @@ package image
 import "errors"
 
 var (
-	ErrGetImageBusiness   = errors.New("error get image from business layer")
-	ErrPostImageBusiness  = errors.New("error post image from business layer")
-	ErrPostImageDecode    = errors.New("error post image parsing user input")
-	ErrPostImageDecodeLen = errors.New("error post image parsing user input: no images found")
-	ErrPostImageRead      = errors.New("error post image parsing user input: cannot open image")
+	ErrGetImageBusiness     = errors.New("error get image from business layer")
+	ErrGetImageValidateUUID = errors.New("error get image validate uuid")
+	ErrPostImageBusiness    = errors.New("error post image from business layer")
+	ErrPostImageDecode      = errors.New("error post image parsing user input")
+	ErrPostImageDecodeLen   = errors.New("error post image parsing user input: no images found")
+	ErrPostImageRead        = errors.New("error post image parsing user input: cannot open image")
 )
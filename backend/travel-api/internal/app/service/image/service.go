@@ -60,6 +60,32 @@ func (s *Service) Serve(ctx context.Context, w http.ResponseWriter, r *http.Requ
 	return web.RespondRaw(ctx, w, reader, http.StatusOK, "image/webp")
 }
 
+func (s *Service) ServePublic(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.image.serve-public")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	slug := web.Param(r, "slug")
+	fileID := web.Param(r, "fname")
+	if err := web.ValidateUUID(fileID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetImageValidateUUID.Error())
+		return web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	reader, err := s.core.GetPublicImageByID(ctx, slug, fileID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetImageBusiness.Error())
+		return fmt.Errorf(
+			"cannot get image: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	defer reader.Close()
+
+	return web.RespondRaw(ctx, w, reader, http.StatusOK, "image/webp")
+}
+
 func (s *Service) Store(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	ctx, span := web.AddSpan(ctx, "service.image.store")
 	defer span.End()
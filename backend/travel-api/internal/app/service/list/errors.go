@@ -20,4 +20,49 @@ var (
 	ErrItemCreateValidate   = errors.New("error create item parsing user input")
 	ErrItemUpdateValidate   = errors.New("error update item parsing user input")
 	ErrItemDeleteValidate   = errors.New("error delete item parsing user input")
+
+	ErrGetMembersBusiness     = errors.New("error query members from business layer")
+	ErrAddMemberBusiness      = errors.New("error add member from business layer")
+	ErrUpdateMemberBusiness   = errors.New("error update member from business layer")
+	ErrDeleteMemberBusiness   = errors.New("error delete member from business layer")
+	ErrMemberValidateUserUUID = errors.New("error member validate user uuid")
+	ErrMemberAddValidate      = errors.New("error add member parsing user input")
+	ErrMemberUpdateValidate   = errors.New("error update member parsing user input")
+	ErrMemberDeleteValidate   = errors.New("error delete member parsing user input")
+	ErrMemberSendMessage      = errors.New("error add member send message")
+
+	ErrGetPublicListBusiness  = errors.New("error query public list from business layer")
+	ErrGetSharesBusiness      = errors.New("error query shares from business layer")
+	ErrCreateShareBusiness    = errors.New("error create share from business layer")
+	ErrDeleteShareBusiness    = errors.New("error delete share from business layer")
+	ErrShareValidateShareUUID = errors.New("error share validate share uuid")
+	ErrShareCreateValidate    = errors.New("error create share parsing user input")
+	ErrShareDeleteValidate    = errors.New("error delete share parsing user input")
+
+	ErrGetItemsNearBusiness = errors.New("error query items near from business layer")
+	ErrGetItemsBBoxBusiness = errors.New("error query items in bbox from business layer")
+	ErrItemsNearValidate    = errors.New("error items near parsing user input")
+	ErrItemsBBoxValidate    = errors.New("error items bbox parsing user input")
+
+	ErrOptimizeRouteBusiness = errors.New("error optimize route from business layer")
+	ErrRouteOptimizeValidate = errors.New("error optimize route parsing user input")
+
+	ErrExportListBusiness = errors.New("error export list from business layer")
+	ErrListExportValidate = errors.New("error export list parsing user input")
+
+	ErrImportItemsBusiness = errors.New("error import items from business layer")
+	ErrItemsImportDecode   = errors.New("error import items parsing user input")
+	ErrItemsImportRead     = errors.New("error import items parsing user input: cannot open file")
+	ErrItemsImportFormat   = errors.New("error import items parsing user input: unknown file format")
+	ErrItemsImportLen      = errors.New("error import items parsing user input: too many features")
+
+	ErrListsQueryValidate    = errors.New("error query lists parsing user input")
+	ErrItemsQueryValidate    = errors.New("error query items parsing user input")
+	ErrListsValidateUserUUID = errors.New("error query lists validate user uuid")
+
+	ErrSearchBusiness = errors.New("error search from business layer")
+	ErrSearchValidate = errors.New("error search parsing user input")
+
+	ErrSubscribeEventsBusiness = errors.New("error subscribe list events from business layer")
+	ErrStreamEvents            = errors.New("error stream list events")
 )
@@ -0,0 +1,11 @@
+package list
+
+import "github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+
+func (lr ListResponse) ETag() string {
+	return web.ETag(lr.Version)
+}
+
+func (ir ItemResponse) ETag() string {
+	return web.ETag(ir.Version)
+}
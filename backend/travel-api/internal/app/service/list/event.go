@@ -0,0 +1,96 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// eventsHeartbeat is how often an idle stream is pinged,
+// the access to the list is checked again at the same time.
+const eventsHeartbeat = 25 * time.Second
+
+// StreamEvents streams the list changes as server-sent events until the
+// client disconnects, loses access to the list or the list is deleted.
+// Once the stream has started errors are only logged, the status is already sent.
+func (s *Service) StreamEvents(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.stream-events")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	events, cancel, err := s.core.SubscribeEvents(ctx, claims.Subject, listID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSubscribeEventsBusiness.Error())
+		// a stream ending right away would have the clients reconnect for nothing
+		if errors.Is(err, listUsecase.ErrEventsUnavailable) {
+			return web.NewRequestError(err, http.StatusServiceUnavailable)
+		}
+		return fmt.Errorf(
+			"cannot subscribe to list events: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	defer cancel()
+
+	es, err := web.NewEventStream(ctx, w)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrStreamEvents.Error())
+		return nil
+	}
+	ticker := time.NewTicker(eventsHeartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := es.Send(e.ID, e.Type, populateListEventResponse(e)); err != nil {
+				s.log.Err(err).Str("TraceID", tID).Msg(ErrStreamEvents.Error())
+				return nil
+			}
+			if e.Type == listUsecase.EventListDeleted {
+				return nil
+			}
+		case <-ticker.C:
+			if _, err := s.core.GetListByID(ctx, claims.Subject, listID); err != nil {
+				s.log.Err(err).Str("TraceID", tID).Msg(ErrSubscribeEventsBusiness.Error())
+				return nil
+			}
+			if err := es.Ping(); err != nil {
+				s.log.Err(err).Str("TraceID", tID).Msg(ErrStreamEvents.Error())
+				return nil
+			}
+		}
+	}
+}
+
+func populateListEventResponse(e listUsecase.Event) ListEventResponse {
+	return ListEventResponse{
+		ID:      e.ID,
+		Type:    e.Type,
+		ListID:  e.ListID,
+		ItemID:  e.ItemID,
+		ImageID: e.ImageID,
+		UserID:  e.UserID,
+		Version: e.Version,
+		Date:    e.Date,
+	}
+}
@@ -0,0 +1,76 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/geofile"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) ExportList(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.export-list")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetListsListIdExportParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListExportValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	list, items, err := s.core.ExportList(ctx, claims.Subject, listID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrExportListBusiness.Error())
+		return fmt.Errorf(
+			"cannot export list: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	doc := geofile.Document{
+		Name:        list.Name,
+		Description: list.Description,
+		Route:       true,
+	}
+	for _, item := range items {
+		wp := geofile.Waypoint{
+			Name:    item.Name,
+			Lat:     item.Point.Lat,
+			Lng:     item.Point.Lng,
+			Visited: item.Visited,
+		}
+		if item.Description != nil {
+			wp.Description = *item.Description
+		}
+		if item.Address != nil {
+			wp.Address = *item.Address
+		}
+		doc.Waypoints = append(doc.Waypoints, wp)
+	}
+
+	f := geofile.Format(p.Format)
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(geofile.Encode(pw, f, doc))
+	}()
+	defer pr.Close()
+
+	fname := fmt.Sprintf("%s.%s", list.ID, f)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": fname}))
+	return web.RespondRaw(ctx, w, pr, http.StatusOK, f.ContentType())
+}
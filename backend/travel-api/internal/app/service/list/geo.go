@@ -0,0 +1,113 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) GetItemsNear(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.get-items-near")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetItemsNearParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsNearValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	q := listUsecase.NearQuery{
+		UserID:   claims.Subject,
+		Lat:      p.Lat,
+		Lng:      p.Lng,
+		Radius:   p.RadiusM,
+		Page:     derefInt(p.Page),
+		PageSize: derefInt(p.PageSize),
+	}
+	res, err := s.core.GetItemsNear(ctx, q)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetItemsNearBusiness.Error())
+		return fmt.Errorf(
+			"cannot query items near: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, populateGeoItemsResponse(res), http.StatusOK)
+}
+
+func (s *Service) GetItemsInBBox(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.get-items-in-bbox")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetItemsBboxParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsBBoxValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	q := listUsecase.BBoxQuery{
+		UserID:   claims.Subject,
+		MinLat:   p.MinLat,
+		MinLng:   p.MinLng,
+		MaxLat:   p.MaxLat,
+		MaxLng:   p.MaxLng,
+		Page:     derefInt(p.Page),
+		PageSize: derefInt(p.PageSize),
+	}
+	res, err := s.core.GetItemsInBBox(ctx, q)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetItemsBBoxBusiness.Error())
+		return fmt.Errorf(
+			"cannot query items in bbox: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, populateGeoItemsResponse(res), http.StatusOK)
+}
+
+func populateGeoItemsResponse(res []listUsecase.GeoItem) []GeoItemResponse {
+	is := []GeoItemResponse{}
+	for _, gi := range res {
+		i := populateItemResponse(gi.Item)
+		is = append(is, GeoItemResponse{
+			ID:          i.ID,
+			ListID:      i.ListID,
+			Name:        i.Name,
+			Description: i.Description,
+			Address:     i.Address,
+			Point:       i.Point,
+			ImagesID:    i.ImagesID,
+			Visited:     i.Visited,
+			Version:     i.Version,
+			DateCreated: i.DateCreated,
+			DateUpdated: i.DateUpdated,
+			DistanceM:   gi.Distance,
+		})
+	}
+	return is
+}
+
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
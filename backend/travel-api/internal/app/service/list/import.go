@@ -0,0 +1,152 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/geofile"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const (
+	meg8              = 8 << 20 //8Mib
+	maxImportFeatures = 1000
+)
+
+var (
+	errImportMissingName = errors.New("feature has no name")
+	errImportDuplicate   = errors.New("item with this name already exists in the list")
+	errImportCreate      = errors.New("cannot create item")
+)
+
+func (s *Service) ImportItems(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.import-items")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemValidateListUUID.Error())
+		return err
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, meg8)
+	if err := r.ParseMultipartForm(meg8); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsImportDecode.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsImportRead.Error())
+		return web.NewRequestError(
+			ErrItemsImportRead,
+			http.StatusBadRequest,
+		)
+	}
+	defer file.Close()
+	f := geofile.Format(r.FormValue("format"))
+	if f == "" {
+		f, err = geofile.FormatFromName(header.Filename)
+		if err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsImportFormat.Error())
+			return web.NewRequestError(
+				ErrItemsImportFormat,
+				http.StatusBadRequest,
+			)
+		}
+	}
+	features, err := geofile.Decode(file, f)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsImportDecode.Error())
+		return web.NewRequestError(
+			fmt.Errorf("cannot read %s file: %w", f, err),
+			http.StatusBadRequest,
+		)
+	}
+	if len(features) > maxImportFeatures {
+		s.log.Error().Str("TraceID", tID).Msg(ErrItemsImportLen.Error())
+		return web.NewRequestError(
+			ErrItemsImportLen,
+			http.StatusBadRequest,
+		)
+	}
+
+	res := ImportResponse{
+		Items:  []ItemResponse{},
+		Errors: []ImportError{},
+	}
+	nis := []listUsecase.NewItem{}
+	// index of the feature for every item to import
+	idx := []int{}
+	for _, ft := range features {
+		if ft.Err == nil && ft.Waypoint.Name == "" {
+			ft.Err = errImportMissingName
+		}
+		if ft.Err != nil {
+			res.Errors = append(res.Errors, ImportError{
+				Index: ft.Index,
+				Name:  ft.Waypoint.Name,
+				Error: ft.Err.Error(),
+			})
+			continue
+		}
+		nis = append(nis, populateImportItem(ft.Waypoint))
+		idx = append(idx, ft.Index)
+	}
+	if len(nis) > 0 {
+		results, err := s.core.ImportItems(ctx, claims.Subject, listID, nis)
+		if err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrImportItemsBusiness.Error())
+			return fmt.Errorf(
+				"cannot import items: %w",
+				web.GetResponseErrorFromBusiness(err),
+			)
+		}
+		for i, ir := range results {
+			if ir.Err == nil {
+				res.Items = append(res.Items, populateItemResponse(ir.Item))
+				continue
+			}
+			iErr := errImportCreate
+			if errors.Is(ir.Err, web.ErrAlreadyExists) {
+				iErr = errImportDuplicate
+			}
+			res.Errors = append(res.Errors, ImportError{
+				Index: idx[i],
+				Name:  ir.Item.Name,
+				Error: iErr.Error(),
+			})
+		}
+	}
+	return web.Respond(ctx, w, res, http.StatusCreated)
+}
+
+func populateImportItem(wp geofile.Waypoint) listUsecase.NewItem {
+	ni := listUsecase.NewItem{
+		Name: wp.Name,
+		Point: listUsecase.NewPoint{
+			Lat: wp.Lat,
+			Lng: wp.Lng,
+		},
+		Visited: wp.Visited,
+	}
+	if wp.Description != "" {
+		d := wp.Description
+		ni.Description = &d
+	}
+	if wp.Address != "" {
+		a := wp.Address
+		ni.Address = &a
+	}
+	return ni
+}
@@ -0,0 +1,206 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/messages"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) GetMembers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.get-members")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	res, err := s.core.GetMembers(ctx, claims.Subject, listID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetMembersBusiness.Error())
+		return fmt.Errorf(
+			"cannot query members: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ms := []MemberResponse{}
+	for _, member := range res {
+		ms = append(ms, populateMemberResponse(member))
+	}
+	return web.Respond(ctx, w, ms, http.StatusOK)
+}
+
+func (s *Service) AddMember(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.add-member")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	nm := NewMember{}
+	if err := web.Decode(r, &nm); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMemberAddValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	var email *string
+	if nm.Email != nil {
+		e := strings.ToLower(*nm.Email)
+		email = &e
+	}
+	m := listUsecase.NewMember{
+		ListID:    listID,
+		UserID:    nm.UserID,
+		Email:     email,
+		Role:      listUsecase.Role(nm.Role),
+		InvitedBy: claims.Subject,
+	}
+	inv, err := s.core.AddMember(ctx, m)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrAddMemberBusiness.Error())
+		return fmt.Errorf(
+			"cannot add member: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	msg := messages.Message{
+		ID:       tID,
+		Email:    inv.Member.Email,
+		Name:     inv.Member.Name,
+		Type:     messages.ListInvite,
+		ListID:   inv.Member.ListID,
+		ListName: inv.ListName,
+		Sender:   inv.InviterName,
+	}
+	if err := s.mq.Publish(ctx, msg); err != nil {
+		// the member is added already, the invitation only lets them know
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMemberSendMessage.Error())
+	}
+	return web.Respond(ctx, w, populateMemberResponse(inv.Member), http.StatusCreated)
+}
+
+func (s *Service) UpdateMember(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.update-member")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	um := UpdateMember{}
+	if err := web.Decode(r, &um); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMemberUpdateValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	userID, err := getUserIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMemberValidateUserUUID.Error())
+		return err
+	}
+	m := listUsecase.UpdateMember{
+		ListID:    listID,
+		UserID:    userID,
+		Role:      listUsecase.Role(um.Role),
+		UpdatedBy: claims.Subject,
+	}
+	res, err := s.core.UpdateMember(ctx, m)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUpdateMemberBusiness.Error())
+		return fmt.Errorf(
+			"cannot update member: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, populateMemberResponse(res), http.StatusOK)
+}
+
+func (s *Service) DeleteMember(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.delete-member")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	dm := struct{}{}
+	if err := web.Decode(r, &dm); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMemberDeleteValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	userID, err := getUserIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrMemberValidateUserUUID.Error())
+		return err
+	}
+	if err := s.core.DeleteMember(ctx, claims.Subject, listID, userID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteMemberBusiness.Error())
+		return fmt.Errorf(
+			"cannot delete member: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusOK)
+}
+
+func getUserIDParam(r *http.Request) (string, error) {
+	userID := web.Param(r, "userID")
+	if err := web.ValidateUUID(userID); err != nil {
+		return "", web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	return userID, nil
+}
+
+func populateMemberResponse(res listUsecase.Member) MemberResponse {
+	m := MemberResponse{
+		ListID:      res.ListID,
+		UserID:      res.UserID,
+		Name:        res.Name,
+		Email:       res.Email,
+		Role:        string(res.Role),
+		InvitedBy:   res.InvitedBy,
+		DateCreated: res.DateCreated,
+		DateUpdated: res.DateUpdated,
+	}
+	return m
+}
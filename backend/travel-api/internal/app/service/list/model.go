@@ -5,6 +5,8 @@ package list
 
 import (
 	"time"
+
+	openapi_types "github.com/deepmap/oapi-codegen/pkg/types"
 )
 
 // ErrorResponse defines model for ErrorResponse.
@@ -14,6 +16,66 @@ type ErrorResponse struct {
 	Fields *map[string]string `json:"fields,omitempty"`
 }
 
+// GeoItemResponse defines model for GeoItemResponse.
+type GeoItemResponse struct {
+	// Address item address on map
+	Address *string `json:"address,omitempty"`
+
+	// DateCreated creation date
+	DateCreated time.Time `json:"date_created"`
+
+	// DateUpdated date of last update
+	DateUpdated time.Time `json:"date_updated"`
+
+	// Description item description
+	Description *string `json:"description,omitempty"`
+
+	// DistanceM distance from the search origin in meters
+	DistanceM float64 `json:"distance_m"`
+
+	// Id item id
+	ID string `json:"id"`
+
+	// ImagesId array of attached image ids
+	ImagesID *[]string `json:"images_id,omitempty"`
+
+	// ListId item parent id
+	ListID string `json:"list_id"`
+
+	// Name item name
+	Name string `json:"name"`
+
+	// Point item location on map
+	Point PointResponse `json:"point"`
+
+	// Version item version, sent as ETag
+	Version int `json:"version"`
+
+	// Visited location is visited
+	Visited bool `json:"visited"`
+}
+
+// ImportError defines model for ImportError.
+type ImportError struct {
+	// Error reason the feature was not imported
+	Error string `json:"error"`
+
+	// Index position of the feature in the file
+	Index int `json:"index"`
+
+	// Name name of the feature
+	Name string `json:"name"`
+}
+
+// ImportResponse defines model for ImportResponse.
+type ImportResponse struct {
+	// Errors features of the file that were not imported
+	Errors []ImportError `json:"errors"`
+
+	// Items created items
+	Items []ItemResponse `json:"items"`
+}
+
 // ItemResponse defines model for ItemResponse.
 type ItemResponse struct {
 	// Address item address on map
@@ -43,10 +105,51 @@ type ItemResponse struct {
 	// Point item location on map
 	Point PointResponse `json:"point"`
 
+	// Version item version, sent as ETag
+	Version int `json:"version"`
+
 	// Visited location is visited
 	Visited bool `json:"visited"`
 }
 
+// ItemsPageResponse defines model for ItemsPageResponse.
+type ItemsPageResponse struct {
+	Items []ItemResponse `json:"items"`
+
+	// NextCursor cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Total number of items matching the filters
+	Total int `json:"total"`
+}
+
+// ListEventResponse defines model for ListEventResponse.
+type ListEventResponse struct {
+	// Date date of the change
+	Date time.Time `json:"date"`
+
+	// Id event id
+	ID string `json:"id"`
+
+	// ImageId attached image id, only for image.attached
+	ImageID *string `json:"image_id,omitempty"`
+
+	// ItemId changed item id, absent for list events
+	ItemID *string `json:"item_id,omitempty"`
+
+	// ListId list id
+	ListID string `json:"list_id"`
+
+	// Type one of item.created, item.updated, item.deleted, list.updated, list.deleted, image.attached
+	Type string `json:"type"`
+
+	// UserId id of the user who made the change
+	UserID string `json:"user_id"`
+
+	// Version version of the list or item after the change, absent for deletions
+	Version *int `json:"version,omitempty"`
+}
+
 // ListResponse defines model for ListResponse.
 type ListResponse struct {
 	// Completed is list completed
@@ -78,6 +181,47 @@ type ListResponse struct {
 
 	// UserId list owner id
 	UserID string `json:"user_id"`
+
+	// Version list version, sent as ETag
+	Version int `json:"version"`
+}
+
+// ListsPageResponse defines model for ListsPageResponse.
+type ListsPageResponse struct {
+	Lists []ListResponse `json:"lists"`
+
+	// NextCursor cursor of the next page, absent on the last page
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Total number of lists matching the filters
+	Total int `json:"total"`
+}
+
+// MemberResponse defines model for MemberResponse.
+type MemberResponse struct {
+	// DateCreated date created
+	DateCreated time.Time `json:"date_created"`
+
+	// DateUpdated date updated
+	DateUpdated time.Time `json:"date_updated"`
+
+	// Email member email
+	Email string `json:"email"`
+
+	// InvitedBy id of the user who added the member
+	InvitedBy string `json:"invited_by"`
+
+	// ListId list id
+	ListID string `json:"list_id"`
+
+	// Name member name
+	Name string `json:"name"`
+
+	// Role member role
+	Role string `json:"role"`
+
+	// UserId member user id
+	UserID string `json:"user_id"`
 }
 
 // NewItem new item object
@@ -110,6 +254,18 @@ type NewList struct {
 	Private *bool `json:"private,omitempty" validate:"omitempty,boolean"`
 }
 
+// NewMember new list member object, either user_id or email is required
+type NewMember struct {
+	// Email email of the user to invite
+	Email *string `json:"email,omitempty" validate:"required_without=UserID,omitempty,email"`
+
+	// Role member role
+	Role string `json:"role" validate:"required,oneof=viewer editor"`
+
+	// UserId id of the user to invite
+	UserID *string `json:"user_id,omitempty" validate:"required_without=Email,omitempty,uuid"`
+}
+
 // NewPoint new point object
 type NewPoint struct {
 	// Lat new point's latitude
@@ -119,6 +275,21 @@ type NewPoint struct {
 	Lng float64 `json:"lng" validate:"required,number"`
 }
 
+// OptimizeRoute route optimization options
+type OptimizeRoute struct {
+	// EndItemId item the route must end at, equal to the start for a round trip
+	EndItemID *string `json:"end_item_id,omitempty" validate:"omitempty,uuid"`
+
+	// Persist store the suggested order as the list order
+	Persist *bool `json:"persist,omitempty" validate:"omitempty,boolean"`
+
+	// StartItemId item the route must start at
+	StartItemID *string `json:"start_item_id,omitempty" validate:"omitempty,uuid"`
+
+	// UnvisitedOnly route only the items that are not visited yet
+	UnvisitedOnly *bool `json:"unvisited_only,omitempty" validate:"omitempty,boolean"`
+}
+
 // PointResponse item location on map
 type PointResponse struct {
 	// Id location id
@@ -134,6 +305,74 @@ type PointResponse struct {
 	Lng float64 `json:"lng"`
 }
 
+// PublicListResponse defines model for PublicListResponse.
+type PublicListResponse struct {
+	Items []ItemResponse `json:"items"`
+	List  ListResponse   `json:"list"`
+}
+
+// RouteResponse defines model for RouteResponse.
+type RouteResponse struct {
+	// DistanceM route length in meters
+	DistanceM float64 `json:"distance_m"`
+
+	// ItemsId item ids in the suggested visiting order
+	ItemsID []string `json:"items_id"`
+
+	// ListId list id
+	ListID string `json:"list_id"`
+}
+
+// SearchGroupResponse defines model for SearchGroupResponse.
+type SearchGroupResponse struct {
+	Items []SearchItemResponse `json:"items"`
+
+	// ListId list id
+	ListID string `json:"list_id"`
+
+	// ListName list name
+	ListName string `json:"list_name"`
+
+	// Rank best rank of the list and its items
+	Rank float64 `json:"rank"`
+
+	// Snippet matched text of the list with the matches wrapped in mark tags, absent if only items matched
+	Snippet *string `json:"snippet,omitempty"`
+}
+
+// SearchItemResponse defines model for SearchItemResponse.
+type SearchItemResponse struct {
+	// ItemId item id
+	ItemID string `json:"item_id"`
+
+	// ItemName item name
+	ItemName string `json:"item_name"`
+
+	// Rank rank of the item
+	Rank float64 `json:"rank"`
+
+	// Snippet matched text of the item with the matches wrapped in mark tags
+	Snippet string `json:"snippet"`
+}
+
+// ShareResponse defines model for ShareResponse.
+type ShareResponse struct {
+	// CreatedBy id of the user who created the link
+	CreatedBy string `json:"created_by"`
+
+	// DateCreated date created
+	DateCreated time.Time `json:"date_created"`
+
+	// Id share link id
+	ID string `json:"id"`
+
+	// ListId list id
+	ListID string `json:"list_id"`
+
+	// Token share token, used as slug of the public list, returned only when the link is created
+	Token *string `json:"token,omitempty"`
+}
+
 // UpdateItem update item object
 type UpdateItem struct {
 	// Address updated item address
@@ -176,6 +415,12 @@ type UpdateList struct {
 	Private *bool `json:"private,omitempty" validate:"omitempty,boolean"`
 }
 
+// UpdateMember update list member object
+type UpdateMember struct {
+	// Role member role
+	Role string `json:"role" validate:"required,oneof=viewer editor"`
+}
+
 // UpdatePoint new point object
 type UpdatePoint struct {
 	// Lat new point's latitude
@@ -185,12 +430,210 @@ type UpdatePoint struct {
 	Lng float64 `json:"lng" validate:"required,number"`
 }
 
+// DeleteAdminListsListIdJSONBody defines parameters for DeleteAdminListsListId.
+type DeleteAdminListsListIdJSONBody = map[string]interface{}
+
+// DeleteAdminListsListIdParams defines parameters for DeleteAdminListsListId.
+type DeleteAdminListsListIdParams struct {
+	// IfMatch version of the list the change is based on, 412 is returned if it is stale
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// DeleteAdminListsListIdItemsItemIdJSONBody defines parameters for DeleteAdminListsListIdItemsItemId.
+type DeleteAdminListsListIdItemsItemIdJSONBody = map[string]interface{}
+
+// DeleteAdminListsListIdItemsItemIdParams defines parameters for DeleteAdminListsListIdItemsItemId.
+type DeleteAdminListsListIdItemsItemIdParams struct {
+	// IfMatch version of the item the change is based on, 412 is returned if it is stale
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetAdminUsersIdListsParams defines parameters for GetAdminUsersIdLists.
+type GetAdminUsersIdListsParams struct {
+	// Limit page size, 20 by default
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+
+	// Cursor cursor of the next page returned with the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort sort key, date_created by default
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty" validate:"omitempty,oneof=date_created date_updated name"`
+
+	// Order sort direction, asc or desc, asc by default
+	Order *string `form:"order,omitempty" json:"order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// Favorite only favorite or not favorite lists
+	Favorite *bool `form:"favorite,omitempty" json:"favorite,omitempty"`
+
+	// Completed only completed or not completed lists
+	Completed *bool `form:"completed,omitempty" json:"completed,omitempty"`
+
+	// Private only private or public lists
+	Private *bool `form:"private,omitempty" json:"private,omitempty"`
+}
+
+// GetItemsBboxParams defines parameters for GetItemsBbox.
+type GetItemsBboxParams struct {
+	// MinLat south edge of the box
+	MinLat float64 `form:"min_lat" json:"min_lat" validate:"gte=-90,lte=90"`
+
+	// MinLng west edge of the box
+	MinLng float64 `form:"min_lng" json:"min_lng" validate:"gte=-180,lte=180"`
+
+	// MaxLat north edge of the box
+	MaxLat float64 `form:"max_lat" json:"max_lat" validate:"gte=-90,lte=90"`
+
+	// MaxLng east edge of the box
+	MaxLng float64 `form:"max_lng" json:"max_lng" validate:"gte=-180,lte=180"`
+
+	// Page page number, starts from 1
+	Page *int `form:"page,omitempty" json:"page,omitempty" validate:"omitempty,gte=1"`
+
+	// PageSize page size, 100 at most
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty" validate:"omitempty,gte=1,lte=100"`
+}
+
+// GetItemsNearParams defines parameters for GetItemsNear.
+type GetItemsNearParams struct {
+	// Lat latitude of the search origin
+	Lat float64 `form:"lat" json:"lat" validate:"gte=-90,lte=90"`
+
+	// Lng longitude of the search origin
+	Lng float64 `form:"lng" json:"lng" validate:"gte=-180,lte=180"`
+
+	// RadiusM search radius in meters
+	RadiusM float64 `form:"radius_m" json:"radius_m" validate:"required,gt=0,lte=100000"`
+
+	// Page page number, starts from 1
+	Page *int `form:"page,omitempty" json:"page,omitempty" validate:"omitempty,gte=1"`
+
+	// PageSize page size, 100 at most
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty" validate:"omitempty,gte=1,lte=100"`
+}
+
+// GetListsParams defines parameters for GetLists.
+type GetListsParams struct {
+	// Limit page size, 20 by default
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+
+	// Cursor cursor of the next page returned with the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort sort key, date_created by default
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty" validate:"omitempty,oneof=date_created date_updated name"`
+
+	// Order sort direction, asc or desc, asc by default
+	Order *string `form:"order,omitempty" json:"order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// Favorite only favorite or not favorite lists
+	Favorite *bool `form:"favorite,omitempty" json:"favorite,omitempty"`
+
+	// Completed only completed or not completed lists
+	Completed *bool `form:"completed,omitempty" json:"completed,omitempty"`
+
+	// Private only private or public lists
+	Private *bool `form:"private,omitempty" json:"private,omitempty"`
+}
+
 // DeleteListsListIdJSONBody defines parameters for DeleteListsListId.
 type DeleteListsListIdJSONBody = map[string]interface{}
 
+// DeleteListsListIdParams defines parameters for DeleteListsListId.
+type DeleteListsListIdParams struct {
+	// IfMatch version of the list the change is based on, 412 is returned if it is stale
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetListsListIdParams defines parameters for GetListsListId.
+type GetListsListIdParams struct {
+	// IfNoneMatch version of the list the client has, 304 is returned if it is current
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// PutListsListIdParams defines parameters for PutListsListId.
+type PutListsListIdParams struct {
+	// IfMatch version of the list the change is based on, 412 is returned if it is stale
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetListsListIdExportParams defines parameters for GetListsListIdExport.
+type GetListsListIdExportParams struct {
+	// Format file format of the export
+	Format string `form:"format" json:"format" validate:"required,oneof=gpx kml geojson"`
+}
+
+// PostListsListIdImportMultipartBody defines parameters for PostListsListIdImport.
+type PostListsListIdImportMultipartBody struct {
+	// File gpx, kml or geojson file with the items to import
+	File openapi_types.File `json:"file"`
+
+	// Format file format, guessed by the file extension when omitted
+	Format *string `json:"format,omitempty"`
+}
+
+// GetListsListIdItemsParams defines parameters for GetListsListIdItems.
+type GetListsListIdItemsParams struct {
+	// Limit page size, 20 by default
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+
+	// Cursor cursor of the next page returned with the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Sort sort key, order of the list by default
+	Sort *string `form:"sort,omitempty" json:"sort,omitempty" validate:"omitempty,oneof=date_created date_updated name order"`
+
+	// Order sort direction, asc or desc, asc by default
+	Order *string `form:"order,omitempty" json:"order,omitempty" validate:"omitempty,oneof=asc desc"`
+
+	// Visited only visited or not visited items
+	Visited *bool `form:"visited,omitempty" json:"visited,omitempty"`
+}
+
 // DeleteListsListIdItemsItemIdJSONBody defines parameters for DeleteListsListIdItemsItemId.
 type DeleteListsListIdItemsItemIdJSONBody = map[string]interface{}
 
+// DeleteListsListIdItemsItemIdParams defines parameters for DeleteListsListIdItemsItemId.
+type DeleteListsListIdItemsItemIdParams struct {
+	// IfMatch version of the item the change is based on, 412 is returned if it is stale
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetListsListIdItemsItemIdParams defines parameters for GetListsListIdItemsItemId.
+type GetListsListIdItemsItemIdParams struct {
+	// IfNoneMatch version of the item the client has, 304 is returned if it is current
+	IfNoneMatch *string `json:"If-None-Match,omitempty"`
+}
+
+// PutListsListIdItemsItemIdParams defines parameters for PutListsListIdItemsItemId.
+type PutListsListIdItemsItemIdParams struct {
+	// IfMatch version of the item the change is based on, 412 is returned if it is stale
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// DeleteListsListIdMembersUserIdJSONBody defines parameters for DeleteListsListIdMembersUserId.
+type DeleteListsListIdMembersUserIdJSONBody = map[string]interface{}
+
+// PostListsListIdSharesJSONBody defines parameters for PostListsListIdShares.
+type PostListsListIdSharesJSONBody = map[string]interface{}
+
+// DeleteListsListIdSharesShareIdJSONBody defines parameters for DeleteListsListIdSharesShareId.
+type DeleteListsListIdSharesShareIdJSONBody = map[string]interface{}
+
+// GetSearchParams defines parameters for GetSearch.
+type GetSearchParams struct {
+	// Q search words, quoted phrases and -excluded words are supported
+	Q string `form:"q" json:"q" validate:"required,gte=1,lte=256"`
+
+	// Limit max number of matches, 20 by default
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty" validate:"omitempty,gte=1,lte=100"`
+}
+
+// DeleteAdminListsListIdJSONRequestBody defines body for DeleteAdminListsListId for application/json ContentType.
+type DeleteAdminListsListIdJSONRequestBody = DeleteAdminListsListIdJSONBody
+
+// DeleteAdminListsListIdItemsItemIdJSONRequestBody defines body for DeleteAdminListsListIdItemsItemId for application/json ContentType.
+type DeleteAdminListsListIdItemsItemIdJSONRequestBody = DeleteAdminListsListIdItemsItemIdJSONBody
+
 // PostListsJSONRequestBody defines body for PostLists for application/json ContentType.
 type PostListsJSONRequestBody = NewList
 
@@ -200,6 +643,9 @@ type DeleteListsListIdJSONRequestBody = DeleteListsListIdJSONBody
 // PutListsListIdJSONRequestBody defines body for PutListsListId for application/json ContentType.
 type PutListsListIdJSONRequestBody = UpdateList
 
+// PostListsListIdImportMultipartRequestBody defines body for PostListsListIdImport for multipart/form-data ContentType.
+type PostListsListIdImportMultipartRequestBody PostListsListIdImportMultipartBody
+
 // PostListsListIdItemsJSONRequestBody defines body for PostListsListIdItems for application/json ContentType.
 type PostListsListIdItemsJSONRequestBody = NewItem
 
@@ -208,3 +654,21 @@ type DeleteListsListIdItemsItemIdJSONRequestBody = DeleteListsListIdItemsItemIdJ
 
 // PutListsListIdItemsItemIdJSONRequestBody defines body for PutListsListIdItemsItemId for application/json ContentType.
 type PutListsListIdItemsItemIdJSONRequestBody = UpdateItem
+
+// PostListsListIdMembersJSONRequestBody defines body for PostListsListIdMembers for application/json ContentType.
+type PostListsListIdMembersJSONRequestBody = NewMember
+
+// DeleteListsListIdMembersUserIdJSONRequestBody defines body for DeleteListsListIdMembersUserId for application/json ContentType.
+type DeleteListsListIdMembersUserIdJSONRequestBody = DeleteListsListIdMembersUserIdJSONBody
+
+// PutListsListIdMembersUserIdJSONRequestBody defines body for PutListsListIdMembersUserId for application/json ContentType.
+type PutListsListIdMembersUserIdJSONRequestBody = UpdateMember
+
+// PostListsListIdOptimizeJSONRequestBody defines body for PostListsListIdOptimize for application/json ContentType.
+type PostListsListIdOptimizeJSONRequestBody = OptimizeRoute
+
+// PostListsListIdSharesJSONRequestBody defines body for PostListsListIdShares for application/json ContentType.
+type PostListsListIdSharesJSONRequestBody = PostListsListIdSharesJSONBody
+
+// DeleteListsListIdSharesShareIdJSONRequestBody defines body for DeleteListsListIdSharesShareId for application/json ContentType.
+type DeleteListsListIdSharesShareIdJSONRequestBody = DeleteListsListIdSharesShareIdJSONBody
@@ -0,0 +1,89 @@
+package list
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// layout of a timestamp cursor value as it is printed by the database
+const cursorTimeLayout = "2006-01-02 15:04:05.999999"
+
+var (
+	errCursorInvalid      = errors.New("is invalid")
+	errCursorSortMismatch = errors.New("was issued for another sort")
+)
+
+// cursor is an opaque page cursor, it is bound to the sort it was issued for.
+type cursor struct {
+	Sort  string `json:"s"`
+	Desc  bool   `json:"d,omitempty"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+func encodeCursor(p listUsecase.Page, c *listUsecase.Cursor) *string {
+	if c == nil {
+		return nil
+	}
+	b, err := json.Marshal(cursor{Sort: p.Sort, Desc: p.Desc, Value: c.Value, ID: c.ID})
+	if err != nil {
+		return nil
+	}
+	s := base64.RawURLEncoding.EncodeToString(b)
+	return &s
+}
+
+func decodeCursor(p listUsecase.Page, s string) (*listUsecase.Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errCursorInvalid
+	}
+	c := cursor{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return nil, errCursorInvalid
+	}
+	if c.Sort != p.Sort || c.Desc != p.Desc {
+		return nil, errCursorSortMismatch
+	}
+	if err := web.ValidateUUID(c.ID); err != nil {
+		return nil, errCursorInvalid
+	}
+	switch c.Sort {
+	case listUsecase.SortDateCreated, listUsecase.SortDateUpdated:
+		_, err = time.Parse(cursorTimeLayout, c.Value)
+	case listUsecase.SortOrder:
+		_, err = strconv.Atoi(c.Value)
+	}
+	if err != nil {
+		return nil, errCursorInvalid
+	}
+	return &listUsecase.Cursor{Value: c.Value, ID: c.ID}, nil
+}
+
+// newPage resolves the page of a collection query, sort falls back to def.
+func newPage(limit *int, cur *string, sort *string, order *string, def string) (listUsecase.Page, error) {
+	p := listUsecase.Page{Sort: def}
+	if limit != nil {
+		p.Limit = *limit
+	}
+	if sort != nil {
+		p.Sort = *sort
+	}
+	if order != nil {
+		p.Desc = *order == "desc"
+	}
+	if cur != nil {
+		c, err := decodeCursor(p, *cur)
+		if err != nil {
+			return listUsecase.Page{}, web.NewFieldsError("cursor", err)
+		}
+		p.Cursor = c
+	}
+	return p, nil
+}
@@ -0,0 +1,57 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) OptimizeRoute(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.optimize-route")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	or := OptimizeRoute{}
+	if err := web.Decode(r, &or); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrRouteOptimizeValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	o := listUsecase.OptimizeRoute{
+		ListID:        listID,
+		UserID:        claims.Subject,
+		StartItemID:   or.StartItemID,
+		EndItemID:     or.EndItemID,
+		UnvisitedOnly: or.UnvisitedOnly != nil && *or.UnvisitedOnly,
+		Persist:       or.Persist != nil && *or.Persist,
+	}
+	res, err := s.core.OptimizeRoute(ctx, o)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrOptimizeRouteBusiness.Error())
+		return fmt.Errorf(
+			"cannot optimize route: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	rr := RouteResponse{
+		ListID:    res.ListID,
+		ItemsID:   res.ItemsID,
+		DistanceM: res.Distance,
+	}
+	return web.Respond(ctx, w, rr, http.StatusOK)
+}
@@ -0,0 +1,99 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) Search(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.search")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetSearchParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSearchValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	q := listUsecase.SearchQuery{
+		UserID: claims.Subject,
+		Query:  p.Q,
+		Limit:  derefInt(p.Limit),
+	}
+	res, err := s.core.Search(ctx, q)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrSearchBusiness.Error())
+		return fmt.Errorf(
+			"cannot search: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	gs := []SearchGroupResponse{}
+	for _, g := range res {
+		gs = append(gs, populateSearchGroupResponse(g))
+	}
+	return web.Respond(ctx, w, gs, http.StatusOK)
+}
+
+func populateSearchGroupResponse(g listUsecase.SearchGroup) SearchGroupResponse {
+	gr := SearchGroupResponse{
+		ListID:   g.ListID,
+		ListName: g.ListName,
+		Rank:     g.Rank,
+		Items:    []SearchItemResponse{},
+	}
+	if g.Snippet != nil {
+		snippet := escapeSnippet(*g.Snippet)
+		gr.Snippet = &snippet
+	}
+	for _, m := range g.Items {
+		ir := SearchItemResponse{
+			Rank:    m.Rank,
+			Snippet: escapeSnippet(m.Snippet),
+		}
+		if m.ItemID != nil {
+			ir.ItemID = *m.ItemID
+		}
+		if m.ItemName != nil {
+			ir.ItemName = *m.ItemName
+		}
+		gr.Items = append(gr.Items, ir)
+	}
+	return gr
+}
+
+// escapeSnippet escapes user text of a snippet so it is safe to render
+// as html, only the match markers are left as tags.
+func escapeSnippet(s string) string {
+	b := strings.Builder{}
+	for {
+		before, rest, found := strings.Cut(s, listUsecase.SearchStartSel)
+		b.WriteString(html.EscapeString(before))
+		if !found {
+			return b.String()
+		}
+		match, after, found := strings.Cut(rest, listUsecase.SearchStopSel)
+		if !found {
+			b.WriteString(html.EscapeString(rest))
+			return b.String()
+		}
+		b.WriteString(listUsecase.SearchStartSel)
+		b.WriteString(html.EscapeString(match))
+		b.WriteString(listUsecase.SearchStopSel)
+		s = after
+	}
+}
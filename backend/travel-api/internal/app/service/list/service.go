@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 
+	queue "github.com/f4mk/travel/backend/pkg/mb"
 	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
@@ -14,17 +15,19 @@ import (
 type Service struct {
 	core *listUsecase.Core
 	log  *zerolog.Logger
+	mq   *queue.Channel
 }
 
-func NewService(l *zerolog.Logger, c *listUsecase.Core) *Service {
+func NewService(l *zerolog.Logger, c *listUsecase.Core, mq *queue.Channel) *Service {
 
 	return &Service{
 		core: c,
 		log:  l,
+		mq:   mq,
 	}
 }
 
-func (s *Service) GetLists(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+func (s *Service) GetLists(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	ctx, span := web.AddSpan(ctx, "service.list.get-lists")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
@@ -33,7 +36,51 @@ func (s *Service) GetLists(ctx context.Context, w http.ResponseWriter, _ *http.R
 		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
 		return auth.ErrGetClaims
 	}
-	res, err := s.core.GetAllLists(ctx, claims.Subject)
+	return s.getLists(ctx, w, r, claims.Subject)
+}
+
+// GetUserLists returns a page of the lists of the user in the path to admins.
+func (s *Service) GetUserLists(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.get-user-lists")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	userID := web.Param(r, "id")
+	if err := web.ValidateUUID(userID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListsValidateUserUUID.Error())
+		return web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	return s.getLists(ctx, w, r, userID)
+}
+
+func (s *Service) getLists(ctx context.Context, w http.ResponseWriter, r *http.Request, userID string) error {
+	tID := web.GetTraceID(ctx)
+	p := GetListsParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListsQueryValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	page, err := newPage(p.Limit, p.Cursor, p.Sort, p.Order, listUsecase.SortDateCreated)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListsQueryValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	q := listUsecase.ListsQuery{
+		UserID:    userID,
+		Favorite:  p.Favorite,
+		Completed: p.Completed,
+		Private:   p.Private,
+		Page:      page,
+	}
+	res, err := s.core.GetAllLists(ctx, q)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetListsBusiness.Error())
 		return fmt.Errorf(
@@ -41,12 +88,16 @@ func (s *Service) GetLists(ctx context.Context, w http.ResponseWriter, _ *http.R
 			web.GetResponseErrorFromBusiness(err),
 		)
 	}
-	ls := []ListResponse{}
-	for _, list := range res {
+	lp := ListsPageResponse{
+		Lists:      []ListResponse{},
+		NextCursor: encodeCursor(page, res.NextCursor),
+		Total:      res.Total,
+	}
+	for _, list := range res.Lists {
 		l := populateListResponse(list)
-		ls = append(ls, l)
+		lp.Lists = append(lp.Lists, l)
 	}
-	return web.Respond(ctx, w, ls, http.StatusOK)
+	return web.Respond(ctx, w, lp, http.StatusOK)
 }
 
 func (s *Service) GetList(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -72,6 +123,9 @@ func (s *Service) GetList(ctx context.Context, w http.ResponseWriter, r *http.Re
 		)
 	}
 	ls := populateListResponse(res)
+	if web.NoneMatch(r, ls.ETag()) {
+		return web.Respond(ctx, w, ls, http.StatusNotModified)
+	}
 	return web.Respond(ctx, w, ls, http.StatusOK)
 }
 
@@ -79,6 +133,22 @@ func (s *Service) GetItems(ctx context.Context, w http.ResponseWriter, r *http.R
 	ctx, span := web.AddSpan(ctx, "service.list.get-items")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
+	p := GetListsListIdItemsParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsQueryValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	page, err := newPage(p.Limit, p.Cursor, p.Sort, p.Order, listUsecase.SortOrder)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemsQueryValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
 	claims, err := auth.GetClaims(ctx)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
@@ -89,7 +159,12 @@ func (s *Service) GetItems(ctx context.Context, w http.ResponseWriter, r *http.R
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
 		return err
 	}
-	res, err := s.core.GetItemsByListID(ctx, claims.Subject, listID)
+	q := listUsecase.ItemsQuery{
+		ListID:  listID,
+		Visited: p.Visited,
+		Page:    page,
+	}
+	res, err := s.core.GetItemsByListID(ctx, claims.Subject, q)
 	if err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetListsBusiness.Error())
 		return fmt.Errorf(
@@ -97,12 +172,16 @@ func (s *Service) GetItems(ctx context.Context, w http.ResponseWriter, r *http.R
 			web.GetResponseErrorFromBusiness(err),
 		)
 	}
-	is := []ItemResponse{}
-	for _, item := range res {
+	ip := ItemsPageResponse{
+		Items:      []ItemResponse{},
+		NextCursor: encodeCursor(page, res.NextCursor),
+		Total:      res.Total,
+	}
+	for _, item := range res.Items {
 		i := populateItemResponse(item)
-		is = append(is, i)
+		ip.Items = append(ip.Items, i)
 	}
-	return web.Respond(ctx, w, is, http.StatusOK)
+	return web.Respond(ctx, w, ip, http.StatusOK)
 }
 
 func (s *Service) GetItem(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
@@ -128,6 +207,9 @@ func (s *Service) GetItem(ctx context.Context, w http.ResponseWriter, r *http.Re
 		)
 	}
 	i := populateItemResponse(res)
+	if web.NoneMatch(r, i.ETag()) {
+		return web.Respond(ctx, w, i, http.StatusNotModified)
+	}
 	return web.Respond(ctx, w, i, http.StatusOK)
 }
 
@@ -188,6 +270,14 @@ func (s *Service) UpdateList(ctx context.Context, w http.ResponseWriter, r *http
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
 		return err
 	}
+	ifMatch, err := web.IfMatch(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListUpdateValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
 	var isID []string
 	if ul.ItemsID != nil {
 		isID = *ul.ItemsID
@@ -201,6 +291,7 @@ func (s *Service) UpdateList(ctx context.Context, w http.ResponseWriter, r *http
 		Favorite:    ul.Favorite,
 		Completed:   ul.Completed,
 		ItemsID:     isID,
+		IfMatch:     ifMatch,
 	}
 
 	res, err := s.core.UpdateList(ctx, l)
@@ -237,7 +328,15 @@ func (s *Service) DeleteList(ctx context.Context, w http.ResponseWriter, r *http
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
 		return err
 	}
-	if err := s.core.DeleteList(ctx, claims.Subject, listID); err != nil {
+	ifMatch, err := web.IfMatch(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListDeleteValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.core.DeleteList(ctx, claims.Subject, listID, ifMatch); err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteListBusiness.Error())
 		return fmt.Errorf(
 			"cannot delete list: %w",
@@ -325,6 +424,14 @@ func (s *Service) UpdateItem(ctx context.Context, w http.ResponseWriter, r *http
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemValidateItemUUID.Error())
 		return err
 	}
+	ifMatch, err := web.IfMatch(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemUpdateValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
 	var up *listUsecase.UpdatePoint
 	if ui.Point != nil {
 		up = &listUsecase.UpdatePoint{
@@ -346,6 +453,7 @@ func (s *Service) UpdateItem(ctx context.Context, w http.ResponseWriter, r *http
 		Point:       up,
 		ImagesID:    imgsID,
 		Visited:     ui.Visited,
+		IfMatch:     ifMatch,
 	}
 	res, err := s.core.UpdateItem(ctx, i)
 	if err != nil {
@@ -381,7 +489,15 @@ func (s *Service) DeleteItem(ctx context.Context, w http.ResponseWriter, r *http
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemValidateItemUUID.Error())
 		return err
 	}
-	if err := s.core.DeleteItem(ctx, claims.Subject, itemID); err != nil {
+	ifMatch, err := web.IfMatch(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrItemDeleteValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.core.DeleteItem(ctx, claims.Subject, itemID, ifMatch); err != nil {
 		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteItemBusiness.Error())
 		return fmt.Errorf(
 			"cannot delete item: %w",
@@ -422,6 +538,7 @@ func populateListResponse(res listUsecase.List) ListResponse {
 		Private:     res.Private,
 		Completed:   res.Completed,
 		ItemsID:     &res.ItemsID,
+		Version:     res.Version,
 		DateCreated: res.DateCreated,
 		DateUpdated: &res.DateUpdated,
 	}
@@ -443,6 +560,7 @@ func populateItemResponse(res listUsecase.Item) ItemResponse {
 		},
 		ImagesID:    &res.ImagesID,
 		Visited:     res.Visited,
+		Version:     res.Version,
 		DateCreated: res.DateCreated,
 		DateUpdated: res.DateUpdated,
 	}
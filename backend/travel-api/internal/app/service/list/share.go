@@ -0,0 +1,150 @@
+package list
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	listUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/list"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (s *Service) GetPublicList(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.get-public-list")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	slug := web.Param(r, "slug")
+	list, items, err := s.core.GetPublicList(ctx, slug)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetPublicListBusiness.Error())
+		return fmt.Errorf(
+			"cannot query public list: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	is := []ItemResponse{}
+	for _, item := range items {
+		is = append(is, populateItemResponse(item))
+	}
+	pl := PublicListResponse{
+		List:  populateListResponse(list),
+		Items: is,
+	}
+	return web.Respond(ctx, w, pl, http.StatusOK)
+}
+
+func (s *Service) GetShares(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.get-shares")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	res, err := s.core.GetShares(ctx, claims.Subject, listID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetSharesBusiness.Error())
+		return fmt.Errorf(
+			"cannot query shares: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ss := []ShareResponse{}
+	for _, share := range res {
+		ss = append(ss, populateShareResponse(share))
+	}
+	return web.Respond(ctx, w, ss, http.StatusOK)
+}
+
+func (s *Service) CreateShare(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.create-share")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	cs := struct{}{}
+	if err := web.Decode(r, &cs); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrShareCreateValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	res, err := s.core.CreateShare(ctx, claims.Subject, listID)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrCreateShareBusiness.Error())
+		return fmt.Errorf(
+			"cannot create share: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, populateShareResponse(res), http.StatusCreated)
+}
+
+func (s *Service) DeleteShare(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.list.delete-share")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ds := struct{}{}
+	if err := web.Decode(r, &ds); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrShareDeleteValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	listID, err := getListIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrListValidateListUUID.Error())
+		return err
+	}
+	shareID := web.Param(r, "shareID")
+	if err := web.ValidateUUID(shareID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrShareValidateShareUUID.Error())
+		return web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.core.DeleteShare(ctx, claims.Subject, listID, shareID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteShareBusiness.Error())
+		return fmt.Errorf(
+			"cannot delete share: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusOK)
+}
+
+func populateShareResponse(res listUsecase.Share) ShareResponse {
+	s := ShareResponse{
+		ID:          res.ID,
+		ListID:      res.ListID,
+		CreatedBy:   res.CreatedBy,
+		DateCreated: res.DateCreated,
+	}
+	if res.Token != "" {
+		s.Token = &res.Token
+	}
+	return s
+}
@@ -1,6 +1,10 @@
 package list
 
-import "github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+import (
+	"errors"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
 
 func (nl NewList) Validate() error {
 	return web.Check(nl)
@@ -25,3 +29,48 @@ func (ui UpdateItem) Validate() error {
 func (up UpdatePoint) Validate() error {
 	return web.Check(up)
 }
+
+func (nm NewMember) Validate() error {
+	return web.Check(nm)
+}
+
+func (um UpdateMember) Validate() error {
+	return web.Check(um)
+}
+
+func (or OptimizeRoute) Validate() error {
+	return web.Check(or)
+}
+
+func (p GetListsParams) Validate() error {
+	return web.Check(p)
+}
+
+func (p GetListsListIdItemsParams) Validate() error {
+	return web.Check(p)
+}
+
+func (p GetSearchParams) Validate() error {
+	return web.Check(p)
+}
+
+func (p GetListsListIdExportParams) Validate() error {
+	return web.Check(p)
+}
+
+func (p GetItemsNearParams) Validate() error {
+	return web.Check(p)
+}
+
+func (p GetItemsBboxParams) Validate() error {
+	if err := web.Check(p); err != nil {
+		return err
+	}
+	if p.MaxLat < p.MinLat {
+		return web.NewFieldsError("max_lat", errors.New("must not be less than min_lat"))
+	}
+	if p.MaxLng < p.MinLng {
+		return web.NewFieldsError("max_lng", errors.New("must not be less than min_lng"))
+	}
+	return nil
+}
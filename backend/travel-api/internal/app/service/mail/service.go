@@ -75,20 +75,64 @@ func (s *Service) Serve(ctx context.Context, errMsgCh chan<- ServeError, errServ
 				}
 			}
 
-			if m.Type == messages.ResetPassword {
+			switch m.Type {
+			case messages.ResetPassword:
 				mReset := mailUsecase.MessageReset{
 					Email:      strings.ToLower(m.Email),
 					Name:       m.Name,
 					ResetToken: m.Token,
 				}
 				err = s.core.SendResetMessage(ctx, mReset)
-			} else if m.Type == messages.RegisterVerify {
+			case messages.RegisterVerify:
 				mVerify := mailUsecase.MessageVerify{
 					Email:       strings.ToLower(m.Email),
 					Name:        m.Name,
 					VerifyToken: m.Token,
 				}
 				err = s.core.SendVerifyMessage(ctx, mVerify)
+			case messages.ListInvite:
+				mInvite := mailUsecase.MessageInvite{
+					Email:    strings.ToLower(m.Email),
+					Name:     m.Name,
+					ListID:   m.ListID,
+					ListName: m.ListName,
+					Sender:   m.Sender,
+				}
+				err = s.core.SendInviteMessage(ctx, mInvite)
+			case messages.LoginUnlock:
+				mUnlock := mailUsecase.MessageUnlock{
+					Email:       strings.ToLower(m.Email),
+					Name:        m.Name,
+					UnlockToken: m.Token,
+				}
+				err = s.core.SendUnlockMessage(ctx, mUnlock)
+			case messages.EmailChangeConfirm:
+				mConfirm := mailUsecase.MessageEmailConfirm{
+					Email:        strings.ToLower(m.Email),
+					Name:         m.Name,
+					ConfirmToken: m.Token,
+				}
+				err = s.core.SendEmailConfirmMessage(ctx, mConfirm)
+			case messages.EmailChangeNotice:
+				mNotice := mailUsecase.MessageEmailNotice{
+					Email: strings.ToLower(m.Email),
+					Name:  m.Name,
+				}
+				err = s.core.SendEmailNoticeMessage(ctx, mNotice)
+			case messages.MagicLink:
+				mMagic := mailUsecase.MessageMagicLink{
+					Email:      strings.ToLower(m.Email),
+					Name:       m.Name,
+					LoginToken: m.Token,
+				}
+				err = s.core.SendMagicLinkMessage(ctx, mMagic)
+			case messages.DataExportReady:
+				mExport := mailUsecase.MessageDataExport{
+					Email:         strings.ToLower(m.Email),
+					Name:          m.Name,
+					DownloadToken: m.Token,
+				}
+				err = s.core.SendDataExportMessage(ctx, mExport)
 			}
 			// process the letter
 			if err != nil {
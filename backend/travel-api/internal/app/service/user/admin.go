@@ -0,0 +1,175 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// GetUsers returns a page of all users to admins.
+func (s *Service) GetUsers(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.get-users")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	p := GetAdminUsersParams{}
+	if err := web.DecodeQuery(r, &p); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetUsersValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	q := userUsecase.UsersQuery{
+		IsActive:  p.IsActive,
+		IsDeleted: p.IsDeleted,
+	}
+	if p.Search != nil {
+		q.Search = *p.Search
+	}
+	if p.Page != nil {
+		q.Page = *p.Page
+	}
+	if p.PageSize != nil {
+		q.PageSize = *p.PageSize
+	}
+	res, err := s.core.QueryAll(ctx, q)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetUsersBusiness.Error())
+		return fmt.Errorf(
+			"cannot get users: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	up := UsersPageResponse{
+		Users: make([]AdminUserResponse, 0, len(res.Users)),
+		Total: res.Total,
+	}
+	for _, u := range res.Users {
+		up.Users = append(up.Users, populateAdminUserResponse(u))
+	}
+	return web.Respond(ctx, w, up, http.StatusOK)
+}
+
+// GetUserAdmin returns the user along with the account state.
+func (s *Service) GetUserAdmin(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.get-user-admin")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	id, err := getUserIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrAdminValidateUserID.Error())
+		return err
+	}
+	res, err := s.core.QueryByID(ctx, id)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetUserBusiness.Error())
+		return fmt.Errorf(
+			"cannot get user: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, populateAdminUserResponse(res), http.StatusOK)
+}
+
+func (s *Service) ActivateUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.activate-user")
+	defer span.End()
+	return s.updateAccount(ctx, w, r, func(ctx context.Context, id string) (userUsecase.User, error) {
+		return s.core.SetActive(ctx, id, true)
+	})
+}
+
+func (s *Service) DeactivateUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.deactivate-user")
+	defer span.End()
+	return s.updateAccount(ctx, w, r, func(ctx context.Context, id string) (userUsecase.User, error) {
+		return s.core.SetActive(ctx, id, false)
+	})
+}
+
+// DeleteUserAdmin marks the user deleted, unlike DeleteUser
+// it needs no password and the user can be restored.
+func (s *Service) DeleteUserAdmin(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.delete-user-admin")
+	defer span.End()
+	return s.updateAccount(ctx, w, r, s.core.SoftDelete)
+}
+
+func (s *Service) RestoreUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.restore-user")
+	defer span.End()
+	return s.updateAccount(ctx, w, r, s.core.Restore)
+}
+
+func (s *Service) UpdateUserRoles(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.update-user-roles")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ur := UpdateUserRoles{}
+	if err := web.Decode(r, &ur); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrUpdateRolesValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	return s.updateAccount(ctx, w, r, func(ctx context.Context, id string) (userUsecase.User, error) {
+		return s.core.UpdateRoles(ctx, id, ur.Roles)
+	})
+}
+
+// updateAccount runs the account change on the user of the path
+// and rejects the tokens of the user if the change logged them out.
+func (s *Service) updateAccount(
+	ctx context.Context,
+	w http.ResponseWriter,
+	r *http.Request,
+	change func(ctx context.Context, id string) (userUsecase.User, error),
+) error {
+	tID := web.GetTraceID(ctx)
+	id, err := getUserIDParam(r)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrAdminValidateUserID.Error())
+		return err
+	}
+	res, err := change(ctx, id)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrAdminUpdateBusiness.Error())
+		return fmt.Errorf(
+			"cannot update user: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	if err := s.auth.StoreUserTokenVersion(ctx, res.ID, res.TokenVersion); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrAdminStoreTokenVersion.Error())
+		return ErrAdminStoreTokenVersion
+	}
+	return web.Respond(ctx, w, populateAdminUserResponse(res), http.StatusOK)
+}
+
+func getUserIDParam(r *http.Request) (string, error) {
+	id := web.Param(r, "id")
+	if err := web.ValidateUUID(id); err != nil {
+		return "", web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	return id, nil
+}
+
+func populateAdminUserResponse(u userUsecase.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:          u.ID,
+		Name:        u.Name,
+		Email:       u.Email,
+		IsActive:    u.IsActive,
+		IsDeleted:   u.IsDeleted,
+		Roles:       u.Roles,
+		DateCreated: u.DateCreated,
+		DateUpdated: u.DateUpdated,
+	}
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/messages"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// ChangeEmail sends a confirmation link to the new email and a notice
+// to the current one, the email is changed by ConfirmEmail.
+func (s *Service) ChangeEmail(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.change-email")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	ce := ChangeEmail{}
+	if err := web.Decode(r, &ce); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrChangeEmailValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	email := strings.ToLower(ce.Email)
+	uce := userUsecase.ChangeEmail{
+		UserID:   claims.Subject,
+		Email:    email,
+		Password: ce.Password,
+	}
+	user, token, err := s.core.RequestEmailChange(ctx, uce)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrChangeEmailBusiness.Error())
+		return fmt.Errorf(
+			"cannot change email: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ms := []messages.Message{
+		{
+			ID:    tID,
+			Email: email,
+			Name:  user.Name,
+			Token: token,
+			Type:  messages.EmailChangeConfirm,
+		},
+		{
+			ID:    tID,
+			Email: user.Email,
+			Name:  user.Name,
+			Type:  messages.EmailChangeNotice,
+		},
+	}
+	for _, m := range ms {
+		if err := s.mq.Publish(ctx, m); err != nil {
+			s.log.Err(err).Str("TraceID", tID).Msg(ErrChangeEmailSendMessage.Error())
+			return fmt.Errorf("cannot send message: %w", err)
+		}
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusAccepted)
+}
+
+// ConfirmEmail changes the email of the user to the confirmed one
+// and rejects the tokens issued for the previous email.
+func (s *Service) ConfirmEmail(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.confirm-email")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ce := ConfirmEmail{}
+	if err := web.Decode(r, &ce); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrConfirmEmailValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	res, err := s.core.ConfirmEmailChange(ctx, ce.Token)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrConfirmEmailBusiness.Error())
+		return fmt.Errorf(
+			"cannot confirm email: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	if err := s.auth.StoreUserTokenVersion(ctx, res.ID, res.TokenVersion); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrConfirmEmailStoreTokenVersion.Error())
+		return ErrConfirmEmailStoreTokenVersion
+	}
+	ur := UserResponse{
+		ID:          res.ID,
+		Name:        res.Name,
+		Email:       res.Email,
+		DateCreated: res.DateCreated,
+	}
+	return web.Respond(ctx, w, ur, http.StatusOK)
+}
@@ -3,9 +3,8 @@ package user
 import "errors"
 
 var (
-	ErrGetUsersBusiness = errors.New("error get users from business layer")
-	ErrValidateUserID   = errors.New("error get user validate uuid")
-	ErrGetUserBusiness  = errors.New("error get user from business layer")
+	ErrValidateUserID  = errors.New("error get user validate uuid")
+	ErrGetUserBusiness = errors.New("error get user from business layer")
 
 	ErrCreateValidate    = errors.New("error create parsing user input")
 	ErrCreateBusiness    = errors.New("error create user from business layer")
@@ -21,4 +20,24 @@ var (
 	ErrDeleteValidateUUID      = errors.New("error delete user validate uuid")
 	ErrDeleteBusiness          = errors.New("error delete user from business layer")
 	ErrDeleteStoreTokenVersion = errors.New("error delete user storing token version")
+
+	ErrCreateTokenValidate     = errors.New("error create access token parsing user input")
+	ErrCreateTokenBusiness     = errors.New("error create access token from business layer")
+	ErrGetTokensBusiness       = errors.New("error get access tokens from business layer")
+	ErrDeleteTokenValidateUUID = errors.New("error delete access token validate uuid")
+	ErrDeleteTokenBusiness     = errors.New("error delete access token from business layer")
+
+	ErrGetUsersValidate       = errors.New("error get users parsing query")
+	ErrGetUsersBusiness       = errors.New("error get users from business layer")
+	ErrAdminValidateUserID    = errors.New("error admin validate user uuid")
+	ErrAdminUpdateBusiness    = errors.New("error admin update user from business layer")
+	ErrAdminStoreTokenVersion = errors.New("error admin update user storing token version")
+	ErrUpdateRolesValidate    = errors.New("error update user roles parsing user input")
+
+	ErrChangeEmailValidate           = errors.New("error change email parsing user input")
+	ErrChangeEmailBusiness           = errors.New("error change email from business layer")
+	ErrChangeEmailSendMessage        = errors.New("error change email send message")
+	ErrConfirmEmailValidate          = errors.New("error confirm email parsing user input")
+	ErrConfirmEmailBusiness          = errors.New("error confirm email from business layer")
+	ErrConfirmEmailStoreTokenVersion = errors.New("error confirm email storing token version")
 )
@@ -7,6 +7,69 @@ import (
 	"time"
 )
 
+// AccessTokenResponse defines model for AccessTokenResponse.
+type AccessTokenResponse struct {
+	// DateCreated creation date
+	DateCreated time.Time `json:"date_created"`
+
+	// ExpiresAt expiry date, none if the token does not expire
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Id token id
+	ID string `json:"id"`
+
+	// LastUsedAt date of the last request with the token, accurate to a minute
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+
+	// Name name of the token
+	Name string `json:"name"`
+
+	// Scopes scopes of the token
+	Scopes []string `json:"scopes"`
+}
+
+// AdminUserResponse defines model for AdminUserResponse.
+type AdminUserResponse struct {
+	// DateCreated date created
+	DateCreated time.Time `json:"date_created"`
+
+	// DateUpdated date updated
+	DateUpdated time.Time `json:"date_updated"`
+
+	// Email user's email
+	Email string `json:"email"`
+
+	// Id user unique id
+	ID string `json:"id"`
+
+	// IsActive whether the user can log in
+	IsActive bool `json:"is_active"`
+
+	// IsDeleted whether the user is deleted
+	IsDeleted bool `json:"is_deleted"`
+
+	// Name user's name
+	Name string `json:"name"`
+
+	// Roles roles of the user
+	Roles []string `json:"roles"`
+}
+
+// ChangeEmail defines model for ChangeEmail.
+type ChangeEmail struct {
+	// Email new email of the user
+	Email string `json:"email" validate:"required,email"`
+
+	// Password current password of the user
+	Password string `json:"password" validate:"required"`
+}
+
+// ConfirmEmail defines model for ConfirmEmail.
+type ConfirmEmail struct {
+	// Token token sent to the new email
+	Token string `json:"token" validate:"required"`
+}
+
 // DeleteUser defines model for DeleteUser.
 type DeleteUser struct {
 	// Password user password
@@ -20,6 +83,26 @@ type ErrorResponse struct {
 	Fields *map[string]string `json:"fields,omitempty"`
 }
 
+// NewAccessToken defines model for NewAccessToken.
+type NewAccessToken struct {
+	// ExpiresInDays days until the token expires, it does not expire if omitted
+	ExpiresInDays *int `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=365"`
+
+	// Name name telling the token apart, such as the script using it
+	Name string `json:"name" validate:"required,max=100"`
+
+	// Scopes scopes of the token: lists:read, lists:write, images:read, images:write
+	Scopes []string `json:"scopes" validate:"required,min=1,unique,dive,oneof=lists:read lists:write images:read images:write"`
+}
+
+// NewAccessTokenResponse defines model for NewAccessTokenResponse.
+type NewAccessTokenResponse struct {
+	AccessToken AccessTokenResponse `json:"access_token"`
+
+	// Token the token to send as a Bearer token, it cannot be shown again
+	Token string `json:"token"`
+}
+
 // NewUser defines model for NewUser.
 type NewUser struct {
 	// Email user email
@@ -37,9 +120,6 @@ type NewUser struct {
 
 // UpdateUser defines model for UpdateUser.
 type UpdateUser struct {
-	// Email user email
-	Email *string `json:"email,omitempty" validate:"omitempty,email"`
-
 	// Name user name
 	Name *string `json:"name,omitempty" validate:"omitempty,gte=2"`
 
@@ -47,6 +127,12 @@ type UpdateUser struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// UpdateUserRoles defines model for UpdateUserRoles.
+type UpdateUserRoles struct {
+	// Roles roles replacing the current ones, each must exist
+	Roles []string `json:"roles" validate:"required,min=1,unique,dive,required"`
+}
+
 // UserResponse defines model for UserResponse.
 type UserResponse struct {
 	// DateCreated date created
@@ -62,6 +148,13 @@ type UserResponse struct {
 	Name string `json:"name"`
 }
 
+// UsersPageResponse defines model for UsersPageResponse.
+type UsersPageResponse struct {
+	// Total number of users matching the filters
+	Total int                 `json:"total"`
+	Users []AdminUserResponse `json:"users"`
+}
+
 // VerifyUser defines model for VerifyUser.
 type VerifyUser struct {
 	// Email user email
@@ -71,6 +164,27 @@ type VerifyUser struct {
 	Token string `json:"token" validate:"required"`
 }
 
+// GetAdminUsersParams defines parameters for GetAdminUsers.
+type GetAdminUsersParams struct {
+	// Search part of the name or email to match
+	Search *string `form:"search,omitempty" json:"search,omitempty" validate:"omitempty,max=100"`
+
+	// IsActive only active or inactive users
+	IsActive *bool `form:"is_active,omitempty" json:"is_active,omitempty"`
+
+	// IsDeleted only deleted or not deleted users
+	IsDeleted *bool `form:"is_deleted,omitempty" json:"is_deleted,omitempty"`
+
+	// Page page number, starts from 1
+	Page *int `form:"page,omitempty" json:"page,omitempty" validate:"omitempty,gte=1"`
+
+	// PageSize page size, 20 by default, 100 at most
+	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty" validate:"omitempty,gte=1,lte=100"`
+}
+
+// PutAdminUsersIdRolesJSONRequestBody defines body for PutAdminUsersIdRoles for application/json ContentType.
+type PutAdminUsersIdRolesJSONRequestBody = UpdateUserRoles
+
 // DeleteUsersJSONRequestBody defines body for DeleteUsers for application/json ContentType.
 type DeleteUsersJSONRequestBody = DeleteUser
 
@@ -80,5 +194,14 @@ type PostUsersJSONRequestBody = NewUser
 // PutUsersJSONRequestBody defines body for PutUsers for application/json ContentType.
 type PutUsersJSONRequestBody = UpdateUser
 
+// PostUsersEmailConfirmJSONRequestBody defines body for PostUsersEmailConfirm for application/json ContentType.
+type PostUsersEmailConfirmJSONRequestBody = ConfirmEmail
+
+// PostUsersMeEmailJSONRequestBody defines body for PostUsersMeEmail for application/json ContentType.
+type PostUsersMeEmailJSONRequestBody = ChangeEmail
+
+// PostUsersMeTokensJSONRequestBody defines body for PostUsersMeTokens for application/json ContentType.
+type PostUsersMeTokensJSONRequestBody = NewAccessToken
+
 // PostUsersVerifyJSONRequestBody defines body for PostUsersVerify for application/json ContentType.
 type PostUsersVerifyJSONRequestBody = VerifyUser
@@ -45,31 +45,6 @@ func NewService(l *zerolog.Logger, a *authPkg.Auth, c *userUsecase.Core, mq *que
 	}
 }
 
-// TODO: should be in admin space or removed
-func (s *Service) GetUsers(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
-	ctx, span := web.AddSpan(ctx, "service.user.get-users")
-	defer span.End()
-	tID := web.GetTraceID(ctx)
-	res, err := s.core.QueryAll(ctx)
-	if err != nil {
-		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetUsersBusiness.Error())
-		return fmt.Errorf(
-			"cannot get users: %w",
-			web.GetResponseErrorFromBusiness(err),
-		)
-	}
-	us := []UserResponse{}
-	for _, u := range res {
-		us = append(us, UserResponse{
-			ID:          u.ID,
-			Name:        u.Name,
-			Email:       u.Email,
-			DateCreated: u.DateCreated,
-		})
-	}
-	return web.Respond(ctx, w, us, http.StatusOK)
-}
-
 func (s *Service) GetUser(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
 	ctx, span := web.AddSpan(ctx, "service.user.get-user")
 	defer span.End()
@@ -221,14 +196,9 @@ func (s *Service) UpdateUser(ctx context.Context, w http.ResponseWriter, r *http
 			http.StatusBadRequest,
 		)
 	}
-	if u.Email != nil {
-		str := strings.ToLower(*u.Email)
-		u.Email = &str
-	}
 	uu := userUsecase.UpdateUser{
 		ID:       claims.Subject,
 		Name:     u.Name,
-		Email:    u.Email,
 		Password: u.Password,
 	}
 	res, err := s.core.Update(ctx, uu)
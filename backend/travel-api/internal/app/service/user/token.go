@@ -0,0 +1,116 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	userUsecase "github.com/f4mk/travel/backend/travel-api/internal/app/usecase/user"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// CreateAccessToken creates a personal access token for scripts and
+// integrations, the token is in the response only.
+func (s *Service) CreateAccessToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.create-access-token")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	t := NewAccessToken{}
+	if err := web.Decode(r, &t); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrCreateTokenValidate.Error())
+		return web.NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
+	}
+	nt := userUsecase.NewAccessToken{
+		UserID: claims.Subject,
+		Name:   t.Name,
+		Scopes: t.Scopes,
+	}
+	if t.ExpiresInDays != nil {
+		nt.ExpiresIn = time.Duration(*t.ExpiresInDays) * 24 * time.Hour
+	}
+	res, token, err := s.core.CreateAccessToken(ctx, nt)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrCreateTokenBusiness.Error())
+		return fmt.Errorf(
+			"cannot create access token: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	at := NewAccessTokenResponse{
+		Token:       token,
+		AccessToken: populateAccessTokenResponse(res),
+	}
+	return web.Respond(ctx, w, at, http.StatusCreated)
+}
+
+func (s *Service) GetAccessTokens(ctx context.Context, w http.ResponseWriter, _ *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.get-access-tokens")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	res, err := s.core.GetAccessTokens(ctx, claims.Subject)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrGetTokensBusiness.Error())
+		return fmt.Errorf(
+			"cannot get access tokens: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	ts := make([]AccessTokenResponse, 0, len(res))
+	for _, t := range res {
+		ts = append(ts, populateAccessTokenResponse(t))
+	}
+	return web.Respond(ctx, w, ts, http.StatusOK)
+}
+
+func (s *Service) DeleteAccessToken(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	ctx, span := web.AddSpan(ctx, "service.user.delete-access-token")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msgf(auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	tokenID := web.Param(r, "tokenID")
+	if err := web.ValidateUUID(tokenID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteTokenValidateUUID.Error())
+		return web.NewRequestError(
+			fmt.Errorf("invalid id: %w", err),
+			http.StatusBadRequest,
+		)
+	}
+	if err := s.core.DeleteAccessToken(ctx, claims.Subject, tokenID); err != nil {
+		s.log.Err(err).Str("TraceID", tID).Msg(ErrDeleteTokenBusiness.Error())
+		return fmt.Errorf(
+			"cannot delete access token: %w",
+			web.GetResponseErrorFromBusiness(err),
+		)
+	}
+	return web.Respond(ctx, w, struct{}{}, http.StatusOK)
+}
+
+func populateAccessTokenResponse(t userUsecase.AccessToken) AccessTokenResponse {
+	return AccessTokenResponse{
+		ID:          t.ID,
+		Name:        t.Name,
+		Scopes:      t.Scopes,
+		ExpiresAt:   t.ExpiresAt,
+		LastUsedAt:  t.LastUsedAt,
+		DateCreated: t.DateCreated,
+	}
+}
@@ -9,3 +9,23 @@ func (nu NewUser) Validate() error {
 func (uu UpdateUser) Validate() error {
 	return web.Check(uu)
 }
+
+func (nt NewAccessToken) Validate() error {
+	return web.Check(nt)
+}
+
+func (ur UpdateUserRoles) Validate() error {
+	return web.Check(ur)
+}
+
+func (p GetAdminUsersParams) Validate() error {
+	return web.Check(p)
+}
+
+func (ce ChangeEmail) Validate() error {
+	return web.Check(ce)
+}
+
+func (ce ConfirmEmail) Validate() error {
+	return web.Check(ce)
+}
@@ -0,0 +1,54 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+type Storer interface {
+	QueryEvents(ctx context.Context, q EventsQuery) (EventsPage, error)
+}
+
+// Core reads the audit events, they are recorded by the other usecases
+// through the audit log and never changed.
+type Core struct {
+	storer Storer
+	log    *zerolog.Logger
+}
+
+func NewCore(l *zerolog.Logger, s Storer) *Core {
+	return &Core{
+		storer: s,
+		log:    l,
+	}
+}
+
+// QueryEvents returns a page of the events matching the query, latest first.
+func (c *Core) QueryEvents(ctx context.Context, q EventsQuery) (EventsPage, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.audit.query-events")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if q.Page < 1 {
+		q.Page = 1
+	}
+	if q.PageSize < 1 {
+		q.PageSize = defaultPageSize
+	}
+	if q.PageSize > maxPageSize {
+		q.PageSize = maxPageSize
+	}
+	ep, err := c.storer.QueryEvents(ctx, q)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("audit: query events: %s", database.ErrQueryDB.Error())
+		return EventsPage{}, database.WrapStorerError(err)
+	}
+	return ep, nil
+}
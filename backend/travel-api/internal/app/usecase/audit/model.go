@@ -0,0 +1,37 @@
+package audit
+
+import "time"
+
+// Event is a recorded action, Before and After hold the fields
+// of the target that the action changed.
+type Event struct {
+	ID          string
+	ActorID     *string
+	Action      string
+	TargetType  string
+	TargetID    string
+	TraceID     string
+	IP          string
+	Before      map[string]any
+	After       map[string]any
+	DateCreated time.Time
+}
+
+// EventsQuery filters the events, From and To bound the event date.
+type EventsQuery struct {
+	ActorID    *string
+	Action     *string
+	TargetType *string
+	TargetID   *string
+	TraceID    *string
+	From       *time.Time
+	To         *time.Time
+	Page       int
+	PageSize   int
+}
+
+// EventsPage is a page of events, Total counts all the events matching the query.
+type EventsPage struct {
+	Events []Event
+	Total  int
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+)
+
+func auditRole(r Role) audit.Fields {
+	return audit.Fields{
+		"description": r.Description,
+		"permissions": r.Permissions,
+	}
+}
+
+// recordLogin records a login attempt of the user, failed attempts have
+// no actor as it is not known who made them.
+func (c *Core) recordLogin(ctx context.Context, userID string, ok bool) {
+	e := audit.Entry{
+		Action:     audit.ActionLoginFailed,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	}
+	if ok {
+		e.Action = audit.ActionLogin
+		e.ActorID = userID
+	}
+	c.audit.Record(ctx, e)
+}
@@ -6,11 +6,11 @@ import (
 	"encoding/hex"
 	"time"
 
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 	"github.com/rs/zerolog"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type Storer interface {
@@ -18,19 +18,68 @@ type Storer interface {
 	StoreResetToken(ctx context.Context, rt ResetToken) error
 	DeleteResetTokensByUserID(ctx context.Context, uID string) error
 	QueryResetTokenByID(ctx context.Context, token string) (ResetToken, error)
+	StoreMagicLinkToken(ctx context.Context, mt MagicLinkToken) error
+	DeleteMagicLinkTokensByUserID(ctx context.Context, uID string) error
+	ConsumeMagicLinkToken(ctx context.Context, tokenID string) (MagicLinkToken, error)
 	QueryByEmail(ctx context.Context, email string) (User, error)
 	QueryByID(ctx context.Context, uID string) (User, error)
 	Update(ctx context.Context, u User) error
+	UpdatePasswordHash(ctx context.Context, userID string, hash []byte) error
+	CreateSession(ctx context.Context, tf TokenFamily, s Session) error
+	QuerySessions(ctx context.Context, userID string, now time.Time) ([]Session, error)
+	QuerySession(ctx context.Context, sessionID string) (Session, error)
+	QueryTokenFamily(ctx context.Context, familyID string) (TokenFamily, error)
+	RotateToken(ctx context.Context, rt RotateToken) error
+	RevokeTokenFamily(ctx context.Context, familyID string, revokedAt time.Time) error
+	RevokeTokenFamiliesByUserID(ctx context.Context, userID string, revokedAt time.Time) error
+	QueryTOTP(ctx context.Context, userID string) (TOTP, error)
+	UpsertTOTP(ctx context.Context, t TOTP) error
+	EnableTOTP(ctx context.Context, t TOTP, rcs []RecoveryCode) error
+	UpdateTOTPStep(ctx context.Context, userID string, step int64, updatedAt time.Time) error
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string, usedAt time.Time) error
+	DeleteTOTP(ctx context.Context, userID string) error
+	QueryRoles(ctx context.Context) ([]Role, error)
+	QueryRole(ctx context.Context, name string) (Role, error)
+	UpsertRole(ctx context.Context, r Role) error
+	RoleInUse(ctx context.Context, name string) (bool, error)
+	DeleteRole(ctx context.Context, name string) error
+}
+
+// Hasher hashes passwords and checks them against the stored hashes.
+// NeedsRehash reports the hashes made by an outdated scheme or params.
+type Hasher interface {
+	Hash(password string) ([]byte, error)
+	Compare(hash []byte, password string) error
+	NeedsRehash(hash []byte) bool
+}
+
+// Policy refuses the passwords which are weak, breached or made of the
+// user inputs such as the name and email.
+type Policy interface {
+	Check(password string, userInputs ...string) error
+}
+
+// Roles resolves the permissions the roles grant.
+type Roles interface {
+	Permissions(roles []string) []string
 }
 
 type Core struct {
 	storer Storer
+	hasher Hasher
+	policy Policy
+	roles  Roles
+	audit  *audit.Log
 	log    *zerolog.Logger
 }
 
-func NewCore(l *zerolog.Logger, s Storer) *Core {
+func NewCore(l *zerolog.Logger, s Storer, h Hasher, p Policy, r Roles, a *audit.Log) *Core {
 	return &Core{
 		storer: s,
+		hasher: h,
+		policy: p,
+		roles:  r,
+		audit:  a,
 		log:    l,
 	}
 }
@@ -53,10 +102,19 @@ func (c *Core) Login(ctx context.Context, lu LoginUser) (AuthenticatedUser, erro
 		c.log.Error().Str("TraceID", tID).Msgf("auth: login: user is deleted")
 		return AuthenticatedUser{}, web.ErrNotFound
 	}
-	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(lu.Password)); err != nil {
+	if err := c.hasher.Compare(u.PasswordHash, lu.Password); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: login: %s", web.ErrAuthFailed.Error())
+		c.recordLogin(ctx, u.ID, false)
 		return AuthenticatedUser{}, web.ErrAuthFailed
 	}
+	if c.hasher.NeedsRehash(u.PasswordHash) {
+		c.rehashPassword(ctx, u.ID, lu.Password)
+	}
+	twoFactor, err := c.hasTOTP(ctx, u.ID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: login: %s", database.ErrQueryDB.Error())
+		return AuthenticatedUser{}, database.WrapStorerError(err)
+	}
 	au := AuthenticatedUser{
 		UserID:       u.ID,
 		Email:        u.Email,
@@ -64,6 +122,11 @@ func (c *Core) Login(ctx context.Context, lu LoginUser) (AuthenticatedUser, erro
 		TokenVersion: u.TokenVersion,
 		Roles:        u.Roles,
 		DateCreated:  u.DateCreated,
+		TwoFactor:    twoFactor,
+	}
+	// with two factors the login completes once the code is verified
+	if !twoFactor {
+		c.recordLogin(ctx, u.ID, true)
 	}
 	return au, nil
 }
@@ -81,6 +144,12 @@ func (c *Core) Logout(ctx context.Context, dt DeleteToken) error {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: logout: %s", database.ErrQueryDB.Error())
 		return database.WrapStorerError(err)
 	}
+	if dt.FamilyID != "" {
+		if err := c.storer.RevokeTokenFamily(ctx, dt.FamilyID, dt.RevokedAt); err != nil {
+			c.log.Err(err).Str("TraceID", tID).Msgf("auth: logout: %s", database.ErrQueryDB.Error())
+			return database.WrapStorerError(err)
+		}
+	}
 
 	return nil
 }
@@ -94,11 +163,15 @@ func (c *Core) ChangePassword(ctx context.Context, cp ChangePassword) (User, err
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: change password: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
-	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(cp.PasswordOld)); err != nil {
+	if err := c.hasher.Compare(u.PasswordHash, cp.PasswordOld); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: login: %s", web.ErrAuthFailed.Error())
 		return User{}, web.ErrAuthFailed
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(cp.Password), bcrypt.DefaultCost)
+	if err := c.policy.Check(cp.Password, u.Name, u.Email); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("auth: change password: password refused")
+		return User{}, web.NewFieldsError("password", err)
+	}
+	hash, err := c.hasher.Hash(cp.Password)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: change password: %s", auth.ErrGenHash.Error())
 		return User{}, auth.ErrGenHash
@@ -111,6 +184,11 @@ func (c *Core) ChangePassword(ctx context.Context, cp ChangePassword) (User, err
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: change password: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionPasswordChange,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+	})
 	return u, nil
 }
 
@@ -179,7 +257,11 @@ func (c *Core) ResetPasswordSubmit(ctx context.Context, sp SubmitPassword) (User
 	if u.IsDeleted {
 		return User{}, web.ErrNotFound
 	}
-	hash, err := bcrypt.GenerateFromPassword([]byte(sp.Password), bcrypt.DefaultCost)
+	if err := c.policy.Check(sp.Password, u.Name, u.Email); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("auth: reset password submit: password refused")
+		return User{}, web.NewFieldsError("password", err)
+	}
+	hash, err := c.hasher.Hash(sp.Password)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: reset password submit: %s", auth.ErrGenHash.Error())
 		return User{}, auth.ErrGenHash
@@ -194,6 +276,12 @@ func (c *Core) ResetPasswordSubmit(ctx context.Context, sp SubmitPassword) (User
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: reset password submit: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionPasswordReset,
+		ActorID:    u.ID,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+	})
 	return u, nil
 }
 
@@ -212,5 +300,53 @@ func (c *Core) LogoutAll(ctx context.Context, dt DeleteToken) (int32, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: logout all: %s", database.ErrQueryDB.Error())
 		return 0, database.WrapStorerError(err)
 	}
+	// the new token version already rejects their tokens
+	if err := c.storer.RevokeTokenFamiliesByUserID(ctx, u.ID, u.DateUpdated); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: logout all: %s", database.ErrQueryDB.Error())
+		return 0, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionLogoutAll,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+	})
 	return u.TokenVersion, nil
 }
+
+// ForceLogout logs the user out everywhere on behalf of an admin,
+// the admin must hold every permission the user holds.
+func (c *Core) ForceLogout(ctx context.Context, userID string) (int32, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.force-logout")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: force logout: %s", auth.ErrGetClaims.Error())
+		return 0, auth.ErrGetClaims
+	}
+	u, err := c.storer.QueryByID(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: force logout: %s", database.ErrQueryDB.Error())
+		return 0, database.WrapStorerError(err)
+	}
+	if !claims.CanAll(c.roles.Permissions(u.Roles)...) {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: force logout: %s", web.ErrForbidden.Error())
+		return 0, web.ErrForbidden
+	}
+	return c.LogoutAll(ctx, DeleteToken{Subject: userID})
+}
+
+// rehashPassword replaces the hash of the user with one of the current
+// scheme while the password is at hand. The login goes on if it fails,
+// the hash is upgraded on a later login.
+func (c *Core) rehashPassword(ctx context.Context, userID string, password string) {
+	tID := web.GetTraceID(ctx)
+	hash, err := c.hasher.Hash(password)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: rehash password: %s", auth.ErrGenHash.Error())
+		return
+	}
+	if err := c.storer.UpdatePasswordHash(ctx, userID, hash); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: rehash password: %s", database.ErrQueryDB.Error())
+	}
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"errors"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// RotateRefreshToken makes the new token the only valid one of the family.
+// A token that is not the current one of its family has been used already,
+// the family is revoked then and auth.ErrRefreshTokenReused returned.
+func (c *Core) RotateRefreshToken(ctx context.Context, rt RotateToken) error {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.rotate-refresh-token")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	err := c.storer.RotateToken(ctx, rt)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(database.WrapStorerError(err), web.ErrNotFound) {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: rotate refresh token: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	tf, err := c.storer.QueryTokenFamily(ctx, rt.FamilyID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: rotate refresh token: %s", database.ErrQueryDB.Error())
+		return web.ErrAuthFailed
+	}
+	if tf.UserID != rt.UserID {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: rotate refresh token: %s", web.ErrAuthFailed.Error())
+		return web.ErrAuthFailed
+	}
+	if tf.RevokedAt != nil {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: rotate refresh token: %s", auth.ErrRevokedTokenFamily.Error())
+		return auth.ErrRevokedTokenFamily
+	}
+	if err := c.storer.RevokeTokenFamily(ctx, tf.ID, rt.UsedAt); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: rotate refresh token: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.log.Warn().Str("TraceID", tID).Msgf(
+		"auth: rotate refresh token: %s: family %s of user %s revoked",
+		auth.ErrRefreshTokenReused.Error(), tf.ID, tf.UserID,
+	)
+	return auth.ErrRefreshTokenReused
+}
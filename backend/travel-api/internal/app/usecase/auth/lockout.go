@@ -0,0 +1,30 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// UnlockRequest returns the recipient of the unlock letter of a locked
+// account, web.ErrNotFound if no user has the email.
+func (c *Core) UnlockRequest(ctx context.Context, email string, token string) (UnlockAccount, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.unlock-request")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	u, err := c.storer.QueryByEmail(ctx, email)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: unlock request: %s", database.ErrQueryDB.Error())
+		return UnlockAccount{}, database.WrapStorerError(err)
+	}
+	if u.IsDeleted {
+		return UnlockAccount{}, web.ErrNotFound
+	}
+	ua := UnlockAccount{
+		Email:       u.Email,
+		Name:        u.Name,
+		UnlockToken: token,
+	}
+	return ua, nil
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const magicLinkTTL = 15 * time.Minute
+
+// MagicLinkRequest issues a login link token for the user of the email,
+// a new link replaces the ones not used yet. Users who cannot log in
+// get no link, web.ErrNotFound is returned for them.
+func (c *Core) MagicLinkRequest(ctx context.Context, email string) (MagicLink, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.magic-link-request")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	u, err := c.storer.QueryByEmail(ctx, email)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link request: %s", database.ErrQueryDB.Error())
+		return MagicLink{}, database.WrapStorerError(err)
+	}
+	if !u.IsActive || u.IsDeleted {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: magic link request: user is inactive or deleted")
+		return MagicLink{}, web.ErrNotFound
+	}
+	if err := c.storer.DeleteMagicLinkTokensByUserID(ctx, u.ID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link request: %s", database.ErrQueryDB.Error())
+		return MagicLink{}, database.WrapStorerError(err)
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link request: %s", auth.ErrGenResetToken.Error())
+		return MagicLink{}, auth.ErrGenResetToken
+	}
+	et := hex.EncodeToString(token)
+	now := time.Now().UTC()
+	mt := MagicLinkToken{
+		TokenID:   hashMagicLinkToken(et),
+		UserID:    u.ID,
+		ExpiresAt: now.Add(magicLinkTTL),
+		IssuedAt:  now,
+	}
+	if err := c.storer.StoreMagicLinkToken(ctx, mt); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link request: %s", database.ErrQueryDB.Error())
+		return MagicLink{}, database.WrapStorerError(err)
+	}
+	ml := MagicLink{
+		Email:      u.Email,
+		Name:       u.Name,
+		LoginToken: et,
+	}
+	return ml, nil
+}
+
+// MagicLinkLogin logs the user in with the token of a login link, the token
+// is used up even if the login fails. It authenticates the way Login does.
+func (c *Core) MagicLinkLogin(ctx context.Context, token string) (AuthenticatedUser, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.magic-link-login")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	mt, err := c.storer.ConsumeMagicLinkToken(ctx, hashMagicLinkToken(token))
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link login: %s", database.ErrQueryDB.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return AuthenticatedUser{}, web.ErrAuthFailed
+		}
+		return AuthenticatedUser{}, database.WrapStorerError(err)
+	}
+	if mt.ExpiresAt.Before(time.Now().UTC()) {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: magic link login: %s", auth.ErrValidateToken.Error())
+		return AuthenticatedUser{}, web.ErrAuthFailed
+	}
+	u, err := c.storer.QueryByID(ctx, mt.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link login: %s", database.ErrQueryDB.Error())
+		return AuthenticatedUser{}, web.ErrAuthFailed
+	}
+	if !u.IsActive {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: magic link login: user is inactive")
+		return AuthenticatedUser{}, web.ErrAuthFailed
+	}
+	if u.IsDeleted {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: magic link login: user is deleted")
+		return AuthenticatedUser{}, web.ErrNotFound
+	}
+	twoFactor, err := c.hasTOTP(ctx, u.ID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: magic link login: %s", database.ErrQueryDB.Error())
+		return AuthenticatedUser{}, database.WrapStorerError(err)
+	}
+	au := AuthenticatedUser{
+		UserID:       u.ID,
+		Email:        u.Email,
+		Name:         u.Name,
+		TokenVersion: u.TokenVersion,
+		Roles:        u.Roles,
+		DateCreated:  u.DateCreated,
+		TwoFactor:    twoFactor,
+	}
+	// the link replaces the password only, the second factor is still asked
+	if !twoFactor {
+		c.recordLogin(ctx, u.ID, true)
+	}
+	return au, nil
+}
+
+func hashMagicLinkToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -26,6 +26,8 @@ type AuthenticatedUser struct {
 	Name         string
 	TokenVersion int32
 	DateCreated  time.Time
+	// TwoFactor is set when the user has to pass a TOTP code before tokens are issued
+	TwoFactor bool
 }
 
 type LoginUser struct {
@@ -35,6 +37,7 @@ type LoginUser struct {
 
 type DeleteToken struct {
 	TokenID      string
+	FamilyID     string
 	Subject      string
 	TokenVersion int32
 	IssuedAt     time.Time
@@ -54,6 +57,12 @@ type ResetPassword struct {
 	ResetToken string
 }
 
+type UnlockAccount struct {
+	Email       string
+	Name        string
+	UnlockToken string
+}
+
 type ResetToken struct {
 	TokenID   string
 	UserID    string
@@ -62,7 +71,117 @@ type ResetToken struct {
 	IssuedAt  time.Time
 }
 
+// MagicLinkToken is a login link token, TokenID is the hash of the token.
+type MagicLinkToken struct {
+	TokenID   string
+	UserID    string
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+}
+
+type MagicLink struct {
+	Email      string
+	Name       string
+	LoginToken string
+}
+
 type SubmitPassword struct {
 	ResetToken string
 	Password   string
 }
+
+// TokenFamily is the chain of refresh tokens started by a login,
+// only its current token can be used to refresh.
+type TokenFamily struct {
+	ID             string
+	UserID         string
+	CurrentTokenID string
+	ExpiresAt      time.Time
+	DateCreated    time.Time
+	DateUpdated    time.Time
+	RevokedAt      *time.Time
+}
+
+// Session is a login on a device, its ID is the ID of the token family
+// the login started. A session ends when its family is revoked or expires.
+type Session struct {
+	ID          string
+	UserID      string
+	DeviceName  string
+	UserAgent   string
+	IP          string
+	DateCreated time.Time
+	LastSeen    time.Time
+	ExpiresAt   time.Time
+	RevokedAt   *time.Time
+}
+
+// NewSession is a login with the first token of its family.
+type NewSession struct {
+	FamilyID   string
+	UserID     string
+	TokenID    string
+	ExpiresAt  time.Time
+	DeviceName string
+	UserAgent  string
+	IP         string
+}
+
+// RotateToken replaces the current token of the family with a new one.
+type RotateToken struct {
+	FamilyID     string
+	UserID       string
+	TokenID      string
+	ExpiresAt    time.Time
+	NewTokenID   string
+	NewExpiresAt time.Time
+	UsedAt       time.Time
+	UserAgent    string
+	IP           string
+}
+
+// TOTP is the authenticator app secret of a user, it is pending until
+// the first code is verified. LastStep is the time step of the last
+// accepted code, codes of that step and before are refused.
+type TOTP struct {
+	UserID      string
+	Secret      string
+	Enabled     bool
+	LastStep    int64
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type TOTPSetup struct {
+	Secret string
+	Email  string
+}
+
+// RecoveryCode is a hashed one-time code to log in without the authenticator app.
+type RecoveryCode struct {
+	CodeHash    string
+	UserID      string
+	DateCreated time.Time
+	DateUsed    *time.Time
+}
+
+type DisableTOTP struct {
+	UserID   string
+	Password string
+	Code     string
+}
+
+// Role is a role users hold with the permissions it grants.
+type Role struct {
+	Name        string
+	Description string
+	Permissions []string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type UpdateRole struct {
+	Name        string
+	Description string
+	Permissions []string
+}
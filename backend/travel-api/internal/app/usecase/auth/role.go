@@ -0,0 +1,140 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// GetRoles returns all roles with their permissions, the admin role
+// is listed with every permission it is granted in code.
+func (c *Core) GetRoles(ctx context.Context) ([]Role, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.get-roles")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	rs, err := c.storer.QueryRoles(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: get roles: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	for i := range rs {
+		if rs[i].Name == auth.RoleAdmin {
+			rs[i].Permissions = auth.AllPermissions
+		}
+	}
+	return rs, nil
+}
+
+// UpdateRole creates the role or replaces its description and permissions.
+// The admin role holds every permission and cannot be changed, other roles
+// can only be changed by those holding every permission before and after.
+func (c *Core) UpdateRole(ctx context.Context, ur UpdateRole) (Role, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.update-role")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: update role: %s", auth.ErrGetClaims.Error())
+		return Role{}, auth.ErrGetClaims
+	}
+	if ur.Name == auth.RoleAdmin {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: update role: %s", web.ErrForbidden.Error())
+		return Role{}, web.ErrForbidden
+	}
+	for _, p := range ur.Permissions {
+		if !auth.IsPermission(p) {
+			c.log.Error().Str("TraceID", tID).Msgf("auth: update role: %s: %s", auth.ErrUnknownPermission.Error(), p)
+			return Role{}, web.NewFieldsError("permissions", auth.ErrUnknownPermission)
+		}
+	}
+	if !claims.CanAll(ur.Permissions...) {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: update role: %s", web.ErrForbidden.Error())
+		return Role{}, web.ErrForbidden
+	}
+	now := time.Now().UTC()
+	r, err := c.storer.QueryRole(ctx, ur.Name)
+	if err != nil && !errors.Is(database.WrapStorerError(err), web.ErrNotFound) {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: update role: %s", database.ErrQueryDB.Error())
+		return Role{}, database.WrapStorerError(err)
+	}
+	var before audit.Fields
+	if err != nil {
+		r = Role{
+			Name:        ur.Name,
+			DateCreated: now,
+		}
+	} else {
+		if !claims.CanAll(r.Permissions...) {
+			c.log.Error().Str("TraceID", tID).Msgf("auth: update role: %s", web.ErrForbidden.Error())
+			return Role{}, web.ErrForbidden
+		}
+		before = auditRole(r)
+	}
+	r.Description = ur.Description
+	r.Permissions = ur.Permissions
+	r.DateUpdated = now
+	if err := c.storer.UpsertRole(ctx, r); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: update role: %s", database.ErrQueryDB.Error())
+		return Role{}, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionRoleUpdate,
+		TargetType: audit.TargetRole,
+		TargetID:   r.Name,
+		Before:     before,
+		After:      auditRole(r),
+	})
+	return r, nil
+}
+
+// DeleteRole deletes a role no user holds anymore,
+// the admin and user roles cannot be deleted.
+// Only those holding every permission of the role can delete it.
+func (c *Core) DeleteRole(ctx context.Context, name string) error {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.delete-role")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: delete role: %s", auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	if name == auth.RoleAdmin || name == auth.RoleUser {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: delete role: %s", web.ErrForbidden.Error())
+		return web.ErrForbidden
+	}
+	inUse, err := c.storer.RoleInUse(ctx, name)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: delete role: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if inUse {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: delete role: %s", web.ErrPreconditionFailed.Error())
+		return web.ErrPreconditionFailed
+	}
+	r, err := c.storer.QueryRole(ctx, name)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: delete role: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if !claims.CanAll(r.Permissions...) {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: delete role: %s", web.ErrForbidden.Error())
+		return web.ErrForbidden
+	}
+	if err := c.storer.DeleteRole(ctx, name); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: delete role: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionRoleDelete,
+		TargetType: audit.TargetRole,
+		TargetID:   name,
+		Before:     auditRole(r),
+	})
+	return nil
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// CreateSession records the login with the token family it started.
+func (c *Core) CreateSession(ctx context.Context, ns NewSession) (Session, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.create-session")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	now := time.Now().UTC()
+	tf := TokenFamily{
+		ID:             ns.FamilyID,
+		UserID:         ns.UserID,
+		CurrentTokenID: ns.TokenID,
+		ExpiresAt:      ns.ExpiresAt,
+		DateCreated:    now,
+		DateUpdated:    now,
+	}
+	s := Session{
+		ID:          ns.FamilyID,
+		UserID:      ns.UserID,
+		DeviceName:  ns.DeviceName,
+		UserAgent:   ns.UserAgent,
+		IP:          ns.IP,
+		DateCreated: now,
+		LastSeen:    now,
+		ExpiresAt:   ns.ExpiresAt,
+	}
+	if s.DeviceName == "" {
+		s.DeviceName = ns.UserAgent
+	}
+	if err := c.storer.CreateSession(ctx, tf, s); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: create session: %s", database.ErrQueryDB.Error())
+		return Session{}, database.WrapStorerError(err)
+	}
+	return s, nil
+}
+
+// GetSessions returns the sessions of the user that are neither revoked nor expired.
+func (c *Core) GetSessions(ctx context.Context, userID string) ([]Session, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.get-sessions")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ss, err := c.storer.QuerySessions(ctx, userID, time.Now().UTC())
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: get sessions: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return ss, nil
+}
+
+// TerminateSession revokes the token family of the session, the session
+// of another user or one already ended is reported as not found.
+func (c *Core) TerminateSession(ctx context.Context, userID string, sessionID string) (Session, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.terminate-session")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	s, err := c.storer.QuerySession(ctx, sessionID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: terminate session: %s", database.ErrQueryDB.Error())
+		return Session{}, database.WrapStorerError(err)
+	}
+	now := time.Now().UTC()
+	if s.UserID != userID || s.RevokedAt != nil || s.ExpiresAt.Before(now) {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: terminate session: %s", web.ErrNotFound.Error())
+		return Session{}, web.ErrNotFound
+	}
+	if err := c.storer.RevokeTokenFamily(ctx, s.ID, now); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: terminate session: %s", database.ErrQueryDB.Error())
+		return Session{}, database.WrapStorerError(err)
+	}
+	s.RevokedAt = &now
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionSessionTerminate,
+		TargetType: audit.TargetSession,
+		TargetID:   s.ID,
+	})
+	return s, nil
+}
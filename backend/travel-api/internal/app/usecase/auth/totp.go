@@ -0,0 +1,247 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/totp"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const (
+	recoveryCodeCount = 10
+	recoveryCodeSize  = 10
+)
+
+// SetupTOTP generates a new pending secret for the user, replacing a
+// pending one. It returns web.ErrAlreadyExists if TOTP is already enabled.
+func (c *Core) SetupTOTP(ctx context.Context, userID string) (TOTPSetup, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.setup-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	u, err := c.storer.QueryByID(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: setup totp: %s", database.ErrQueryDB.Error())
+		return TOTPSetup{}, database.WrapStorerError(err)
+	}
+	enabled, err := c.hasTOTP(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: setup totp: %s", database.ErrQueryDB.Error())
+		return TOTPSetup{}, database.WrapStorerError(err)
+	}
+	if enabled {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: setup totp: %s", web.ErrAlreadyExists.Error())
+		return TOTPSetup{}, web.ErrAlreadyExists
+	}
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: setup totp: %s", auth.ErrGenTOTPSecret.Error())
+		return TOTPSetup{}, auth.ErrGenTOTPSecret
+	}
+	now := time.Now().UTC()
+	t := TOTP{
+		UserID:      userID,
+		Secret:      secret,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+	if err := c.storer.UpsertTOTP(ctx, t); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: setup totp: %s", database.ErrQueryDB.Error())
+		return TOTPSetup{}, database.WrapStorerError(err)
+	}
+	return TOTPSetup{Secret: secret, Email: u.Email}, nil
+}
+
+// EnableTOTP enables the pending secret once a code of it is verified and
+// returns the recovery codes, they are only stored hashed.
+func (c *Core) EnableTOTP(ctx context.Context, userID string, code string) ([]string, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.enable-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	t, err := c.storer.QueryTOTP(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: enable totp: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	if t.Enabled {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: enable totp: %s", web.ErrAlreadyExists.Error())
+		return nil, web.ErrAlreadyExists
+	}
+	now := time.Now().UTC()
+	step, ok, err := totp.Validate(t.Secret, code, now, t.LastStep)
+	if err != nil || !ok {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: enable totp: %s", web.ErrAuthFailed.Error())
+		return nil, web.ErrAuthFailed
+	}
+	codes := make([]string, 0, recoveryCodeCount)
+	rcs := make([]RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			c.log.Err(err).Str("TraceID", tID).Msgf("auth: enable totp: %s", auth.ErrGenRecoveryCodes.Error())
+			return nil, auth.ErrGenRecoveryCodes
+		}
+		codes = append(codes, code)
+		rcs = append(rcs, RecoveryCode{
+			CodeHash:    hashRecoveryCode(code),
+			UserID:      userID,
+			DateCreated: now,
+		})
+	}
+	t.Enabled = true
+	t.LastStep = step
+	t.DateUpdated = now
+	if err := c.storer.EnableTOTP(ctx, t, rcs); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: enable totp: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionTOTPEnable,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+	})
+	return codes, nil
+}
+
+// DisableTOTP removes the secret and the recovery codes of the user,
+// it requires the password and a code.
+func (c *Core) DisableTOTP(ctx context.Context, dt DisableTOTP) error {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.disable-totp")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	u, err := c.storer.QueryByID(ctx, dt.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: disable totp: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if err := c.hasher.Compare(u.PasswordHash, dt.Password); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: disable totp: %s", web.ErrAuthFailed.Error())
+		return web.ErrAuthFailed
+	}
+	t, err := c.storer.QueryTOTP(ctx, dt.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: disable totp: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if !t.Enabled {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: disable totp: %s", web.ErrNotFound.Error())
+		return web.ErrNotFound
+	}
+	if err := c.checkCode(ctx, t, dt.Code); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: disable totp: %s", web.ErrAuthFailed.Error())
+		return err
+	}
+	if err := c.storer.DeleteTOTP(ctx, dt.UserID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: disable totp: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionTOTPDisable,
+		TargetType: audit.TargetUser,
+		TargetID:   dt.UserID,
+	})
+	return nil
+}
+
+// VerifyLoginCode is the second step of the login of a user with TOTP enabled.
+func (c *Core) VerifyLoginCode(ctx context.Context, userID string, code string) (AuthenticatedUser, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.auth.verify-login-code")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	u, err := c.storer.QueryByID(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: verify login code: %s", database.ErrQueryDB.Error())
+		return AuthenticatedUser{}, web.ErrAuthFailed
+	}
+	if !u.IsActive || u.IsDeleted {
+		c.log.Error().Str("TraceID", tID).Msgf("auth: verify login code: user is inactive or deleted")
+		return AuthenticatedUser{}, web.ErrAuthFailed
+	}
+	t, err := c.storer.QueryTOTP(ctx, userID)
+	if err != nil || !t.Enabled {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: verify login code: totp is not enabled")
+		return AuthenticatedUser{}, web.ErrAuthFailed
+	}
+	if err := c.checkCode(ctx, t, code); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("auth: verify login code: %s", web.ErrAuthFailed.Error())
+		c.recordLogin(ctx, u.ID, false)
+		return AuthenticatedUser{}, err
+	}
+	c.recordLogin(ctx, u.ID, true)
+	au := AuthenticatedUser{
+		UserID:       u.ID,
+		Email:        u.Email,
+		Name:         u.Name,
+		TokenVersion: u.TokenVersion,
+		Roles:        u.Roles,
+		DateCreated:  u.DateCreated,
+		TwoFactor:    true,
+	}
+	return au, nil
+}
+
+// checkCode accepts a code of the authenticator app newer than the last
+// accepted one, or an unused recovery code which is used up then.
+func (c *Core) checkCode(ctx context.Context, t TOTP, code string) error {
+	now := time.Now().UTC()
+	if len(code) == totp.Digits {
+		step, ok, err := totp.Validate(t.Secret, code, now, t.LastStep)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return web.ErrAuthFailed
+		}
+		// the step only moves forward, a concurrent use of the same code fails here
+		if err := c.storer.UpdateTOTPStep(ctx, t.UserID, step, now); err != nil {
+			if errors.Is(database.WrapStorerError(err), web.ErrNotFound) {
+				return web.ErrAuthFailed
+			}
+			return database.WrapStorerError(err)
+		}
+		return nil
+	}
+	if err := c.storer.UseRecoveryCode(ctx, t.UserID, hashRecoveryCode(code), now); err != nil {
+		if errors.Is(database.WrapStorerError(err), web.ErrNotFound) {
+			return web.ErrAuthFailed
+		}
+		return database.WrapStorerError(err)
+	}
+	return nil
+}
+
+// hasTOTP reports whether the user has TOTP enabled.
+func (c *Core) hasTOTP(ctx context.Context, userID string) (bool, error) {
+	t, err := c.storer.QueryTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(database.WrapStorerError(err), web.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return t.Enabled, nil
+}
+
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(b)
+	return code[:len(code)/2] + "-" + code[len(code)/2:], nil
+}
+
+// hashRecoveryCode ignores case and dashes the way users retype codes.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,232 @@
+package export
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/geofile"
+)
+
+// imageExt is the extension of the stored images, they are converted to webp on upload.
+const imageExt = ".webp"
+
+// archive is the data of a user put in the archive.
+type archive struct {
+	profile Profile
+	lists   []List
+	items   []Item
+	images  []Image
+}
+
+// The documents of the archive. They are written as JSON, so the fields
+// are named the way the API names them.
+type profileDoc struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	Roles       []string  `json:"roles"`
+	IsActive    bool      `json:"is_active"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+type listDoc struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Private     bool      `json:"private"`
+	Favorite    bool      `json:"favorite"`
+	Completed   bool      `json:"completed"`
+	Items       []itemDoc `json:"items"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+type itemDoc struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Address     *string   `json:"address,omitempty"`
+	Lat         float64   `json:"lat"`
+	Lng         float64   `json:"lng"`
+	Visited     bool      `json:"visited"`
+	ImagesID    []string  `json:"images_id"`
+	DateCreated time.Time `json:"date_created"`
+	DateUpdated time.Time `json:"date_updated"`
+}
+
+type imageDoc struct {
+	ID          string    `json:"id"`
+	File        string    `json:"file"`
+	ListID      string    `json:"list_id"`
+	ItemID      *string   `json:"item_id,omitempty"`
+	Description *string   `json:"description,omitempty"`
+	Private     bool      `json:"private"`
+	DateCreated time.Time `json:"date_created"`
+}
+
+func (c *Core) collect(ctx context.Context, userID string) (archive, error) {
+	p, err := c.storer.QueryProfile(ctx, userID)
+	if err != nil {
+		return archive{}, err
+	}
+	ls, err := c.storer.QueryLists(ctx, userID)
+	if err != nil {
+		return archive{}, err
+	}
+	its, err := c.storer.QueryItems(ctx, userID)
+	if err != nil {
+		return archive{}, err
+	}
+	imgs, err := c.storer.QueryImages(ctx, userID)
+	if err != nil {
+		return archive{}, err
+	}
+	a := archive{
+		profile: p,
+		lists:   ls,
+		items:   its,
+		images:  imgs,
+	}
+	return a, nil
+}
+
+// writeArchive writes the ZIP archive of the data:
+//
+//	profile.json                the user profile
+//	lists.json                  the lists with their items
+//	lists/<list id>.geojson     the points of the items of a list
+//	images.json                 the images with the lists and items they belong to
+//	images/<image id>.webp      the image files
+func (c *Core) writeArchive(ctx context.Context, w io.Writer, a archive) error {
+	zw := zip.NewWriter(w)
+	p := profileDoc{
+		ID:          a.profile.ID,
+		Name:        a.profile.Name,
+		Email:       a.profile.Email,
+		Roles:       a.profile.Roles,
+		IsActive:    a.profile.IsActive,
+		DateCreated: a.profile.DateCreated,
+		DateUpdated: a.profile.DateUpdated,
+	}
+	if err := writeJSON(zw, "profile.json", p); err != nil {
+		return err
+	}
+
+	byList := make(map[string][]Item)
+	for _, it := range a.items {
+		byList[it.ListID] = append(byList[it.ListID], it)
+	}
+	lds := make([]listDoc, 0, len(a.lists))
+	for _, l := range a.lists {
+		ld := listDoc{
+			ID:          l.ID,
+			Name:        l.Name,
+			Description: l.Description,
+			Private:     l.Private,
+			Favorite:    l.Favorite,
+			Completed:   l.Completed,
+			Items:       []itemDoc{},
+			DateCreated: l.DateCreated,
+			DateUpdated: l.DateUpdated,
+		}
+		doc := geofile.Document{
+			Name: l.Name,
+		}
+		if l.Description != nil {
+			doc.Description = *l.Description
+		}
+		for _, it := range byList[l.ID] {
+			ld.Items = append(ld.Items, itemDoc{
+				ID:          it.ID,
+				Name:        it.Name,
+				Description: it.Description,
+				Address:     it.Address,
+				Lat:         it.Lat,
+				Lng:         it.Lng,
+				Visited:     it.Visited,
+				ImagesID:    it.ImagesID,
+				DateCreated: it.DateCreated,
+				DateUpdated: it.DateUpdated,
+			})
+			wp := geofile.Waypoint{
+				Name:    it.Name,
+				Lat:     it.Lat,
+				Lng:     it.Lng,
+				Visited: it.Visited,
+			}
+			if it.Description != nil {
+				wp.Description = *it.Description
+			}
+			if it.Address != nil {
+				wp.Address = *it.Address
+			}
+			doc.Waypoints = append(doc.Waypoints, wp)
+		}
+		lds = append(lds, ld)
+		f, err := zw.Create("lists/" + l.ID + ".geojson")
+		if err != nil {
+			return err
+		}
+		if err := geofile.Encode(f, geofile.GeoJSON, doc); err != nil {
+			return err
+		}
+	}
+	if err := writeJSON(zw, "lists.json", lds); err != nil {
+		return err
+	}
+
+	ids := make([]imageDoc, 0, len(a.images))
+	for _, img := range a.images {
+		ids = append(ids, imageDoc{
+			ID:          img.ID,
+			File:        "images/" + img.ID + imageExt,
+			ListID:      img.ListID,
+			ItemID:      img.ItemID,
+			Description: img.Description,
+			Private:     img.Private,
+			DateCreated: img.DateCreated,
+		})
+	}
+	if err := writeJSON(zw, "images.json", ids); err != nil {
+		return err
+	}
+	for _, img := range ids {
+		if err := c.writeImage(ctx, zw, img); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (c *Core) writeImage(ctx context.Context, zw *zip.Writer, img imageDoc) error {
+	rc, err := c.images.ServeFile(ctx, img.ID)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	// images are compressed already
+	f, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     img.File,
+		Method:   zip.Store,
+		Modified: img.DateCreated,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, rc)
+	return err
+}
+
+func writeJSON(zw *zip.Writer, name string, v any) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
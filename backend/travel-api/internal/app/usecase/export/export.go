@@ -0,0 +1,246 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// pendingTimeout is how long a pending export blocks a new request,
+// an export left pending by a stopped worker is not waited for forever.
+const pendingTimeout = time.Hour
+
+type Storer interface {
+	CreateExport(ctx context.Context, e Export) error
+	UpdateExport(ctx context.Context, e Export) error
+	QueryExportByID(ctx context.Context, exportID string) (Export, error)
+	QueryExportByTokenID(ctx context.Context, tokenID string) (Export, error)
+	HasPendingExport(ctx context.Context, userID string, since time.Time) (bool, error)
+	QueryProfile(ctx context.Context, userID string) (Profile, error)
+	QueryLists(ctx context.Context, userID string) ([]List, error)
+	QueryItems(ctx context.Context, userID string) ([]Item, error)
+	QueryImages(ctx context.Context, userID string) ([]Image, error)
+}
+
+// Server keeps the archives.
+type Server interface {
+	SaveArchive(ctx context.Context, exportID string, archive io.Reader) error
+	ServeArchive(ctx context.Context, exportID string) (io.ReadCloser, error)
+}
+
+// ImageServer serves the stored images of the users.
+type ImageServer interface {
+	ServeFile(ctx context.Context, fileID string) (io.ReadCloser, error)
+}
+
+// Core builds the archives of all the data of a user: the profile, the
+// owned lists with their items, the points of the items and the images.
+type Core struct {
+	storer  Storer
+	server  Server
+	images  ImageServer
+	linkTTL time.Duration
+	audit   *audit.Log
+	log     *zerolog.Logger
+}
+
+// NewCore returns the core, the download links of the archives expire after linkTTL.
+func NewCore(
+	l *zerolog.Logger,
+	s Storer,
+	srv Server,
+	img ImageServer,
+	linkTTL time.Duration,
+	a *audit.Log,
+) *Core {
+	return &Core{
+		storer:  s,
+		server:  srv,
+		images:  img,
+		linkTTL: linkTTL,
+		audit:   a,
+		log:     l,
+	}
+}
+
+// RequestExport stores a pending export of the data of the user, it is
+// built by BuildExport. web.ErrAlreadyExists is returned while an earlier
+// export of the user is still pending.
+func (c *Core) RequestExport(ctx context.Context, userID string) (Export, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.export.request-export")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	now := time.Now().UTC()
+	pending, err := c.storer.HasPendingExport(ctx, userID, now.Add(-pendingTimeout))
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: request export: %s", database.ErrQueryDB.Error())
+		return Export{}, database.WrapStorerError(err)
+	}
+	if pending {
+		c.log.Error().Str("TraceID", tID).Msgf("export: request export: %s", web.ErrAlreadyExists.Error())
+		return Export{}, web.ErrAlreadyExists
+	}
+	e := Export{
+		ID:          uuid.New().String(),
+		UserID:      userID,
+		Status:      StatusPending,
+		DateCreated: now,
+	}
+	if err := c.storer.CreateExport(ctx, e); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: request export: %s", database.ErrQueryDB.Error())
+		return Export{}, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionDataExport,
+		TargetType: audit.TargetUser,
+		TargetID:   userID,
+		After:      audit.Fields{"export_id": e.ID},
+	})
+	return e, nil
+}
+
+// BuildExport writes the archive of the pending export and stores it.
+// The export stays pending until CompleteExport is called with the returned
+// download token, once the user was sent the link. An export that is not
+// pending anymore is not built again, web.ErrNotFound is returned.
+func (c *Core) BuildExport(ctx context.Context, exportID string) (Ready, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.export.build-export")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	e, err := c.storer.QueryExportByID(ctx, exportID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: build export: %s", database.ErrQueryDB.Error())
+		return Ready{}, database.WrapStorerError(err)
+	}
+	if e.Status != StatusPending {
+		c.log.Error().Str("TraceID", tID).Msgf("export: build export: export is %s", e.Status)
+		return Ready{}, web.ErrNotFound
+	}
+	a, err := c.collect(ctx, e.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: build export: %s", database.ErrQueryDB.Error())
+		return Ready{}, database.WrapStorerError(err)
+	}
+	// the archive is streamed to the server as it is written
+	pr, pw := io.Pipe()
+	errCh := make(chan error, 1)
+	go func() {
+		err := c.writeArchive(ctx, pw, a)
+		pw.CloseWithError(err)
+		errCh <- err
+	}()
+	err = c.server.SaveArchive(ctx, e.ID, pr)
+	// unblocks the writer if the server stopped reading
+	pr.Close()
+	if wErr := <-errCh; wErr != nil {
+		c.log.Err(wErr).Str("TraceID", tID).Msg("export: build export: write archive")
+		return Ready{}, wErr
+	}
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("export: build export: save archive")
+		return Ready{}, err
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: build export: %s", auth.ErrGenResetToken.Error())
+		return Ready{}, auth.ErrGenResetToken
+	}
+	r := Ready{
+		Email:         a.profile.Email,
+		Name:          a.profile.Name,
+		DownloadToken: hex.EncodeToString(token),
+	}
+	return r, nil
+}
+
+// CompleteExport marks the built export as ready, its download token is
+// valid for the link TTL from now. An export that is not pending anymore
+// gives web.ErrNotFound.
+func (c *Core) CompleteExport(ctx context.Context, exportID string, downloadToken string) error {
+	ctx, span := web.AddSpan(ctx, "usecase.export.complete-export")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	e, err := c.storer.QueryExportByID(ctx, exportID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: complete export: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if e.Status != StatusPending {
+		c.log.Error().Str("TraceID", tID).Msgf("export: complete export: export is %s", e.Status)
+		return web.ErrNotFound
+	}
+	tokenID := hashDownloadToken(downloadToken)
+	expiresAt := time.Now().UTC().Add(c.linkTTL)
+	e.Status = StatusReady
+	e.TokenID = &tokenID
+	e.ExpiresAt = &expiresAt
+	if err := c.storer.UpdateExport(ctx, e); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: complete export: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	return nil
+}
+
+// FailExport marks the export as failed, so the user can request a new one.
+func (c *Core) FailExport(ctx context.Context, exportID string) error {
+	ctx, span := web.AddSpan(ctx, "usecase.export.fail-export")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	e, err := c.storer.QueryExportByID(ctx, exportID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: fail export: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if e.Status != StatusPending {
+		return nil
+	}
+	e.Status = StatusFailed
+	if err := c.storer.UpdateExport(ctx, e); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: fail export: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	return nil
+}
+
+// GetArchive returns the archive of the download token and its export.
+// An unknown or expired token gives web.ErrNotFound.
+func (c *Core) GetArchive(ctx context.Context, token string) (Export, io.ReadCloser, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.export.get-archive")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	e, err := c.storer.QueryExportByTokenID(ctx, hashDownloadToken(token))
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("export: get archive: %s", database.ErrQueryDB.Error())
+		if errors.Is(err, sql.ErrNoRows) {
+			return Export{}, nil, web.ErrNotFound
+		}
+		return Export{}, nil, database.WrapStorerError(err)
+	}
+	if e.Status != StatusReady || e.ExpiresAt == nil || e.ExpiresAt.Before(time.Now().UTC()) {
+		c.log.Error().Str("TraceID", tID).Msg("export: get archive: download link expired")
+		return Export{}, nil, web.ErrNotFound
+	}
+	rc, err := c.server.ServeArchive(ctx, e.ID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("export: get archive: serve archive")
+		return Export{}, nil, err
+	}
+	return e, rc, nil
+}
+
+func hashDownloadToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
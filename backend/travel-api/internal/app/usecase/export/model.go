@@ -0,0 +1,73 @@
+package export
+
+import "time"
+
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusReady   Status = "ready"
+	StatusFailed  Status = "failed"
+)
+
+// Export is a request for the archive of the user data. TokenID and
+// ExpiresAt are set once the archive is ready to be downloaded.
+type Export struct {
+	ID          string
+	UserID      string
+	Status      Status
+	TokenID     *string
+	DateCreated time.Time
+	ExpiresAt   *time.Time
+}
+
+// Ready is what the user needs to be told the archive can be downloaded.
+type Ready struct {
+	Email         string
+	Name          string
+	DownloadToken string
+}
+
+type Profile struct {
+	ID          string
+	Name        string
+	Email       string
+	Roles       []string
+	IsActive    bool
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type List struct {
+	ID          string
+	Name        string
+	Description *string
+	Private     bool
+	Favorite    bool
+	Completed   bool
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type Item struct {
+	ID          string
+	ListID      string
+	Name        string
+	Description *string
+	Address     *string
+	Lat         float64
+	Lng         float64
+	Visited     bool
+	ImagesID    []string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type Image struct {
+	ID          string
+	ListID      string
+	ItemID      *string
+	Description *string
+	Private     bool
+	DateCreated time.Time
+}
@@ -2,9 +2,12 @@ package image
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"io"
 	"time"
 
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/images"
@@ -20,6 +23,8 @@ type Server interface {
 }
 type Storer interface {
 	QueryByID(ctx context.Context, fileID string) (Image, error)
+	QueryPublicByID(ctx context.Context, slug string, tokenHash string, fileID string) (Image, error)
+	QueryRole(ctx context.Context, userID string, listID string) (Role, error)
 	Create(ctx context.Context, images []Image) error
 }
 type Converter interface {
@@ -30,14 +35,16 @@ type Core struct {
 	server    Server
 	storer    Storer
 	converter Converter
+	audit     *audit.Log
 	log       *zerolog.Logger
 }
 
-func NewCore(l *zerolog.Logger, sr Server, st Storer, cv Converter) *Core {
+func NewCore(l *zerolog.Logger, sr Server, st Storer, cv Converter, a *audit.Log) *Core {
 	return &Core{
 		server:    sr,
 		storer:    st,
 		converter: cv,
+		audit:     a,
 		log:       l,
 	}
 }
@@ -56,14 +63,31 @@ func (c *Core) GetImageByID(ctx context.Context, fileID string, userID string) (
 		c.log.Err(err).Str("TraceID", tID).Msgf("image: query by id: %s", auth.ErrGetClaims.Error())
 		return nil, auth.ErrGetClaims
 	}
-	if !claims.Authorize(auth.RoleAdmin) && userID != img.UserID && img.Private {
-		c.log.Error().Str("TraceID", tID).Msgf("image: query by id: %s", web.ErrForbidden.Error())
-		return nil, web.ErrForbidden
+	// the uploader keeps access to the image after leaving the list
+	if userID != img.UserID && !claims.Can(auth.PermImageReadAny) {
+		if err := c.authorize(ctx, userID, img.ListID, RoleViewer); err != nil {
+			return nil, err
+		}
 	}
 
 	return c.server.ServeFile(ctx, fileID)
 }
 
+// GetPublicImageByID serves an image of a list that is reachable by slug,
+// the slug is either the id of a non-private list or a share token.
+// Private images are only served to the members of the list, a share
+// token does not expose them.
+func (c *Core) GetPublicImageByID(ctx context.Context, slug string, fileID string) (io.ReadCloser, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.image.get-public-image-by-id")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if _, err := c.storer.QueryPublicByID(ctx, slug, hashShareToken(slug), fileID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("image: query public by id: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return c.server.ServeFile(ctx, fileID)
+}
+
 func (c *Core) StoreImages(
 	ctx context.Context,
 	imageStreams []io.Reader,
@@ -72,6 +96,9 @@ func (c *Core) StoreImages(
 	ctx, span := web.AddSpan(ctx, "usecase.image.store-images")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleEditor); err != nil {
+		return nil, err
+	}
 	var imageIDs []string
 	var imageItems []Image
 	imgStreams, err := c.converter.Convert(ctx, imageStreams)
@@ -108,6 +135,57 @@ func (c *Core) StoreImages(
 		}
 		return nil, err
 	}
+	for _, img := range imageItems {
+		c.audit.Record(ctx, audit.Entry{
+			Action:     audit.ActionImageUpload,
+			TargetType: audit.TargetImage,
+			TargetID:   img.ID,
+			After: audit.Fields{
+				"list_id": img.ListID,
+				"private": img.Private,
+			},
+		})
+	}
 
 	return imageIDs, nil
 }
+
+// anyListPermission is the permission granting a role on every list,
+// along with the roles below it.
+var anyListPermission = map[Role]string{
+	RoleViewer: auth.PermListReadAny,
+	RoleEditor: auth.PermListWriteAny,
+	RoleOwner:  auth.PermListManageAny,
+}
+
+// authorize checks that the user holds at least the wanted role on the list
+// of the image, or a permission granting the role on any list.
+func (c *Core) authorize(ctx context.Context, userID string, listID string, want Role) error {
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("image: authorize: %s", auth.ErrGetClaims.Error())
+		return auth.ErrGetClaims
+	}
+	for role, perm := range anyListPermission {
+		if role.Allows(want) && claims.Can(perm) {
+			return nil
+		}
+	}
+	role, err := c.storer.QueryRole(ctx, userID, listID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("image: authorize: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if !role.Allows(want) {
+		c.log.Error().Str("TraceID", tID).Msgf("image: authorize: %s", web.ErrForbidden.Error())
+		return web.ErrForbidden
+	}
+	return nil
+}
+
+// hashShareToken returns the hash share tokens are stored and looked up by.
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
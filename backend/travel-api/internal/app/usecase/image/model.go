@@ -6,6 +6,26 @@ import (
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/images"
 )
 
+// Role is the access level a user has on the list of an image.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Allows reports whether r grants at least the access level of want.
+func (r Role) Allows(want Role) bool {
+	return roleRank[r] > 0 && roleRank[r] >= roleRank[want]
+}
+
 type Image struct {
 	ID          string
 	ListID      string
@@ -0,0 +1,36 @@
+package list
+
+import "github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+
+func auditList(l List) audit.Fields {
+	return audit.Fields{
+		"user_id":     l.UserID,
+		"name":        l.Name,
+		"description": l.Description,
+		"private":     l.Private,
+		"favorite":    l.Favorite,
+		"completed":   l.Completed,
+		"items":       l.ItemsID,
+	}
+}
+
+func auditItem(i Item) audit.Fields {
+	return audit.Fields{
+		"list_id":     i.ListID,
+		"name":        i.Name,
+		"description": i.Description,
+		"address":     i.Address,
+		"lat":         i.Point.Lat,
+		"lng":         i.Point.Lng,
+		"images":      i.ImagesID,
+		"visited":     i.Visited,
+	}
+}
+
+func auditMember(m Member) audit.Fields {
+	return audit.Fields{
+		"list_id": m.ListID,
+		"user_id": m.UserID,
+		"role":    m.Role,
+	}
+}
@@ -0,0 +1,81 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/google/uuid"
+)
+
+// Event types, named as they are streamed to the subscribers.
+const (
+	EventItemCreated   = "item.created"
+	EventItemUpdated   = "item.updated"
+	EventItemDeleted   = "item.deleted"
+	EventListUpdated   = "list.updated"
+	EventListDeleted   = "list.deleted"
+	EventImageAttached = "image.attached"
+)
+
+// ErrEventsUnavailable is returned once the broker has stopped,
+// no events can be streamed until the API is restarted.
+var ErrEventsUnavailable = errors.New("error list events unavailable")
+
+type broker interface {
+	Publish(ctx context.Context, e Event) error
+	Subscribe(listID string) (<-chan Event, func(), bool)
+}
+
+// SubscribeEvents streams the changes of the list to a viewer of the list.
+// The channel is closed when the subscriber falls behind or the broker stops,
+// cancel must be called once the events are no longer read.
+func (c *Core) SubscribeEvents(ctx context.Context, userID string, listID string) (<-chan Event, func(), error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.subscribe-events")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleViewer); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("events: subscribe: authorize")
+		return nil, nil, err
+	}
+	events, cancel, ok := c.broker.Subscribe(listID)
+	if !ok {
+		c.log.Error().Str("TraceID", tID).Msgf("events: subscribe: %s", ErrEventsUnavailable.Error())
+		return nil, nil, ErrEventsUnavailable
+	}
+	return events, cancel, nil
+}
+
+// publish sends the event to the list subscribers on all replicas.
+// Delivery is best effort, a failure is logged and does not fail the change.
+func (c *Core) publish(ctx context.Context, e Event) {
+	tID := web.GetTraceID(ctx)
+	e.ID = uuid.New().String()
+	e.Date = time.Now().UTC()
+	if err := c.broker.Publish(ctx, e); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("events: publish %s: list %s", e.Type, e.ListID)
+	}
+}
+
+func (c *Core) publishImages(ctx context.Context, item Item, userID string, imagesID []string) {
+	for i := range imagesID {
+		c.publish(ctx, Event{Type: EventImageAttached, ListID: item.ListID, ItemID: &item.ID, ImageID: &imagesID[i], UserID: userID})
+	}
+}
+
+// attachedImages returns the images of next missing from prev.
+func attachedImages(prev []string, next []string) []string {
+	seen := make(map[string]bool, len(prev))
+	for _, id := range prev {
+		seen[id] = true
+	}
+	var res []string
+	for _, id := range next {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -0,0 +1,52 @@
+package list
+
+import (
+	"context"
+	"sort"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// ExportList returns the list with its items in the list order.
+// Items missing from the list order follow by creation date.
+func (c *Core) ExportList(ctx context.Context, userID string, listID string) (List, []Item, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.export-list")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleViewer); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("list: export: authorize")
+		return List{}, nil, err
+	}
+	list, err := c.storer.QueryListByID(ctx, listID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("list: export: %s", database.ErrQueryDB.Error())
+		return List{}, nil, database.WrapStorerError(err)
+	}
+	items, err := c.storer.QueryItemsByListID(ctx, listID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("list: export: %s", database.ErrQueryDB.Error())
+		return List{}, nil, database.WrapStorerError(err)
+	}
+	pos := make(map[string]int, len(list.ItemsID))
+	for i, id := range list.ItemsID {
+		if _, ok := pos[id]; !ok {
+			pos[id] = i
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		pi, iok := pos[items[i].ID]
+		pj, jok := pos[items[j].ID]
+		switch {
+		case iok && jok:
+			return pi < pj
+		case iok != jok:
+			return iok
+		case items[i].DateCreated.Equal(items[j].DateCreated):
+			return items[i].ID < items[j].ID
+		default:
+			return items[i].DateCreated.Before(items[j].DateCreated)
+		}
+	})
+	return list, items, nil
+}
@@ -0,0 +1,56 @@
+package list
+
+import (
+	"context"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// GetItemsNear returns items within the radius around the point, closest first.
+// Only the items of lists the user owns, is a member of, or that are public are searched.
+func (c *Core) GetItemsNear(ctx context.Context, q NearQuery) ([]GeoItem, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.get-items-near")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+	is, err := c.storer.QueryItemsNear(ctx, q)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("items: query near: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return is, nil
+}
+
+// GetItemsInBBox returns items inside the bounding box, closest to its center first.
+// Only the items of lists the user owns, is a member of, or that are public are searched.
+func (c *Core) GetItemsInBBox(ctx context.Context, q BBoxQuery) ([]GeoItem, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.get-items-in-bbox")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+	is, err := c.storer.QueryItemsInBBox(ctx, q)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("items: query bbox: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return is, nil
+}
+
+func normalizePage(page int, size int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	return page, size
+}
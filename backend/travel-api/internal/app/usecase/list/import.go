@@ -0,0 +1,70 @@
+package list
+
+import (
+	"context"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/google/uuid"
+)
+
+// ImportItems creates the items in a single transaction.
+// An item that cannot be created does not fail the import,
+// its error is reported in the result at the same position.
+func (c *Core) ImportItems(ctx context.Context, userID string, listID string, nis []NewItem) ([]ImportResult, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.import-items")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleEditor); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("items: import: authorize")
+		return nil, err
+	}
+	now := time.Now().UTC()
+	items := make([]Item, 0, len(nis))
+	for _, ni := range nis {
+		itemID := uuid.New().String()
+		items = append(items, Item{
+			ID:          itemID,
+			ListID:      listID,
+			UserID:      userID,
+			Name:        ni.Name,
+			Description: ni.Description,
+			Address:     ni.Address,
+			Point: Point{
+				ID:     uuid.New().String(),
+				ItemID: itemID,
+				Lat:    ni.Point.Lat,
+				Lng:    ni.Point.Lng,
+			},
+			ImagesID:    []string{},
+			Visited:     ni.Visited,
+			Version:     1,
+			DateCreated: now,
+			DateUpdated: now,
+		})
+	}
+	errs, err := c.storer.CreateItems(ctx, items)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("items: import: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	res := make([]ImportResult, len(items))
+	for i, item := range items {
+		res[i].Item = item
+		if errs[i] != nil {
+			c.log.Warn().Err(errs[i]).Str("TraceID", tID).Msgf("items: import: item %d: %s", i, database.ErrQueryDB.Error())
+			res[i].Err = database.WrapStorerError(errs[i])
+			continue
+		}
+		c.audit.Record(ctx, audit.Entry{
+			Action:     audit.ActionItemsImport,
+			TargetType: audit.TargetItem,
+			TargetID:   item.ID,
+			After:      auditItem(item),
+		})
+		c.publish(ctx, Event{Type: EventItemCreated, ListID: listID, ItemID: &items[i].ID, UserID: userID, Version: &items[i].Version})
+	}
+	return res, nil
+}
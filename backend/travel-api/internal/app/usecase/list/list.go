@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
@@ -12,40 +13,64 @@ import (
 )
 
 type storer interface {
-	QueryListByID(ctx context.Context, userID string, listID string) (List, error)
-	QueryListsByUserID(ctx context.Context, userID string) ([]List, error)
-	QueryItemsByListID(ctx context.Context, userID string, listID string) ([]Item, error)
+	QueryListByID(ctx context.Context, listID string) (List, error)
+	QueryListsByUserID(ctx context.Context, q ListsQuery) (ListsPage, error)
+	QueryItemsByListID(ctx context.Context, listID string) ([]Item, error)
+	QueryItemsPage(ctx context.Context, q ItemsQuery) (ItemsPage, error)
 	QueryItemByID(ctx context.Context, itemID string) (Item, error)
 	CreateList(ctx context.Context, list List) error
 	UpdateList(ctx context.Context, list List) error
-	DeleteListAdmin(ctx context.Context, listID string) error
-	DeleteList(ctx context.Context, userID string, listID string) error
+	DeleteList(ctx context.Context, listID string, version *int) error
 	CreateItem(ctx context.Context, item Item) error
+	CreateItems(ctx context.Context, items []Item) ([]error, error)
 	UpdateItem(ctx context.Context, item Item, deleteImages []string) error
-	DeleteItemAdmin(ctx context.Context, itemID string) error
-	DeleteItem(ctx context.Context, userID string, itemID string) error
+	DeleteItem(ctx context.Context, itemID string, version *int) error
+	QueryRole(ctx context.Context, userID string, listID string) (Role, error)
+	QueryMembers(ctx context.Context, listID string) ([]Member, error)
+	QueryMember(ctx context.Context, listID string, userID string) (Member, error)
+	CreateMember(ctx context.Context, member Member) error
+	UpdateMember(ctx context.Context, member Member) error
+	DeleteMember(ctx context.Context, listID string, userID string) error
+	QueryUserByID(ctx context.Context, userID string) (User, error)
+	QueryUserByEmail(ctx context.Context, email string) (User, error)
+	QueryPublicListBySlug(ctx context.Context, slug string, tokenHash string) (List, error)
+	QueryShares(ctx context.Context, listID string) ([]Share, error)
+	CreateShare(ctx context.Context, share Share) error
+	DeleteShare(ctx context.Context, listID string, shareID string) error
+	QueryItemsNear(ctx context.Context, q NearQuery) ([]GeoItem, error)
+	QueryItemsInBBox(ctx context.Context, q BBoxQuery) ([]GeoItem, error)
+	Search(ctx context.Context, q SearchQuery) ([]SearchMatch, error)
 }
 
 type Core struct {
 	storer storer
+	broker broker
+	audit  *audit.Log
 	log    *zerolog.Logger
 }
 
-func NewCore(l *zerolog.Logger, s storer) *Core {
+func NewCore(l *zerolog.Logger, s storer, b broker, a *audit.Log) *Core {
 	return &Core{
 		storer: s,
+		broker: b,
+		audit:  a,
 		log:    l,
 	}
 }
 
-func (c *Core) GetAllLists(ctx context.Context, userID string) ([]List, error) {
+// GetAllLists returns a page of the lists the user owns or is a member of.
+func (c *Core) GetAllLists(ctx context.Context, q ListsQuery) (ListsPage, error) {
 	ctx, span := web.AddSpan(ctx, "usecase.list.get-all-lists")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	ls, err := c.storer.QueryListsByUserID(ctx, userID)
+	_, q.Page.Limit = normalizePage(1, q.Page.Limit)
+	if q.Page.Sort == "" {
+		q.Page.Sort = SortDateCreated
+	}
+	ls, err := c.storer.QueryListsByUserID(ctx, q)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("lists: query all: %s", database.ErrQueryDB.Error())
-		return nil, database.WrapStorerError(err)
+		return ListsPage{}, database.WrapStorerError(err)
 	}
 	return ls, nil
 }
@@ -54,7 +79,11 @@ func (c *Core) GetListByID(ctx context.Context, userID string, listID string) (L
 	ctx, span := web.AddSpan(ctx, "usecase.list.get-list-by-id")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	list, err := c.storer.QueryListByID(ctx, userID, listID)
+	if err := c.authorize(ctx, userID, listID, RoleViewer); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("lists: query: authorize")
+		return List{}, err
+	}
+	list, err := c.storer.QueryListByID(ctx, listID)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("lists: query: %s", database.ErrQueryDB.Error())
 		return List{}, database.WrapStorerError(err)
@@ -62,14 +91,23 @@ func (c *Core) GetListByID(ctx context.Context, userID string, listID string) (L
 	return list, nil
 }
 
-func (c *Core) GetItemsByListID(ctx context.Context, userID string, listID string) ([]Item, error) {
+// GetItemsByListID returns a page of the list items, in the list order by default.
+func (c *Core) GetItemsByListID(ctx context.Context, userID string, q ItemsQuery) (ItemsPage, error) {
 	ctx, span := web.AddSpan(ctx, "usecase.list.get-items-by-list-id")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	is, err := c.storer.QueryItemsByListID(ctx, userID, listID)
+	if err := c.authorize(ctx, userID, q.ListID, RoleViewer); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("lists: query: authorize")
+		return ItemsPage{}, err
+	}
+	_, q.Page.Limit = normalizePage(1, q.Page.Limit)
+	if q.Page.Sort == "" {
+		q.Page.Sort = SortOrder
+	}
+	is, err := c.storer.QueryItemsPage(ctx, q)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("lists: query: %s", database.ErrQueryDB.Error())
-		return []Item{}, database.WrapStorerError(err)
+		return ItemsPage{}, database.WrapStorerError(err)
 	}
 	return is, nil
 }
@@ -83,14 +121,11 @@ func (c *Core) GetItemByID(ctx context.Context, userID, itemID string) (Item, er
 		c.log.Err(err).Str("TraceID", tID).Msgf("item: query: %s", database.ErrQueryDB.Error())
 		return Item{}, database.WrapStorerError(err)
 	}
-	claims, err := auth.GetClaims(ctx)
-	if err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("item: query: %s", auth.ErrGetClaims.Error())
-		return Item{}, auth.ErrGetClaims
-	}
-	if !claims.Authorize(auth.RoleAdmin) && (item.UserID != userID && item.Private) {
-		c.log.Error().Str("TraceID", tID).Msgf("item: query: %s", web.ErrForbidden.Error())
-		return Item{}, web.ErrForbidden
+	if item.Private {
+		if err := c.authorize(ctx, userID, item.ListID, RoleViewer); err != nil {
+			c.log.Err(err).Str("TraceID", tID).Msg("item: query: authorize")
+			return Item{}, err
+		}
 	}
 	return item, nil
 }
@@ -116,6 +151,7 @@ func (c *Core) CreateList(ctx context.Context, nl NewList) (List, error) {
 		Private:     priv,
 		Completed:   false,
 		ItemsID:     nil,
+		Version:     1,
 		DateCreated: now,
 		DateUpdated: now,
 	}
@@ -123,6 +159,12 @@ func (c *Core) CreateList(ctx context.Context, nl NewList) (List, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("list: create: %s", database.ErrQueryDB.Error())
 		return List{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionListCreate,
+		TargetType: audit.TargetList,
+		TargetID:   list.ID,
+		After:      auditList(list),
+	})
 	return list, nil
 }
 
@@ -130,20 +172,25 @@ func (c *Core) UpdateList(ctx context.Context, ul UpdateList) (List, error) {
 	ctx, span := web.AddSpan(ctx, "usecase.list.update-list")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	list, err := c.storer.QueryListByID(ctx, ul.UserID, ul.ID)
+	// changing visibility is reserved to the owner, editors may change the rest
+	want := RoleEditor
+	if ul.Private != nil {
+		want = RoleOwner
+	}
+	if err := c.authorize(ctx, ul.UserID, ul.ID, want); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("list: update: authorize")
+		return List{}, err
+	}
+	list, err := c.storer.QueryListByID(ctx, ul.ID)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("list: update: %s", database.ErrQueryDB.Error())
 		return List{}, database.WrapStorerError(err)
 	}
-	claims, err := auth.GetClaims(ctx)
-	if err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("list: update: %s", auth.ErrGetClaims.Error())
-		return List{}, auth.ErrGetClaims
-	}
-	if !claims.Authorize(auth.RoleAdmin) && list.UserID != ul.UserID {
-		c.log.Error().Str("TraceID", tID).Msgf("item: query: %s", web.ErrForbidden.Error())
-		return List{}, web.ErrForbidden
+	if !ul.IfMatch.Match(list.Version) {
+		c.log.Error().Str("TraceID", tID).Msgf("list: update: %s", web.ErrPreconditionFailed.Error())
+		return List{}, web.ErrPreconditionFailed
 	}
+	before := auditList(list)
 	if ul.Name != nil {
 		list.Name = *ul.Name
 	}
@@ -168,31 +215,49 @@ func (c *Core) UpdateList(ctx context.Context, ul UpdateList) (List, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("list: update: %s", database.ErrQueryDB.Error())
 		return List{}, database.WrapStorerError(err)
 	}
+	list.Version++
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionListUpdate,
+		TargetType: audit.TargetList,
+		TargetID:   list.ID,
+		Before:     before,
+		After:      auditList(list),
+	})
 	c.log.Warn().Str("TraceID", tID).Msgf("list: update: %s", list.ID)
+	c.publish(ctx, Event{Type: EventListUpdated, ListID: list.ID, UserID: ul.UserID, Version: &list.Version})
 	return list, nil
 }
 
-func (c *Core) DeleteList(ctx context.Context, userID string, listID string) error {
+// DeleteList deletes the list if its version meets the precondition.
+func (c *Core) DeleteList(ctx context.Context, userID string, listID string, ifMatch web.Precondition) error {
 	ctx, span := web.AddSpan(ctx, "usecase.list.delete-list-by-id")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	claims, err := auth.GetClaims(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleOwner); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("list: delete: authorize")
+		return err
+	}
+	list, err := c.storer.QueryListByID(ctx, listID)
 	if err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("list: delete: %s", auth.ErrGetClaims.Error())
-		return auth.ErrGetClaims
+		c.log.Err(err).Str("TraceID", tID).Msgf("list: delete: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
 	}
-	if claims.Authorize(auth.RoleAdmin) {
-		if err := c.storer.DeleteListAdmin(ctx, listID); err != nil {
-			c.log.Err(err).Str("TraceID", tID).Msgf("list: delete by admin: %s", database.ErrQueryDB.Error())
-			return database.WrapStorerError(err)
-		}
-		c.log.Warn().Str("TraceID", tID).Msgf("list: deleted by admin: %s", listID)
-		return nil
+	if !ifMatch.Match(list.Version) {
+		c.log.Error().Str("TraceID", tID).Msgf("list: delete: %s", web.ErrPreconditionFailed.Error())
+		return web.ErrPreconditionFailed
 	}
-	if err := c.storer.DeleteList(ctx, userID, listID); err != nil {
+	// matching on the version read keeps a concurrent change from being lost
+	if err := c.storer.DeleteList(ctx, listID, &list.Version); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("list: delete: %s", database.ErrQueryDB.Error())
 		return database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionListDelete,
+		TargetType: audit.TargetList,
+		TargetID:   listID,
+		Before:     auditList(list),
+	})
+	c.publish(ctx, Event{Type: EventListDeleted, ListID: listID, UserID: userID})
 	return nil
 }
 
@@ -200,6 +265,10 @@ func (c *Core) CreateItem(ctx context.Context, ni NewItem) (Item, error) {
 	ctx, span := web.AddSpan(ctx, "usecase.list.create-item")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, ni.UserID, ni.ListID, RoleEditor); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("item: create: authorize")
+		return Item{}, err
+	}
 	now := time.Now().UTC()
 	itemID := uuid.New().String()
 	point := Point{
@@ -217,7 +286,8 @@ func (c *Core) CreateItem(ctx context.Context, ni NewItem) (Item, error) {
 		Address:     ni.Address,
 		Point:       point,
 		ImagesID:    ni.ImagesID,
-		Visited:     false,
+		Visited:     ni.Visited,
+		Version:     1,
 		DateCreated: now,
 		DateUpdated: now,
 	}
@@ -225,6 +295,14 @@ func (c *Core) CreateItem(ctx context.Context, ni NewItem) (Item, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("item: create: %s", database.ErrQueryDB.Error())
 		return Item{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionItemCreate,
+		TargetType: audit.TargetItem,
+		TargetID:   item.ID,
+		After:      auditItem(item),
+	})
+	c.publish(ctx, Event{Type: EventItemCreated, ListID: item.ListID, ItemID: &item.ID, UserID: ni.UserID, Version: &item.Version})
+	c.publishImages(ctx, item, ni.UserID, item.ImagesID)
 	return item, nil
 }
 
@@ -237,15 +315,15 @@ func (c *Core) UpdateItem(ctx context.Context, ui UpdateItem) (Item, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("item: update: %s", database.ErrQueryDB.Error())
 		return Item{}, database.WrapStorerError(err)
 	}
-	claims, err := auth.GetClaims(ctx)
-	if err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("item: update: %s", auth.ErrGetClaims.Error())
-		return Item{}, auth.ErrGetClaims
+	if err := c.authorize(ctx, ui.UserID, item.ListID, RoleEditor); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("item: update: authorize")
+		return Item{}, err
 	}
-	if !claims.Authorize(auth.RoleAdmin) && item.UserID != ui.UserID {
-		c.log.Error().Str("TraceID", tID).Msgf("item: update: %s", web.ErrForbidden.Error())
-		return Item{}, web.ErrForbidden
+	if !ui.IfMatch.Match(item.Version) {
+		c.log.Error().Str("TraceID", tID).Msgf("item: update: %s", web.ErrPreconditionFailed.Error())
+		return Item{}, web.ErrPreconditionFailed
 	}
+	before := auditItem(item)
 	if ui.Point != nil {
 		item.Point.Lat = ui.Point.Lat
 		item.Point.Lng = ui.Point.Lng
@@ -261,6 +339,7 @@ func (c *Core) UpdateItem(ctx context.Context, ui UpdateItem) (Item, error) {
 	}
 	var toUpsert []string
 	var toDelete []string
+	attached := attachedImages(item.ImagesID, ui.ImagesID)
 	if len(item.ImagesID) != 0 {
 		existingMap := make(map[string]bool)
 		newMap := make(map[string]bool)
@@ -294,30 +373,83 @@ func (c *Core) UpdateItem(ctx context.Context, ui UpdateItem) (Item, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("item: update: %s", database.ErrQueryDB.Error())
 		return Item{}, database.WrapStorerError(err)
 	}
+	item.Version++
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionItemUpdate,
+		TargetType: audit.TargetItem,
+		TargetID:   item.ID,
+		Before:     before,
+		After:      auditItem(item),
+	})
 	c.log.Warn().Str("TraceID", tID).Msgf("item: update: %s", item.ID)
+	c.publish(ctx, Event{Type: EventItemUpdated, ListID: item.ListID, ItemID: &item.ID, UserID: ui.UserID, Version: &item.Version})
+	c.publishImages(ctx, item, ui.UserID, attached)
 	return item, nil
 }
 
-func (c *Core) DeleteItem(ctx context.Context, userID, itemID string) error {
+// DeleteItem deletes the item if its version meets the precondition.
+func (c *Core) DeleteItem(ctx context.Context, userID, itemID string, ifMatch web.Precondition) error {
 	ctx, span := web.AddSpan(ctx, "usecase.list.delete-item")
 	defer span.End()
+	tID := web.GetTraceID(ctx)
+	item, err := c.storer.QueryItemByID(ctx, itemID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("item: delete: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	if err := c.authorize(ctx, userID, item.ListID, RoleEditor); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("item: delete: authorize")
+		return err
+	}
+	if !ifMatch.Match(item.Version) {
+		c.log.Error().Str("TraceID", tID).Msgf("item: delete: %s", web.ErrPreconditionFailed.Error())
+		return web.ErrPreconditionFailed
+	}
+	// matching on the version read keeps a concurrent change from being lost
+	if err := c.storer.DeleteItem(ctx, itemID, &item.Version); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("item: delete: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionItemDelete,
+		TargetType: audit.TargetItem,
+		TargetID:   item.ID,
+		Before:     auditItem(item),
+	})
+	c.publish(ctx, Event{Type: EventItemDeleted, ListID: item.ListID, ItemID: &item.ID, UserID: userID})
+	return nil
+}
+
+// anyListPermission is the permission granting a role on every list,
+// along with the roles below it.
+var anyListPermission = map[Role]string{
+	RoleViewer: auth.PermListReadAny,
+	RoleEditor: auth.PermListWriteAny,
+	RoleOwner:  auth.PermListManageAny,
+}
+
+// authorize checks that the user holds at least the wanted role on the list,
+// or a permission granting the role on any list.
+func (c *Core) authorize(ctx context.Context, userID string, listID string, want Role) error {
 	tID := web.GetTraceID(ctx)
 	claims, err := auth.GetClaims(ctx)
 	if err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("item: delete: %s", auth.ErrGetClaims.Error())
+		c.log.Err(err).Str("TraceID", tID).Msgf("list: authorize: %s", auth.ErrGetClaims.Error())
 		return auth.ErrGetClaims
 	}
-	if claims.Authorize(auth.RoleAdmin) {
-		if err := c.storer.DeleteItemAdmin(ctx, itemID); err != nil {
-			c.log.Err(err).Str("TraceID", tID).Msgf("item: delete by admin: %s", database.ErrQueryDB.Error())
-			return database.WrapStorerError(err)
+	for role, perm := range anyListPermission {
+		if role.Allows(want) && claims.Can(perm) {
+			return nil
 		}
-		c.log.Warn().Str("TraceID", tID).Msgf("item: deleted by admin: %s", itemID)
-		return nil
 	}
-	if err := c.storer.DeleteItem(ctx, userID, itemID); err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("item: delete: %s", database.ErrQueryDB.Error())
+	role, err := c.storer.QueryRole(ctx, userID, listID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("list: authorize: %s", database.ErrQueryDB.Error())
 		return database.WrapStorerError(err)
 	}
+	if !role.Allows(want) {
+		c.log.Error().Str("TraceID", tID).Msgf("list: authorize: %s", web.ErrForbidden.Error())
+		return web.ErrForbidden
+	}
 	return nil
 }
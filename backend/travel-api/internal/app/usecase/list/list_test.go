@@ -0,0 +1,50 @@
+package list
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/rs/zerolog"
+)
+
+// roleStorer answers role lookups with a fixed role, the lists
+// of the other users return no role.
+type roleStorer struct {
+	storer
+	role Role
+}
+
+func (s roleStorer) QueryRole(ctx context.Context, userID string, listID string) (Role, error) {
+	return s.role, nil
+}
+
+func TestAuthorize(t *testing.T) {
+	log := zerolog.Nop()
+	adminPerms := auth.AllPermissions
+	tests := []struct {
+		name  string
+		perms []string
+		role  Role
+		want  Role
+		err   error
+	}{
+		{"admin session on a list of another user", adminPerms, "", RoleOwner, nil},
+		{"admin personal token on a list of another user", auth.PersonalPermissions(adminPerms), "", RoleEditor, web.ErrForbidden},
+		{"admin personal token reading a list of another user", auth.PersonalPermissions(adminPerms), "", RoleViewer, web.ErrForbidden},
+		{"personal token on an own list", auth.PersonalPermissions(adminPerms), RoleOwner, RoleOwner, nil},
+		{"viewer writing", nil, RoleViewer, RoleEditor, web.ErrForbidden},
+		{"editor writing", nil, RoleEditor, RoleEditor, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewCore(&log, roleStorer{role: tt.role}, nil, nil)
+			ctx := auth.SetClaims(context.Background(), auth.Claims{Permissions: tt.perms})
+			if err := c.authorize(ctx, "user", "list", tt.want); !errors.Is(err, tt.err) {
+				t.Errorf("authorize(%s) = %v, want %v", tt.want, err, tt.err)
+			}
+		})
+	}
+}
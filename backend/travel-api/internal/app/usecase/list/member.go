@@ -0,0 +1,148 @@
+package list
+
+import (
+	"context"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+func (c *Core) GetMembers(ctx context.Context, userID string, listID string) ([]Member, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.get-members")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleViewer); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("members: query: authorize")
+		return nil, err
+	}
+	ms, err := c.storer.QueryMembers(ctx, listID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("members: query: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return ms, nil
+}
+
+func (c *Core) AddMember(ctx context.Context, nm NewMember) (Invite, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.add-member")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, nm.InvitedBy, nm.ListID, RoleOwner); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("member: add: authorize")
+		return Invite{}, err
+	}
+	list, err := c.storer.QueryListByID(ctx, nm.ListID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: add: %s", database.ErrQueryDB.Error())
+		return Invite{}, database.WrapStorerError(err)
+	}
+	var user User
+	if nm.UserID != nil {
+		user, err = c.storer.QueryUserByID(ctx, *nm.UserID)
+	} else if nm.Email != nil {
+		user, err = c.storer.QueryUserByEmail(ctx, *nm.Email)
+	} else {
+		return Invite{}, web.ErrNotFound
+	}
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: add: %s", database.ErrQueryDB.Error())
+		return Invite{}, database.WrapStorerError(err)
+	}
+	if !user.IsActive || user.IsDeleted {
+		c.log.Error().Str("TraceID", tID).Msgf("member: add: %s", web.ErrNotFound.Error())
+		return Invite{}, web.ErrNotFound
+	}
+	if user.ID == list.UserID {
+		c.log.Error().Str("TraceID", tID).Msgf("member: add: owner: %s", web.ErrAlreadyExists.Error())
+		return Invite{}, web.ErrAlreadyExists
+	}
+	inviter, err := c.storer.QueryUserByID(ctx, nm.InvitedBy)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: add: %s", database.ErrQueryDB.Error())
+		return Invite{}, database.WrapStorerError(err)
+	}
+	now := time.Now().UTC()
+	m := Member{
+		ListID:      list.ID,
+		UserID:      user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		Role:        nm.Role,
+		InvitedBy:   nm.InvitedBy,
+		DateCreated: now,
+		DateUpdated: now,
+	}
+	if err := c.storer.CreateMember(ctx, m); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: add: %s", database.ErrQueryDB.Error())
+		return Invite{}, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionMemberAdd,
+		TargetType: audit.TargetList,
+		TargetID:   m.ListID,
+		After:      auditMember(m),
+	})
+	inv := Invite{
+		Member:      m,
+		ListName:    list.Name,
+		InviterName: inviter.Name,
+	}
+	return inv, nil
+}
+
+func (c *Core) UpdateMember(ctx context.Context, um UpdateMember) (Member, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.update-member")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, um.UpdatedBy, um.ListID, RoleOwner); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("member: update: authorize")
+		return Member{}, err
+	}
+	m, err := c.storer.QueryMember(ctx, um.ListID, um.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: update: %s", database.ErrQueryDB.Error())
+		return Member{}, database.WrapStorerError(err)
+	}
+	before := auditMember(m)
+	m.Role = um.Role
+	m.DateUpdated = time.Now().UTC()
+	if err := c.storer.UpdateMember(ctx, m); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: update: %s", database.ErrQueryDB.Error())
+		return Member{}, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionMemberUpdate,
+		TargetType: audit.TargetList,
+		TargetID:   m.ListID,
+		Before:     before,
+		After:      auditMember(m),
+	})
+	return m, nil
+}
+
+// DeleteMember revokes access to the list. The owner can remove anyone,
+// members can only remove themselves.
+func (c *Core) DeleteMember(ctx context.Context, userID string, listID string, memberID string) error {
+	ctx, span := web.AddSpan(ctx, "usecase.list.delete-member")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if userID != memberID {
+		if err := c.authorize(ctx, userID, listID, RoleOwner); err != nil {
+			c.log.Err(err).Str("TraceID", tID).Msg("member: delete: authorize")
+			return err
+		}
+	}
+	if err := c.storer.DeleteMember(ctx, listID, memberID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("member: delete: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionMemberDelete,
+		TargetType: audit.TargetList,
+		TargetID:   listID,
+		Before:     audit.Fields{"list_id": listID, "user_id": memberID},
+	})
+	return nil
+}
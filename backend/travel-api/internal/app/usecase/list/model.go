@@ -1,6 +1,10 @@
 package list
 
-import "time"
+import (
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
 
 type List struct {
 	ID          string
@@ -11,6 +15,7 @@ type List struct {
 	Favorite    bool
 	Completed   bool
 	ItemsID     []string
+	Version     int
 	DateCreated time.Time
 	DateUpdated time.Time
 }
@@ -31,6 +36,8 @@ type UpdateList struct {
 	Favorite    *bool
 	Completed   *bool
 	ItemsID     []string
+	// IfMatch holds the versions the update may be based on
+	IfMatch web.Precondition
 }
 
 type Item struct {
@@ -44,6 +51,7 @@ type Item struct {
 	Point       Point
 	ImagesID    []string
 	Visited     bool
+	Version     int
 	DateCreated time.Time
 	DateUpdated time.Time
 }
@@ -63,6 +71,7 @@ type NewItem struct {
 	Address     *string
 	Point       NewPoint
 	ImagesID    []string
+	Visited     bool
 }
 
 type NewPoint struct {
@@ -80,9 +89,224 @@ type UpdateItem struct {
 	Point       *UpdatePoint
 	ImagesID    []string
 	Visited     *bool
+	// IfMatch holds the versions the update may be based on
+	IfMatch web.Precondition
 }
 
 type UpdatePoint struct {
 	Lat float64
 	Lng float64
 }
+
+// Role is the access level a user has on a list.
+type Role string
+
+const (
+	RoleViewer Role = "viewer"
+	RoleEditor Role = "editor"
+	RoleOwner  Role = "owner"
+)
+
+var roleRank = map[Role]int{
+	RoleViewer: 1,
+	RoleEditor: 2,
+	RoleOwner:  3,
+}
+
+// Allows reports whether r grants at least the access level of want.
+func (r Role) Allows(want Role) bool {
+	return roleRank[r] > 0 && roleRank[r] >= roleRank[want]
+}
+
+type Member struct {
+	ListID      string
+	UserID      string
+	Name        string
+	Email       string
+	Role        Role
+	InvitedBy   string
+	DateCreated time.Time
+	DateUpdated time.Time
+}
+
+type NewMember struct {
+	ListID    string
+	UserID    *string
+	Email     *string
+	Role      Role
+	InvitedBy string
+}
+
+type UpdateMember struct {
+	ListID    string
+	UserID    string
+	Role      Role
+	UpdatedBy string
+}
+
+type User struct {
+	ID        string
+	Name      string
+	Email     string
+	IsActive  bool
+	IsDeleted bool
+}
+
+// Invite holds everything needed to notify a newly added member.
+type Invite struct {
+	Member      Member
+	ListName    string
+	InviterName string
+}
+
+// Share is a link to a list. The token is known only when the share is
+// created, the storer keeps its hash.
+type Share struct {
+	ID          string
+	ListID      string
+	Token       string
+	TokenHash   string
+	CreatedBy   string
+	DateCreated time.Time
+}
+
+type NearQuery struct {
+	UserID   string
+	Lat      float64
+	Lng      float64
+	Radius   float64
+	Page     int
+	PageSize int
+}
+
+type BBoxQuery struct {
+	UserID   string
+	MinLat   float64
+	MinLng   float64
+	MaxLat   float64
+	MaxLng   float64
+	Page     int
+	PageSize int
+}
+
+// GeoItem is an item found by a geo search with its distance in meters
+// from the search origin.
+type GeoItem struct {
+	Item
+	Distance float64
+}
+
+type OptimizeRoute struct {
+	ListID        string
+	UserID        string
+	StartItemID   *string
+	EndItemID     *string
+	UnvisitedOnly bool
+	Persist       bool
+}
+
+// Route is a suggested visiting order of list items with its length in meters.
+type Route struct {
+	ListID   string
+	ItemsID  []string
+	Distance float64
+}
+
+// ImportResult is the outcome of importing a single item,
+// Err is set when the item was not created.
+type ImportResult struct {
+	Item Item
+	Err  error
+}
+
+// Sort keys of list and item collections,
+// SortOrder is the order of items in their list.
+const (
+	SortDateCreated = "date_created"
+	SortDateUpdated = "date_updated"
+	SortName        = "name"
+	SortOrder       = "order"
+)
+
+// Cursor points at the last element of a page by its sort value and ID.
+type Cursor struct {
+	Value string
+	ID    string
+}
+
+type Page struct {
+	Limit  int
+	Cursor *Cursor
+	Sort   string
+	Desc   bool
+}
+
+type ListsQuery struct {
+	UserID    string
+	Favorite  *bool
+	Completed *bool
+	Private   *bool
+	Page      Page
+}
+
+type ItemsQuery struct {
+	ListID  string
+	Visited *bool
+	Page    Page
+}
+
+// ListsPage is a page of lists, NextCursor is nil on the last page.
+// Total counts all the lists matching the query.
+type ListsPage struct {
+	Lists      []List
+	NextCursor *Cursor
+	Total      int
+}
+
+// ItemsPage is a page of items, NextCursor is nil on the last page.
+// Total counts all the items matching the query.
+type ItemsPage struct {
+	Items      []Item
+	NextCursor *Cursor
+	Total      int
+}
+
+type SearchQuery struct {
+	UserID string
+	Query  string
+	Limit  int
+}
+
+// SearchMatch is a list or, when ItemID is set, an item matching a search.
+// Snippet has the matched words wrapped in SearchStartSel and SearchStopSel.
+type SearchMatch struct {
+	ListID   string
+	ListName string
+	ItemID   *string
+	ItemName *string
+	Snippet  string
+	Rank     float64
+}
+
+// SearchGroup is a list with its matching items, Snippet is nil
+// if only the items of the list matched. Rank is the best rank of the group.
+type SearchGroup struct {
+	ListID   string
+	ListName string
+	Snippet  *string
+	Rank     float64
+	Items    []SearchMatch
+}
+
+// Event is a change of a list or of its items. ItemID and ImageID are set
+// for item and image events, Version is the version after the change.
+type Event struct {
+	ID      string
+	Type    string
+	ListID  string
+	ItemID  *string
+	ImageID *string
+	UserID  string
+	Version *int
+	Date    time.Time
+}
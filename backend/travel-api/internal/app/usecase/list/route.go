@@ -0,0 +1,130 @@
+package list
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/route"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// OptimizeRoute suggests a visiting order of the list items.
+// When asked to persist, the order is written to the list, items left out
+// of the route keep their previous relative order after the routed ones.
+func (c *Core) OptimizeRoute(ctx context.Context, or OptimizeRoute) (Route, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.optimize-route")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	want := RoleViewer
+	if or.Persist {
+		want = RoleEditor
+	}
+	if err := c.authorize(ctx, or.UserID, or.ListID, want); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("route: optimize: authorize")
+		return Route{}, err
+	}
+	items, err := c.storer.QueryItemsByListID(ctx, or.ListID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("route: optimize: %s", database.ErrQueryDB.Error())
+		return Route{}, database.WrapStorerError(err)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].DateCreated.Equal(items[j].DateCreated) {
+			return items[i].ID < items[j].ID
+		}
+		return items[i].DateCreated.Before(items[j].DateCreated)
+	})
+
+	var stops []Item
+	start, end := route.NoPoint, route.NoPoint
+	for _, item := range items {
+		isStart := or.StartItemID != nil && *or.StartItemID == item.ID
+		isEnd := or.EndItemID != nil && *or.EndItemID == item.ID
+		// fixed ends are kept even if they were visited
+		if or.UnvisitedOnly && item.Visited && !isStart && !isEnd {
+			continue
+		}
+		if isStart {
+			start = len(stops)
+		}
+		if isEnd {
+			end = len(stops)
+		}
+		stops = append(stops, item)
+	}
+	if (or.StartItemID != nil && start == route.NoPoint) || (or.EndItemID != nil && end == route.NoPoint) {
+		c.log.Error().Str("TraceID", tID).Msgf("route: optimize: fixed item: %s", web.ErrNotFound.Error())
+		return Route{}, web.ErrNotFound
+	}
+
+	points := make([]route.Point, len(stops))
+	for i, item := range stops {
+		points[i] = route.Point{Lat: item.Point.Lat, Lng: item.Point.Lng}
+	}
+	order, dist := route.Optimize(points, start, end)
+	ids := make([]string, len(order))
+	for i, idx := range order {
+		ids[i] = stops[idx].ID
+	}
+	r := Route{
+		ListID:   or.ListID,
+		ItemsID:  ids,
+		Distance: dist,
+	}
+	if !or.Persist {
+		return r, nil
+	}
+
+	list, err := c.storer.QueryListByID(ctx, or.ListID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("route: persist: %s", database.ErrQueryDB.Error())
+		return Route{}, database.WrapStorerError(err)
+	}
+	before := auditList(list)
+	list.ItemsID = mergeOrder(ids, list.ItemsID, items)
+	list.DateUpdated = time.Now().UTC()
+	if err := c.storer.UpdateList(ctx, list); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("route: persist: %s", database.ErrQueryDB.Error())
+		return Route{}, database.WrapStorerError(err)
+	}
+	list.Version++
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionListUpdate,
+		TargetType: audit.TargetList,
+		TargetID:   list.ID,
+		Before:     before,
+		After:      auditList(list),
+	})
+	c.publish(ctx, Event{Type: EventListUpdated, ListID: list.ID, UserID: or.UserID, Version: &list.Version})
+	return r, nil
+}
+
+// mergeOrder puts the routed ids first, then the rest of the previous order,
+// then any item that was missing from the previous order.
+func mergeOrder(routed []string, prev []string, items []Item) []string {
+	seen := make(map[string]bool, len(items))
+	exists := make(map[string]bool, len(items))
+	for _, item := range items {
+		exists[item.ID] = true
+	}
+	res := make([]string, 0, len(items))
+	add := func(id string) {
+		if exists[id] && !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	for _, id := range routed {
+		add(id)
+	}
+	for _, id := range prev {
+		add(id)
+	}
+	for _, item := range items {
+		add(item.ID)
+	}
+	return res
+}
@@ -0,0 +1,62 @@
+package list
+
+import (
+	"context"
+	"sort"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+// Markers of the matched words in search snippets.
+const (
+	SearchStartSel = "<mark>"
+	SearchStopSel  = "</mark>"
+)
+
+// Search finds lists and items by their names, descriptions and addresses
+// and groups the matches by list, best ranked first. Only the lists
+// the user owns, is a member of, or that are public are searched.
+func (c *Core) Search(ctx context.Context, q SearchQuery) ([]SearchGroup, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.search")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	_, q.Limit = normalizePage(1, q.Limit)
+	ms, err := c.storer.Search(ctx, q)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("search: query: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return groupMatches(ms), nil
+}
+
+func groupMatches(ms []SearchMatch) []SearchGroup {
+	groups := []SearchGroup{}
+	byList := make(map[string]int)
+	for _, m := range ms {
+		i, ok := byList[m.ListID]
+		if !ok {
+			i = len(groups)
+			byList[m.ListID] = i
+			groups = append(groups, SearchGroup{
+				ListID:   m.ListID,
+				ListName: m.ListName,
+				Items:    []SearchMatch{},
+			})
+		}
+		g := &groups[i]
+		if m.Rank > g.Rank {
+			g.Rank = m.Rank
+		}
+		if m.ItemID == nil {
+			snippet := m.Snippet
+			g.Snippet = &snippet
+			continue
+		}
+		g.Items = append(g.Items, m)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return groups[i].Rank > groups[j].Rank
+	})
+	return groups
+}
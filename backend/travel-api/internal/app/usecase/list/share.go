@@ -0,0 +1,116 @@
+package list
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/google/uuid"
+)
+
+// GetPublicList returns a list and its items without authentication.
+// The slug is either the id of a non-private list or a share token.
+func (c *Core) GetPublicList(ctx context.Context, slug string) (List, []Item, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.get-public-list")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	list, err := c.storer.QueryPublicListBySlug(ctx, slug, hashShareToken(slug))
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("public list: query: %s", database.ErrQueryDB.Error())
+		return List{}, nil, database.WrapStorerError(err)
+	}
+	is, err := c.storer.QueryItemsByListID(ctx, list.ID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("public list: query items: %s", database.ErrQueryDB.Error())
+		return List{}, nil, database.WrapStorerError(err)
+	}
+	return list, is, nil
+}
+
+// GetShares returns the share links of the list, their tokens are not
+// stored and so not returned.
+func (c *Core) GetShares(ctx context.Context, userID string, listID string) ([]Share, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.get-shares")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleOwner); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("shares: query: authorize")
+		return nil, err
+	}
+	ss, err := c.storer.QueryShares(ctx, listID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("shares: query: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return ss, nil
+}
+
+// CreateShare creates a share link to the list. The token is returned
+// only here, the storer keeps its hash.
+func (c *Core) CreateShare(ctx context.Context, userID string, listID string) (Share, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.list.create-share")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleOwner); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("share: create: authorize")
+		return Share{}, err
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("share: create: generate token")
+		return Share{}, err
+	}
+	t := hex.EncodeToString(token)
+	share := Share{
+		ID:          uuid.New().String(),
+		ListID:      listID,
+		Token:       t,
+		TokenHash:   hashShareToken(t),
+		CreatedBy:   userID,
+		DateCreated: time.Now().UTC(),
+	}
+	if err := c.storer.CreateShare(ctx, share); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("share: create: %s", database.ErrQueryDB.Error())
+		return Share{}, database.WrapStorerError(err)
+	}
+	// the token grants access, only its id is recorded
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionShareCreate,
+		TargetType: audit.TargetList,
+		TargetID:   listID,
+		After:      audit.Fields{"share_id": share.ID},
+	})
+	return share, nil
+}
+
+func (c *Core) DeleteShare(ctx context.Context, userID string, listID string, shareID string) error {
+	ctx, span := web.AddSpan(ctx, "usecase.list.delete-share")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.authorize(ctx, userID, listID, RoleOwner); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("share: delete: authorize")
+		return err
+	}
+	if err := c.storer.DeleteShare(ctx, listID, shareID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("share: delete: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionShareDelete,
+		TargetType: audit.TargetList,
+		TargetID:   listID,
+		Before:     audit.Fields{"share_id": shareID},
+	})
+	return nil
+}
+
+// hashShareToken returns the hash share tokens are stored and looked up by.
+func hashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
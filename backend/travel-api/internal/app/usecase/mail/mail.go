@@ -11,6 +11,12 @@ import (
 type Sender interface {
 	SendResetPwdEmail(ctx context.Context, l Letter) error
 	SendRegisterEmail(ctx context.Context, l Letter) error
+	SendInviteEmail(ctx context.Context, l Letter) error
+	SendUnlockEmail(ctx context.Context, l Letter) error
+	SendEmailConfirmEmail(ctx context.Context, l Letter) error
+	SendEmailNoticeEmail(ctx context.Context, l Letter) error
+	SendMagicLinkEmail(ctx context.Context, l Letter) error
+	SendDataExportEmail(ctx context.Context, l Letter) error
 }
 
 type Core struct {
@@ -64,3 +70,121 @@ func (c *Core) SendVerifyMessage(ctx context.Context, m MessageVerify) error {
 	}
 	return c.sender.SendRegisterEmail(ctx, l)
 }
+
+func (c *Core) SendUnlockMessage(ctx context.Context, m MessageUnlock) error {
+	ctx, span := web.AddSpan(ctx, "usecase.mail.send-unlock-message")
+	defer span.End()
+	sub := "Sign in locked"
+	head := fmt.Sprintf("Hello %s", m.Name)
+	body := `There were too many failed attempts to sign in to your account,
+	 so signing in is locked for a while. If that was you, follow the provided
+	 link to unlock it now. If it was not, consider changing your password.`
+
+	l := Letter{
+		To:      m.Email,
+		Name:    m.Name,
+		Subject: sub,
+		Header:  head,
+		Token:   m.UnlockToken,
+		Body:    body,
+	}
+	return c.sender.SendUnlockEmail(ctx, l)
+}
+
+func (c *Core) SendEmailConfirmMessage(ctx context.Context, m MessageEmailConfirm) error {
+	ctx, span := web.AddSpan(ctx, "usecase.mail.send-email-confirm-message")
+	defer span.End()
+	sub := "Confirm your new email"
+	head := fmt.Sprintf("Hello %s", m.Name)
+	body := `You (or somebody on your behalf) have requested to use this
+	 email for your Traillyst account. If that was not you, just ignore this letter.
+	 Otherwise, please, follow the provided link to confirm the new email.`
+
+	l := Letter{
+		To:      m.Email,
+		Name:    m.Name,
+		Subject: sub,
+		Header:  head,
+		Token:   m.ConfirmToken,
+		Body:    body,
+	}
+	return c.sender.SendEmailConfirmEmail(ctx, l)
+}
+
+func (c *Core) SendEmailNoticeMessage(ctx context.Context, m MessageEmailNotice) error {
+	ctx, span := web.AddSpan(ctx, "usecase.mail.send-email-notice-message")
+	defer span.End()
+	sub := "Email change requested"
+	head := fmt.Sprintf("Hello %s", m.Name)
+	body := `A change of the email of your Traillyst account has been requested,
+	 the new email is used once confirmed from the new address. If that was not you,
+	 follow the provided link to reset your password.`
+
+	l := Letter{
+		To:      m.Email,
+		Name:    m.Name,
+		Subject: sub,
+		Header:  head,
+		Body:    body,
+	}
+	return c.sender.SendEmailNoticeEmail(ctx, l)
+}
+
+func (c *Core) SendMagicLinkMessage(ctx context.Context, m MessageMagicLink) error {
+	ctx, span := web.AddSpan(ctx, "usecase.mail.send-magic-link-message")
+	defer span.End()
+	sub := "Sign in to Traillyst"
+	head := fmt.Sprintf("Hello %s", m.Name)
+	body := `You (or somebody on your behalf) have requested a link to sign in.
+	 If that was not you, just ignore this letter. Otherwise, please, follow
+	 the provided link to sign in. The link works once and expires shortly.`
+
+	l := Letter{
+		To:      m.Email,
+		Name:    m.Name,
+		Subject: sub,
+		Header:  head,
+		Token:   m.LoginToken,
+		Body:    body,
+	}
+	return c.sender.SendMagicLinkEmail(ctx, l)
+}
+
+func (c *Core) SendDataExportMessage(ctx context.Context, m MessageDataExport) error {
+	ctx, span := web.AddSpan(ctx, "usecase.mail.send-data-export-message")
+	defer span.End()
+	sub := "Your data is ready"
+	head := fmt.Sprintf("Hello %s", m.Name)
+	body := `The archive of your Traillyst data you have requested is ready.
+	 Please, follow the provided link to download it. The link expires in a few days,
+	 after that you can request a new archive.`
+
+	l := Letter{
+		To:      m.Email,
+		Name:    m.Name,
+		Subject: sub,
+		Header:  head,
+		Token:   m.DownloadToken,
+		Body:    body,
+	}
+	return c.sender.SendDataExportEmail(ctx, l)
+}
+
+func (c *Core) SendInviteMessage(ctx context.Context, m MessageInvite) error {
+	ctx, span := web.AddSpan(ctx, "usecase.mail.send-invite-message")
+	defer span.End()
+	sub := "List shared with you"
+	head := fmt.Sprintf("Hello %s", m.Name)
+	body := fmt.Sprintf(`%s has shared the list "%s" with you on Traillyst.
+	 Please, follow the provided link to open it.`, m.Sender, m.ListName)
+
+	l := Letter{
+		To:      m.Email,
+		Name:    m.Name,
+		Subject: sub,
+		Header:  head,
+		Body:    body,
+		ListID:  m.ListID,
+	}
+	return c.sender.SendInviteEmail(ctx, l)
+}
@@ -7,6 +7,7 @@ type Letter struct {
 	Header  string
 	Body    string
 	Token   string
+	ListID  string
 }
 
 type MessageReset struct {
@@ -20,3 +21,40 @@ type MessageVerify struct {
 	Name        string
 	VerifyToken string
 }
+
+type MessageUnlock struct {
+	Email       string
+	Name        string
+	UnlockToken string
+}
+
+type MessageInvite struct {
+	Email    string
+	Name     string
+	ListID   string
+	ListName string
+	Sender   string
+}
+
+type MessageEmailConfirm struct {
+	Email        string
+	Name         string
+	ConfirmToken string
+}
+
+type MessageEmailNotice struct {
+	Email string
+	Name  string
+}
+
+type MessageMagicLink struct {
+	Email      string
+	Name       string
+	LoginToken string
+}
+
+type MessageDataExport struct {
+	Email         string
+	Name          string
+	DownloadToken string
+}
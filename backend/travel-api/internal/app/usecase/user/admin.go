@@ -0,0 +1,160 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// QueryAll returns a page of all users matching the query,
+// deleted users included unless filtered out.
+func (c *Core) QueryAll(ctx context.Context, q UsersQuery) (UsersPage, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.query-all")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	q.Page, q.PageSize = normalizePage(q.Page, q.PageSize)
+	up, err := c.storer.QueryAll(ctx, q)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: query all: %s", database.ErrQueryDB.Error())
+		return UsersPage{}, database.WrapStorerError(err)
+	}
+	return up, nil
+}
+
+// SetActive activates or deactivates the user, a deactivated user
+// is logged out everywhere and cannot log in.
+func (c *Core) SetActive(ctx context.Context, userID string, active bool) (User, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.set-active")
+	defer span.End()
+	action := audit.ActionUserActivate
+	if !active {
+		action = audit.ActionUserDeactivate
+	}
+	return c.updateAccount(ctx, "set active", action, userID, func(u *User) {
+		if u.IsActive && !active {
+			u.TokenVersion = u.TokenVersion + 1
+		}
+		u.IsActive = active
+	})
+}
+
+// SoftDelete marks the user deleted the way the user deletes themself,
+// the data is kept so that the user can be restored.
+func (c *Core) SoftDelete(ctx context.Context, userID string) (User, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.soft-delete")
+	defer span.End()
+	return c.updateAccount(ctx, "soft delete", audit.ActionUserDelete, userID, func(u *User) {
+		u.IsActive = false
+		u.IsDeleted = true
+		u.TokenVersion = u.TokenVersion + 1
+	})
+}
+
+// Restore clears the deleted mark, the user stays inactive until activated.
+func (c *Core) Restore(ctx context.Context, userID string) (User, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.restore")
+	defer span.End()
+	return c.updateAccount(ctx, "restore", audit.ActionUserRestore, userID, func(u *User) {
+		u.IsDeleted = false
+	})
+}
+
+// UpdateRoles replaces the roles of the user. Roles travel in the tokens,
+// so the user is logged out everywhere for the new roles to apply.
+// Only the roles granting no more than the admin holds can be assigned.
+func (c *Core) UpdateRoles(ctx context.Context, userID string, roles []string) (User, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.update-roles")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: update roles: %s", auth.ErrGetClaims.Error())
+		return User{}, auth.ErrGetClaims
+	}
+	if !claims.CanAll(c.roles.Permissions(roles)...) {
+		c.log.Error().Str("TraceID", tID).Msgf("user: update roles: %s", web.ErrForbidden.Error())
+		return User{}, web.ErrForbidden
+	}
+	ok, err := c.storer.RolesExist(ctx, roles)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: update roles: %s", database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	if !ok {
+		c.log.Error().Str("TraceID", tID).Msgf("user: update roles: %s", web.ErrPreconditionFailed.Error())
+		return User{}, web.ErrPreconditionFailed
+	}
+	return c.updateAccount(ctx, "update roles", audit.ActionUserRolesUpdate, userID, func(u *User) {
+		u.Roles = roles
+		u.TokenVersion = u.TokenVersion + 1
+	})
+}
+
+// updateAccount applies the change to the user on behalf of an admin,
+// admins cannot change their own account this way to not lock themselves out.
+// Neither can they change the account of a user holding a permission
+// they lack.
+func (c *Core) updateAccount(
+	ctx context.Context,
+	op string,
+	action string,
+	userID string,
+	change func(u *User),
+) (User, error) {
+	tID := web.GetTraceID(ctx)
+	claims, err := auth.GetClaims(ctx)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: %s: %s", op, auth.ErrGetClaims.Error())
+		return User{}, auth.ErrGetClaims
+	}
+	if claims.Subject == userID {
+		c.log.Error().Str("TraceID", tID).Msgf("user: %s: %s", op, web.ErrForbidden.Error())
+		return User{}, web.ErrForbidden
+	}
+	u, err := c.storer.QueryByID(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: %s: %s", op, database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	if !claims.CanAll(c.roles.Permissions(u.Roles)...) {
+		c.log.Error().Str("TraceID", tID).Msgf("user: %s: %s", op, web.ErrForbidden.Error())
+		return User{}, web.ErrForbidden
+	}
+	before := auditUser(u)
+	change(&u)
+	u.DateUpdated = time.Now().UTC()
+	if err := c.storer.UpdateAccount(ctx, u); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: %s: %s", op, database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     action,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		Before:     before,
+		After:      auditUser(u),
+	})
+	return u, nil
+}
+
+func normalizePage(page int, size int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = defaultPageSize
+	}
+	if size > maxPageSize {
+		size = maxPageSize
+	}
+	return page, size
+}
@@ -0,0 +1,22 @@
+package user
+
+import "github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+
+func auditUser(u User) audit.Fields {
+	return audit.Fields{
+		"name":       u.Name,
+		"email":      u.Email,
+		"is_active":  u.IsActive,
+		"is_deleted": u.IsDeleted,
+		"roles":      u.Roles,
+	}
+}
+
+func auditAccessToken(at AccessToken) audit.Fields {
+	return audit.Fields{
+		"user_id":    at.UserID,
+		"name":       at.Name,
+		"scopes":     at.Scopes,
+		"expires_at": at.ExpiresAt,
+	}
+}
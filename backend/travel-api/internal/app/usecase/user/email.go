@@ -0,0 +1,118 @@
+package user
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+)
+
+const emailChangeTTL = 24 * time.Hour
+
+// RequestEmailChange keeps the new email of the user pending until it is
+// confirmed with the returned token, only the latest request can be confirmed.
+func (c *Core) RequestEmailChange(ctx context.Context, ce ChangeEmail) (User, string, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.request-email-change")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	u, err := c.storer.QueryByID(ctx, ce.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: request email change: %s", database.ErrQueryDB.Error())
+		return User{}, "", database.WrapStorerError(err)
+	}
+	if err := c.hasher.Compare(u.PasswordHash, ce.Password); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: request email change: %s", web.ErrAuthFailed.Error())
+		return User{}, "", web.ErrAuthFailed
+	}
+	// the current email of the user counts as taken too
+	if _, err := c.storer.QueryByEmail(ctx, ce.Email); err == nil {
+		c.log.Error().Str("TraceID", tID).Msgf("user: request email change: %s", web.ErrAlreadyExists.Error())
+		return User{}, "", web.ErrAlreadyExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: request email change: %s", database.ErrQueryDB.Error())
+		return User{}, "", database.WrapStorerError(err)
+	}
+	if err := c.storer.DeleteEmailChangeTokensByUserID(ctx, u.ID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: request email change: %s", database.ErrQueryDB.Error())
+		return User{}, "", database.WrapStorerError(err)
+	}
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: request email change: %s", auth.ErrGenResetToken.Error())
+		return User{}, "", auth.ErrGenResetToken
+	}
+	now := time.Now().UTC()
+	vt := VerifyToken{
+		TokenID:   hex.EncodeToString(token),
+		UserID:    u.ID,
+		Email:     ce.Email,
+		ExpiresAt: now.Add(emailChangeTTL),
+		IssuedAt:  now,
+	}
+	if err := c.storer.StoreEmailChangeToken(ctx, vt); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: request email change: %s", database.ErrQueryDB.Error())
+		return User{}, "", database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionEmailRequest,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		After:      audit.Fields{"email": ce.Email},
+	})
+	return u, vt.TokenID, nil
+}
+
+// ConfirmEmailChange sets the pending email of the token as the email of
+// the user. The email is the login, so the user is logged out everywhere.
+func (c *Core) ConfirmEmailChange(ctx context.Context, token string) (User, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.confirm-email-change")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	vt, err := c.storer.QueryEmailChangeToken(ctx, token)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: confirm email change: %s", database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	// an expired token is as good as a missing one
+	if vt.ExpiresAt.Before(time.Now().UTC()) {
+		c.log.Error().Str("TraceID", tID).Msgf("user: confirm email change: %s", auth.ErrValidateVerifyToken.Error())
+		return User{}, web.ErrNotFound
+	}
+	if err := c.storer.DeleteEmailChangeTokensByUserID(ctx, vt.UserID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: confirm email change: %s", database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	u, err := c.storer.QueryByID(ctx, vt.UserID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: confirm email change: %s", database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	if u.IsDeleted {
+		return User{}, web.ErrNotFound
+	}
+	before := auditUser(u)
+	u.Email = vt.Email
+	u.TokenVersion = u.TokenVersion + 1
+	u.DateUpdated = time.Now().UTC()
+	// the email might have been taken since the request
+	if err := c.storer.UpdateEmail(ctx, u); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: confirm email change: %s", database.ErrQueryDB.Error())
+		return User{}, database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionEmailChange,
+		ActorID:    u.ID,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		Before:     before,
+		After:      auditUser(u),
+	})
+	return u, nil
+}
@@ -19,16 +19,38 @@ type User struct {
 	DateUpdated  time.Time
 }
 
+// UsersQuery filters the users listed to admins, nil filters match all.
+type UsersQuery struct {
+	// Search matches a part of the name or email.
+	Search    string
+	IsActive  *bool
+	IsDeleted *bool
+	Page      int
+	PageSize  int
+}
+
+type UsersPage struct {
+	Users []User
+	Total int
+}
+
 type NewUser struct {
 	Name     string
 	Email    string
 	Password string
 }
 
+// UpdateUser changes the profile of the user, the email is changed
+// through ChangeEmail as the new address has to be confirmed first.
 type UpdateUser struct {
 	ID       string
 	Name     *string
-	Email    *string
+	Password string
+}
+
+type ChangeEmail struct {
+	UserID   string
+	Email    string
 	Password string
 }
 
@@ -49,3 +71,24 @@ type VerifyToken struct {
 	ExpiresAt time.Time
 	IssuedAt  time.Time
 }
+
+// AccessToken is a personal access token, the token itself is only
+// returned when it is created.
+type AccessToken struct {
+	ID          string
+	UserID      string
+	Name        string
+	TokenHash   string
+	Scopes      pq.StringArray
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	DateCreated time.Time
+}
+
+type NewAccessToken struct {
+	UserID string
+	Name   string
+	Scopes []string
+	// ExpiresIn of zero makes a token that does not expire.
+	ExpiresIn time.Duration
+}
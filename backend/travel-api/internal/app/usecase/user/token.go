@@ -0,0 +1,83 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/google/uuid"
+)
+
+// CreateAccessToken creates a personal access token of the user and
+// returns it along with the token, which is not stored and cannot be
+// shown again.
+func (c *Core) CreateAccessToken(ctx context.Context, nt NewAccessToken) (AccessToken, string, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.create-access-token")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	token, hash, err := auth.GeneratePersonalToken()
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: create access token: %s", auth.ErrGenPersonalToken.Error())
+		return AccessToken{}, "", auth.ErrGenPersonalToken
+	}
+	now := time.Now().UTC()
+	at := AccessToken{
+		ID:          uuid.New().String(),
+		UserID:      nt.UserID,
+		Name:        nt.Name,
+		TokenHash:   hash,
+		Scopes:      nt.Scopes,
+		DateCreated: now,
+	}
+	if nt.ExpiresIn > 0 {
+		ea := now.Add(nt.ExpiresIn)
+		at.ExpiresAt = &ea
+	}
+	if err := c.storer.CreateAccessToken(ctx, at); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: create access token: %s", database.ErrQueryDB.Error())
+		return AccessToken{}, "", database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionTokenCreate,
+		TargetType: audit.TargetToken,
+		TargetID:   at.ID,
+		After:      auditAccessToken(at),
+	})
+	return at, token, nil
+}
+
+// GetAccessTokens returns the personal access tokens of the user,
+// expired ones included until they are deleted.
+func (c *Core) GetAccessTokens(ctx context.Context, userID string) ([]AccessToken, error) {
+	ctx, span := web.AddSpan(ctx, "usecase.user.get-access-tokens")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ts, err := c.storer.QueryAccessTokens(ctx, userID)
+	if err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: get access tokens: %s", database.ErrQueryDB.Error())
+		return nil, database.WrapStorerError(err)
+	}
+	return ts, nil
+}
+
+// DeleteAccessToken revokes the personal access token, the token of
+// another user is reported as not found.
+func (c *Core) DeleteAccessToken(ctx context.Context, userID string, tokenID string) error {
+	ctx, span := web.AddSpan(ctx, "usecase.user.delete-access-token")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	if err := c.storer.DeleteAccessToken(ctx, userID, tokenID); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msgf("user: delete access token: %s", database.ErrQueryDB.Error())
+		return database.WrapStorerError(err)
+	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionTokenDelete,
+		TargetType: audit.TargetToken,
+		TargetID:   tokenID,
+		Before:     audit.Fields{"user_id": userID},
+	})
+	return nil
+}
@@ -6,12 +6,12 @@ import (
 	"encoding/hex"
 	"time"
 
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/audit"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/database"
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type Storer interface {
@@ -19,12 +19,38 @@ type Storer interface {
 	Update(ctx context.Context, user User) error
 	Delete(ctx context.Context, user User) error
 	Verify(ctx context.Context, user User) error
-	QueryAll(ctx context.Context) ([]User, error)
+	UpdateAccount(ctx context.Context, user User) error
+	QueryAll(ctx context.Context, q UsersQuery) (UsersPage, error)
+	RolesExist(ctx context.Context, roles []string) (bool, error)
 	QueryByID(ctx context.Context, userID string) (User, error)
 	QueryByEmail(ctx context.Context, email string) (User, error)
 	QueryTokenByEmail(ctx context.Context, email string) (VerifyToken, error)
 	DeleteVerifyTokensByUserID(ctx context.Context, userID string) error
 	StoreVerifyToken(ctx context.Context, vt VerifyToken) error
+	UpdateEmail(ctx context.Context, user User) error
+	QueryEmailChangeToken(ctx context.Context, tokenID string) (VerifyToken, error)
+	DeleteEmailChangeTokensByUserID(ctx context.Context, userID string) error
+	StoreEmailChangeToken(ctx context.Context, vt VerifyToken) error
+	CreateAccessToken(ctx context.Context, at AccessToken) error
+	QueryAccessTokens(ctx context.Context, userID string) ([]AccessToken, error)
+	DeleteAccessToken(ctx context.Context, userID string, tokenID string) error
+}
+
+// Hasher hashes passwords and checks them against the stored hashes.
+type Hasher interface {
+	Hash(password string) ([]byte, error)
+	Compare(hash []byte, password string) error
+}
+
+// Policy refuses the passwords which are weak, breached or made of the
+// user inputs such as the name and email.
+type Policy interface {
+	Check(password string, userInputs ...string) error
+}
+
+// Roles resolves the permissions the roles grant.
+type Roles interface {
+	Permissions(roles []string) []string
 }
 
 // Core unit implements a set of methods for model types transformation.
@@ -34,33 +60,33 @@ type Storer interface {
 
 type Core struct {
 	storer Storer
+	hasher Hasher
+	policy Policy
+	roles  Roles
+	audit  *audit.Log
 	log    *zerolog.Logger
 }
 
-func NewCore(l *zerolog.Logger, s Storer) *Core {
+func NewCore(l *zerolog.Logger, s Storer, h Hasher, p Policy, r Roles, a *audit.Log) *Core {
 	return &Core{
 		storer: s,
+		hasher: h,
+		policy: p,
+		roles:  r,
+		audit:  a,
 		log:    l,
 	}
 }
 
-func (c *Core) QueryAll(ctx context.Context) ([]User, error) {
-	ctx, span := web.AddSpan(ctx, "usecase.user.query-all")
-	defer span.End()
-	tID := web.GetTraceID(ctx)
-	us, err := c.storer.QueryAll(ctx)
-	if err != nil {
-		c.log.Err(err).Str("TraceID", tID).Msgf("user: query all: %s", database.ErrQueryDB.Error())
-		return []User{}, database.WrapStorerError(err)
-	}
-	return us, nil
-}
-
 func (c *Core) Create(ctx context.Context, nu NewUser) (User, string, error) {
 	ctx, span := web.AddSpan(ctx, "usecase.user.create")
 	defer span.End()
 	tID := web.GetTraceID(ctx)
-	hash, err := bcrypt.GenerateFromPassword([]byte(nu.Password), bcrypt.DefaultCost)
+	if err := c.policy.Check(nu.Password, nu.Name, nu.Email); err != nil {
+		c.log.Err(err).Str("TraceID", tID).Msg("user: create: password refused")
+		return User{}, "", web.NewFieldsError("password", err)
+	}
+	hash, err := c.hasher.Hash(nu.Password)
 	if err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: create: %s", auth.ErrGenHash.Error())
 		return User{}, "", auth.ErrGenHash
@@ -103,6 +129,13 @@ func (c *Core) Create(ctx context.Context, nu NewUser) (User, string, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: create: %s", database.ErrQueryDB.Error())
 		return User{}, "", database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionUserCreate,
+		ActorID:    u.ID,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		After:      auditUser(u),
+	})
 	return u, et, nil
 }
 
@@ -115,7 +148,7 @@ func (c *Core) Update(ctx context.Context, uu UpdateUser) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: update: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
-	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(uu.Password)); err != nil {
+	if err := c.hasher.Compare(u.PasswordHash, uu.Password); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: update: %s", web.ErrAuthFailed.Error())
 		return User{}, web.ErrAuthFailed
 	}
@@ -125,21 +158,26 @@ func (c *Core) Update(ctx context.Context, uu UpdateUser) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: update: %s", auth.ErrGetClaims.Error())
 		return User{}, auth.ErrGetClaims
 	}
-	if !claims.Authorize(auth.RoleAdmin) && uu.ID != u.ID {
+	if !claims.Can(auth.PermUserWriteAny) && uu.ID != u.ID {
 		c.log.Error().Str("TraceID", tID).Msgf("user: update: %s", web.ErrForbidden.Error())
 		return User{}, web.ErrForbidden
 	}
+	before := auditUser(u)
 	if uu.Name != nil {
 		u.Name = *uu.Name
 	}
-	if uu.Email != nil {
-		u.Email = *uu.Email
-	}
 	u.DateUpdated = time.Now().UTC()
 	if err := c.storer.Update(ctx, u); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: update: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionUserUpdate,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		Before:     before,
+		After:      auditUser(u),
+	})
 	return u, nil
 }
 
@@ -166,12 +204,21 @@ func (c *Core) Verify(ctx context.Context, vu VerifyUser) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: verify: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
+	before := auditUser(u)
 	u.IsActive = true
 	u.DateUpdated = time.Now().UTC()
 	if err := c.storer.Verify(ctx, u); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: verify: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionUserVerify,
+		ActorID:    u.ID,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		Before:     before,
+		After:      auditUser(u),
+	})
 	return u, nil
 }
 
@@ -189,7 +236,7 @@ func (c *Core) QueryByID(ctx context.Context, uID string) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: query by id: %s", auth.ErrGetClaims.Error())
 		return User{}, auth.ErrGetClaims
 	}
-	if !claims.Authorize(auth.RoleAdmin) && uID != u.ID {
+	if !claims.Can(auth.PermUserReadAny) && uID != u.ID {
 		c.log.Error().Str("TraceID", tID).Msgf("user: query by id: %s", web.ErrForbidden.Error())
 		return User{}, web.ErrForbidden
 	}
@@ -205,7 +252,7 @@ func (c *Core) Delete(ctx context.Context, du DeleteUser) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: delete: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
-	if err := bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(du.Password)); err != nil {
+	if err := c.hasher.Compare(u.PasswordHash, du.Password); err != nil {
 		c.log.Err(err).Str("TraceID", tID).Msgf("auth: login: %s", web.ErrAuthFailed.Error())
 		return User{}, web.ErrAuthFailed
 	}
@@ -214,10 +261,11 @@ func (c *Core) Delete(ctx context.Context, du DeleteUser) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: delete: %s", auth.ErrGetClaims.Error())
 		return User{}, auth.ErrGetClaims
 	}
-	if !claims.Authorize(auth.RoleAdmin) && claims.Subject != du.ID {
+	if !claims.Can(auth.PermUserWriteAny) && claims.Subject != du.ID {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: delete: %s", web.ErrForbidden.Error())
 		return User{}, web.ErrForbidden
 	}
+	before := auditUser(u)
 	u.DateUpdated = time.Now().UTC()
 	u.IsActive = false
 	u.IsDeleted = true
@@ -226,5 +274,12 @@ func (c *Core) Delete(ctx context.Context, du DeleteUser) (User, error) {
 		c.log.Err(err).Str("TraceID", tID).Msgf("user: delete: %s", database.ErrQueryDB.Error())
 		return User{}, database.WrapStorerError(err)
 	}
+	c.audit.Record(ctx, audit.Entry{
+		Action:     audit.ActionUserDelete,
+		TargetType: audit.TargetUser,
+		TargetID:   u.ID,
+		Before:     before,
+		After:      auditUser(u),
+	})
 	return u, nil
 }
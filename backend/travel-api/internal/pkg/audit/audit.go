@@ -0,0 +1,186 @@
+// Package audit records security relevant and data changing actions in the
+// append-only audit_events table, to tell who did what, when and from where.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/auth"
+	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+)
+
+// Actions, named after the target and what was done to it.
+const (
+	ActionLogin            = "auth.login"
+	ActionLoginFailed      = "auth.login.failed"
+	ActionLogoutAll        = "auth.logout.all"
+	ActionPasswordChange   = "auth.password.change"
+	ActionPasswordReset    = "auth.password.reset"
+	ActionTOTPEnable       = "auth.2fa.enable"
+	ActionTOTPDisable      = "auth.2fa.disable"
+	ActionSessionTerminate = "auth.session.terminate"
+
+	ActionRoleUpdate = "role.update"
+	ActionRoleDelete = "role.delete"
+
+	ActionUserCreate      = "user.create"
+	ActionUserVerify      = "user.verify"
+	ActionUserUpdate      = "user.update"
+	ActionUserDelete      = "user.delete"
+	ActionUserActivate    = "user.activate"
+	ActionUserDeactivate  = "user.deactivate"
+	ActionUserRestore     = "user.restore"
+	ActionUserRolesUpdate = "user.roles.update"
+	ActionEmailRequest    = "user.email.request"
+	ActionEmailChange     = "user.email.change"
+	ActionTokenCreate     = "user.token.create"
+	ActionTokenDelete     = "user.token.delete"
+	ActionDataExport      = "user.data.export"
+
+	ActionListCreate   = "list.create"
+	ActionListUpdate   = "list.update"
+	ActionListDelete   = "list.delete"
+	ActionItemCreate   = "item.create"
+	ActionItemUpdate   = "item.update"
+	ActionItemDelete   = "item.delete"
+	ActionItemsImport  = "item.import"
+	ActionMemberAdd    = "list.member.add"
+	ActionMemberUpdate = "list.member.update"
+	ActionMemberDelete = "list.member.delete"
+	ActionShareCreate  = "list.share.create"
+	ActionShareDelete  = "list.share.delete"
+
+	ActionImageUpload = "image.upload"
+)
+
+// Target types.
+const (
+	TargetUser    = "user"
+	TargetRole    = "role"
+	TargetSession = "session"
+	TargetToken   = "access_token"
+	TargetList    = "list"
+	TargetItem    = "item"
+	TargetImage   = "image"
+)
+
+// Fields is a snapshot of the audited fields of a target,
+// secrets such as password hashes must be left out.
+type Fields map[string]any
+
+// Entry is an action to record. The actor is the user of the claims in the
+// context unless ActorID is set, trace ID and IP are taken from the context.
+type Entry struct {
+	Action     string
+	ActorID    string
+	TargetType string
+	TargetID   string
+	// Before and After are the target before and after the action, nil if
+	// it did not exist. Only the fields that differ are recorded.
+	Before Fields
+	After  Fields
+}
+
+type event struct {
+	EventID     string    `db:"event_id"`
+	ActorID     *string   `db:"actor_id"`
+	Action      string    `db:"action"`
+	TargetType  string    `db:"target_type"`
+	TargetID    string    `db:"target_id"`
+	TraceID     string    `db:"trace_id"`
+	IP          string    `db:"ip"`
+	Before      *string   `db:"before"`
+	After       *string   `db:"after"`
+	DateCreated time.Time `db:"date_created"`
+}
+
+type Log struct {
+	db  *sqlx.DB
+	log *zerolog.Logger
+}
+
+func New(db *sqlx.DB, log *zerolog.Logger) *Log {
+	return &Log{db: db, log: log}
+}
+
+// Record stores the entry. The action has already happened by then, so
+// a failure does not fail the action, the entry is logged instead.
+func (l *Log) Record(ctx context.Context, e Entry) {
+	ctx, span := web.AddSpan(ctx, "audit.record")
+	defer span.End()
+	tID := web.GetTraceID(ctx)
+	ev := event{
+		EventID:     uuid.New().String(),
+		Action:      e.Action,
+		TargetType:  e.TargetType,
+		TargetID:    e.TargetID,
+		TraceID:     tID,
+		IP:          web.GetClientIP(ctx),
+		DateCreated: time.Now().UTC(),
+	}
+	if e.ActorID != "" {
+		ev.ActorID = &e.ActorID
+	} else if claims, err := auth.GetClaims(ctx); err == nil {
+		ev.ActorID = &claims.Subject
+	}
+	before, after := diff(e.Before, e.After)
+	var err error
+	if ev.Before, err = marshal(before); err == nil {
+		ev.After, err = marshal(after)
+	}
+	if err != nil {
+		l.log.Err(err).Str("TraceID", tID).Interface("entry", e).Msg(ErrEncodeEvent.Error())
+		return
+	}
+	q := `INSERT INTO audit_events
+	(event_id, actor_id, action, target_type, target_id, trace_id, ip, before, after, date_created)
+	VALUES (:event_id, :actor_id, :action, :target_type, :target_id, :trace_id, :ip, :before, :after, :date_created);`
+	if _, err := l.db.NamedExecContext(ctx, q, ev); err != nil {
+		l.log.Err(err).Str("TraceID", tID).Interface("entry", e).Msg(ErrStoreEvent.Error())
+	}
+}
+
+// diff drops the fields that are equal before and after,
+// a target that is created or deleted is recorded whole.
+func diff(before Fields, after Fields) (Fields, Fields) {
+	if before == nil || after == nil {
+		return before, after
+	}
+	b, a := Fields{}, Fields{}
+	for k, v := range before {
+		if !equal(v, after[k]) {
+			b[k] = v
+		}
+	}
+	for k, v := range after {
+		if _, ok := b[k]; ok || !equal(before[k], v) {
+			a[k] = v
+		}
+	}
+	return b, a
+}
+
+// equal compares the values the way they are stored.
+func equal(x any, y any) bool {
+	bx, errX := json.Marshal(x)
+	by, errY := json.Marshal(y)
+	return errX == nil && errY == nil && string(bx) == string(by)
+}
+
+// marshal returns nil for nil fields to store NULL rather than an empty value.
+func marshal(f Fields) (*string, error) {
+	if f == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
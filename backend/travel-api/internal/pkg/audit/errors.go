@@ -0,0 +1,8 @@
+package audit
+
+import "errors"
+
+var (
+	ErrEncodeEvent = errors.New("error encoding audit event")
+	ErrStoreEvent  = errors.New("error storing audit event")
+)
@@ -2,7 +2,7 @@ package auth
 
 import (
 	"context"
-	"crypto/rsa"
+	"crypto"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -16,45 +16,52 @@ import (
 	"github.com/rs/zerolog"
 )
 
+const challengeDuration = 5 * time.Minute
+
 type KeyLookup interface {
-	PrivateKey(kid string) (*rsa.PrivateKey, error)
-	PublicKey(kid string) (*rsa.PublicKey, error)
+	PrivateKey(kid string) (crypto.PrivateKey, error)
+	PublicKey(kid string) (crypto.PublicKey, error)
+	Algorithm(kid string) (string, error)
+	SigningKeyID() (string, error)
+	PublicKeys() map[string]crypto.PublicKey
 }
 
 type Auth struct {
-	activeKIDs      []string
-	method          jwt.SigningMethod
 	keyFunc         func(t *jwt.Token) (any, error)
 	parser          *jwt.Parser
 	keyLookup       KeyLookup
+	policy          *Policy
 	cache           *redis.Client
 	db              *sqlx.DB
 	log             *zerolog.Logger
+	Issuer          string
 	AuthDuration    time.Duration
 	RefreshDuration time.Duration
 }
 
 type Config struct {
-	ActiveKIDs      []string
 	KeyLookup       KeyLookup
+	Policy          *Policy
 	Cache           *redis.Client
 	DB              *sqlx.DB
 	Log             *zerolog.Logger
+	Issuer          string
 	AuthDuration    time.Duration
 	RefreshDuration time.Duration
 }
 
 func New(cfg Config) (*Auth, error) {
 
-	for _, activeKID := range cfg.ActiveKIDs {
-		_, err := cfg.KeyLookup.PrivateKey(activeKID)
-		if err != nil {
-			cfg.Log.Err(err).Msg(ErrMissingKey.Error())
-			return nil, ErrMissingKey
-		}
+	if _, err := cfg.KeyLookup.SigningKeyID(); err != nil {
+		cfg.Log.Err(err).Msg(ErrMissingKey.Error())
+		return nil, ErrMissingKey
 	}
 
-	method := jwt.SigningMethodRS256
+	methods := []string{
+		jwt.SigningMethodRS256.Alg(),
+		jwt.SigningMethodES256.Alg(),
+		jwt.SigningMethodEdDSA.Alg(),
+	}
 
 	keyFunc := func(t *jwt.Token) (any, error) {
 		kid, ok := t.Header["kid"]
@@ -71,20 +78,30 @@ func New(cfg Config) (*Auth, error) {
 			return nil, ErrKIDFormat
 		}
 
+		// the algorithm comes with the key, never from the token
+		alg, err := cfg.KeyLookup.Algorithm(kidStr)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != alg {
+			cfg.Log.Error().Msg(ErrKeyAlgorithm.Error())
+			return nil, ErrKeyAlgorithm
+		}
+
 		return cfg.KeyLookup.PublicKey(kidStr)
 	}
 
-	parser := jwt.NewParser(jwt.WithValidMethods([]string{method.Name}))
+	parser := jwt.NewParser(jwt.WithValidMethods(methods))
 
 	a := Auth{
-		method:          jwt.GetSigningMethod(method.Name),
 		keyLookup:       cfg.KeyLookup,
+		policy:          cfg.Policy,
 		keyFunc:         keyFunc,
 		parser:          parser,
-		activeKIDs:      cfg.ActiveKIDs,
 		cache:           cfg.Cache,
 		db:              cfg.DB,
 		log:             cfg.Log,
+		Issuer:          cfg.Issuer,
 		AuthDuration:    cfg.AuthDuration,
 		RefreshDuration: cfg.RefreshDuration,
 	}
@@ -94,10 +111,30 @@ func New(cfg Config) (*Auth, error) {
 		return nil, ErrLoadRevokedTokens
 	}
 
+	if err := a.LoadRevokedFamiliesToCache(); err != nil {
+		a.log.Err(err).Msg(ErrLoadRevokedFamilies.Error())
+		return nil, ErrLoadRevokedFamilies
+	}
+
 	return &a, nil
 }
 
+// GenerateToken signs an access token.
 func (a *Auth) GenerateToken(ctx context.Context, claims Claims, duration time.Duration) (string, error) {
+	claims.Use = UseAccess
+	str, _, err := a.generateToken(ctx, claims, duration)
+	return str, err
+}
+
+// GenerateChallengeToken signs a token proving the password step of a login
+// of a user with two-factor authentication, it only grants the second step.
+func (a *Auth) GenerateChallengeToken(ctx context.Context, claims Claims) (string, error) {
+	claims.Use = UseChallenge
+	str, _, err := a.generateToken(ctx, claims, challengeDuration)
+	return str, err
+}
+
+func (a *Auth) generateToken(ctx context.Context, claims Claims, duration time.Duration) (string, Claims, error) {
 	ia := time.Now().UTC()
 	ea := ia.Add(duration)
 	jti := uuid.New().String()
@@ -105,31 +142,42 @@ func (a *Auth) GenerateToken(ctx context.Context, claims Claims, duration time.D
 	tv, err := a.getLastTokenVersion(ctx, claims)
 	if err != nil {
 		a.log.Err(err).Msg(ErrGetClaims.Error())
-		return "", ErrGetClaims
+		return "", Claims{}, ErrGetClaims
 	}
 	claims.TokenVersion = tv
 	claims.IssuedAt = &jwt.NumericDate{Time: ia}
 	claims.ExpiresAt = &jwt.NumericDate{Time: ea}
-	token := jwt.NewWithClaims(a.method, claims)
-	// TODO: select the most fresh KID
-	currentKID := a.activeKIDs[0]
+	currentKID, err := a.keyLookup.SigningKeyID()
+	if err != nil {
+		a.log.Err(err).Msg(ErrMissingKey.Error())
+		return "", Claims{}, ErrMissingKey
+	}
+	alg, err := a.keyLookup.Algorithm(currentKID)
+	if err != nil {
+		a.log.Err(err).Msg(ErrPrivateNotFound.Error())
+		return "", Claims{}, ErrPrivateNotFound
+	}
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(alg), claims)
 	token.Header["kid"] = currentKID
 	privateKey, err := a.keyLookup.PrivateKey(currentKID)
 	if err != nil {
 		a.log.Err(err).Msg(ErrPrivateNotFound.Error())
-		return "", ErrPrivateNotFound
+		return "", Claims{}, ErrPrivateNotFound
 	}
 
 	str, err := token.SignedString(privateKey)
 
 	if err != nil {
 		a.log.Err(err).Msg(ErrSigningToken.Error())
-		return "", ErrSigningToken
+		return "", Claims{}, ErrSigningToken
 	}
 
-	return str, nil
+	return str, claims, nil
 }
 
+// ValidateToken checks the signature and expiry of the token, that neither
+// the token nor its family is revoked, the latter ending the session the
+// token belongs to, and that the user token version did not change.
 func (a *Auth) ValidateToken(ctx context.Context, t string) (Claims, error) {
 	var claims Claims
 	token, err := a.parser.ParseWithClaims(t, &claims, a.keyFunc)
@@ -147,6 +195,17 @@ func (a *Auth) ValidateToken(ctx context.Context, t string) (Claims, error) {
 		a.log.Error().Msg(ErrRevokedToken.Error())
 		return Claims{}, ErrRevokedToken
 	}
+	if claims.Family != "" {
+		revoked, err := a.cache.Exists(ctx, familyKey(claims.Family)).Result()
+		if err != nil {
+			a.log.Err(err).Msg(ErrCheckCachedToken.Error())
+			return Claims{}, ErrCheckCachedToken
+		}
+		if revoked == 1 {
+			a.log.Error().Msg(ErrRevokedTokenFamily.Error())
+			return Claims{}, ErrRevokedTokenFamily
+		}
+	}
 	et, err := token.Claims.GetExpirationTime()
 	if err != nil {
 		a.log.Err(err).Msg(ErrValidateToken.Error())
@@ -172,6 +231,17 @@ func (a *Auth) ValidateToken(ctx context.Context, t string) (Claims, error) {
 	return claims, nil
 }
 
+// Permissions returns the permissions the roles grant.
+func (a *Auth) Permissions(roles []string) []string {
+	return a.policy.Permissions(roles)
+}
+
+// ReloadPolicy picks up changed role permissions right away, other
+// instances pick them up on their next periodic reload.
+func (a *Auth) ReloadPolicy(ctx context.Context) error {
+	return a.policy.Reload(ctx)
+}
+
 func (a *Auth) ParseClaimsFromHeader(r *http.Request) (Claims, error) {
 	var claims Claims
 	t, err := a.ExtractTokenFromHeader(r.Header.Get("Authorization"))
@@ -9,17 +9,45 @@ import (
 
 type ctxKey int
 
-const key ctxKey = 1
+const (
+	key ctxKey = iota + 1
+	scopesKey
+)
 
 const (
 	RoleAdmin = "ADMIN"
 	RoleUser  = "USER"
 )
 
+// Token uses, tokens issued before the claim was added have none
+// and are taken as access tokens.
+const (
+	UseAccess    = "access"
+	UseRefresh   = "refresh"
+	UseChallenge = "challenge"
+	UsePersonal  = "personal"
+)
+
+// Scopes of personal access tokens, session tokens are not scoped.
+const (
+	ScopeListsRead   = "lists:read"
+	ScopeListsWrite  = "lists:write"
+	ScopeImagesRead  = "images:read"
+	ScopeImagesWrite = "images:write"
+)
+
 type Claims struct {
 	jwt.RegisteredClaims
 	Roles        []string `json:"roles"`
 	TokenVersion int32    `json:"token_version"`
+	// Family is the refresh token family the token was issued in.
+	Family string `json:"fam,omitempty"`
+	Use    string `json:"use,omitempty"`
+	// Scopes are set on the claims of personal access tokens only.
+	Scopes []string `json:"scp,omitempty"`
+	// Permissions of the roles, they are resolved on every request
+	// so that changes of the roles apply to issued tokens.
+	Permissions []string `json:"-"`
 }
 
 func (c Claims) Authorize(roles ...string) bool {
@@ -35,6 +63,43 @@ func (c Claims) Authorize(roles ...string) bool {
 	return false
 }
 
+// Can reports whether the roles of the claims grant the permission.
+func (c Claims) Can(perm string) bool {
+	for _, has := range c.Permissions {
+		if has == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAll reports whether the roles of the claims grant all the permissions.
+func (c Claims) CanAll(perms ...string) bool {
+	for _, perm := range perms {
+		if !c.Can(perm) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasScopes reports whether the claims carry all the scopes.
+func (c Claims) HasScopes(scopes ...string) bool {
+	for _, want := range scopes {
+		found := false
+		for _, has := range c.Scopes {
+			if has == want {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // SetClaims stores the claims in the context.
 func SetClaims(ctx context.Context, claims Claims) context.Context {
 	return context.WithValue(ctx, key, claims)
@@ -48,3 +113,15 @@ func GetClaims(ctx context.Context) (Claims, error) {
 	}
 	return v, nil
 }
+
+// SetScopes stores the scopes a route requires of personal access tokens.
+func SetScopes(ctx context.Context, scopes []string) context.Context {
+	return context.WithValue(ctx, scopesKey, scopes)
+}
+
+// GetScopes returns the scopes the route requires, none if the route
+// does not accept personal access tokens.
+func GetScopes(ctx context.Context) []string {
+	v, _ := ctx.Value(scopesKey).([]string)
+	return v
+}
@@ -6,9 +6,11 @@ var (
 	ErrMissingKey              = errors.New("missing active key")
 	ErrMissingKID              = errors.New("missing key id in header")
 	ErrKIDFormat               = errors.New("key id must be string")
+	ErrKeyAlgorithm            = errors.New("token algorithm does not match the key")
 	ErrPrivateNotFound         = errors.New("missing private key for id")
 	ErrSigningToken            = errors.New("error signing token")
 	ErrLoadRevokedTokens       = errors.New("error loading revoked tokens")
+	ErrLoadRevokedFamilies     = errors.New("error loading revoked token families")
 	ErrParseToken              = errors.New("error parsing token")
 	ErrValidateToken           = errors.New("error validating token")
 	ErrValidateTokenVersion    = errors.New("error validating token version")
@@ -19,16 +21,29 @@ var (
 	ErrCheckCachedTokenVersion = errors.New("error checking token version in cache")
 	ErrParseCachedTokenVersion = errors.New("error parsing token version in cache")
 	ErrRevokedToken            = errors.New("error revoked token")
+	ErrRevokedTokenFamily      = errors.New("error revoked token family")
+	ErrRefreshTokenReused      = errors.New("error refresh token reused")
 	ErrEncodeTokenForCache     = errors.New("error encoding token for cache")
 	ErrEncodeTokensForCache    = errors.New("error encoding tokens for cache")
 	ErrStoreCacheToken         = errors.New("error storing token in cache")
 	ErrStoreCacheTokenVersion  = errors.New("error storing token version in cache")
 	ErrStoreCacheTokens        = errors.New("error storing tokens in cache")
+	ErrStoreCacheFamily        = errors.New("error storing token family in cache")
 	ErrReadTokensFromDB        = errors.New("error loading tokens from db")
 	ErrReadTokenFromDB         = errors.New("error loading token from db")
 	ErrReadUserFromDB          = errors.New("error loading user from db")
 	ErrGenHash                 = errors.New("error generate hash")
 	ErrGenResetToken           = errors.New("error generate reset token")
+	ErrGenTOTPSecret           = errors.New("error generate totp secret")
+	ErrGenRecoveryCodes        = errors.New("error generate recovery codes")
+	ErrInvalidChallengeToken   = errors.New("error invalid challenge token")
+	ErrGenPersonalToken        = errors.New("error generate personal access token")
+	ErrStorePersonalTokenUse   = errors.New("error storing personal access token use")
+	ErrMissingScope            = errors.New("error token is missing a scope of the route")
+	ErrMissingPermission       = errors.New("error missing permission")
+	ErrUnknownPermission       = errors.New("error unknown permission")
+	ErrLoadPolicy              = errors.New("error loading role permissions")
+	ErrReloadPolicy            = errors.New("error reloading role permissions")
 	ErrValidateResetToken      = errors.New("error validate reset token")
 	ErrValidateVerifyToken     = errors.New("error validate verify token")
 	ErrResetTokenReqLimit      = errors.New("error request reset token too often")
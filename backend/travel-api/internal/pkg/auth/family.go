@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshToken is a signed one-time refresh token.
+type RefreshToken struct {
+	Token     string
+	TokenID   string
+	FamilyID  string
+	ExpiresAt time.Time
+}
+
+// FamilyParams is a revoked refresh token family as mirrored in the cache.
+type FamilyParams struct {
+	FamilyID  string    `db:"family_id" json:"family_id"`
+	UserID    string    `db:"user_id" json:"user_id"`
+	ExpiresAt time.Time `db:"expires_at" json:"expires_at"`
+	RevokedAt time.Time `db:"revoked_at" json:"revoked_at"`
+}
+
+// GenerateRefreshToken signs a refresh token in the family of the claims,
+// a new family is started if the claims have none.
+func (a *Auth) GenerateRefreshToken(ctx context.Context, claims Claims) (RefreshToken, error) {
+	if claims.Family == "" {
+		claims.Family = uuid.New().String()
+	}
+	claims.Use = UseRefresh
+	str, c, err := a.generateToken(ctx, claims, a.RefreshDuration)
+	if err != nil {
+		return RefreshToken{}, err
+	}
+	return RefreshToken{
+		Token:     str,
+		TokenID:   c.ID,
+		FamilyID:  c.Family,
+		ExpiresAt: c.ExpiresAt.Time,
+	}, nil
+}
+
+// MarkFamilyAsRevoked makes every token of the family invalid,
+// access tokens included.
+func (a *Auth) MarkFamilyAsRevoked(ctx context.Context, f FamilyParams) error {
+	jsonData, err := json.Marshal(f)
+	if err != nil {
+		a.log.Err(err).Msg(ErrEncodeTokenForCache.Error())
+		return ErrEncodeTokenForCache
+	}
+	if err := a.cache.Set(
+		ctx,
+		familyKey(f.FamilyID),
+		jsonData,
+		f.ExpiresAt.Sub(time.Now().UTC()),
+	).Err(); err != nil {
+		a.log.Err(err).Msg(ErrStoreCacheFamily.Error())
+		return ErrStoreCacheFamily
+	}
+	return nil
+}
+
+func (a *Auth) LoadRevokedFamiliesToCache() error {
+	var families []FamilyParams
+	q := `SELECT family_id, user_id, expires_at, revoked_at FROM token_families
+	WHERE revoked_at IS NOT NULL AND expires_at > $1;`
+	if err := a.db.Select(&families, q, time.Now().UTC()); err != nil {
+		a.log.Err(err).Msg(ErrReadTokensFromDB.Error())
+		return ErrReadTokensFromDB
+	}
+	for _, f := range families {
+		if err := a.MarkFamilyAsRevoked(context.TODO(), f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// familyKey keeps the families apart from the token ids and user ids
+// the cache is keyed by.
+func familyKey(familyID string) string {
+	return "token_family:" + familyID
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+	"sort"
+)
+
+// JWK is a public key in the JSON Web Key format, RFC 7517. RSA keys set N
+// and E, ECDSA keys Crv, X and Y and Ed25519 keys Crv and X, RFC 8037.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// JWKS returns the public keys tokens are verified with, so that other
+// services can verify the tokens without sharing the private keys.
+func (a *Auth) JWKS() []JWK {
+	keys := a.keyLookup.PublicKeys()
+	jwks := make([]JWK, 0, len(keys))
+	for kid, key := range keys {
+		alg, err := a.keyLookup.Algorithm(kid)
+		if err != nil {
+			continue
+		}
+		jwk := JWK{
+			Use: "sig",
+			Alg: alg,
+			Kid: kid,
+		}
+		switch k := key.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = encode(k.N.Bytes())
+			jwk.E = encode(big.NewInt(int64(k.E)).Bytes())
+		case *ecdsa.PublicKey:
+			// coordinates are padded to the curve size, RFC 7518 section 6.2.1.2
+			size := (k.Curve.Params().BitSize + 7) / 8
+			jwk.Kty = "EC"
+			jwk.Crv = k.Curve.Params().Name
+			jwk.X = encode(k.X.FillBytes(make([]byte, size)))
+			jwk.Y = encode(k.Y.FillBytes(make([]byte, size)))
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = encode(k)
+		default:
+			a.log.Error().Msgf("auth: jwks: unsupported key type of key %s", kid)
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+	sort.Slice(jwks, func(i, j int) bool {
+		return jwks[i].Kid < jwks[j].Kid
+	})
+	return jwks
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/lib/pq"
+)
+
+// PersonalTokenPrefix tells personal access tokens from JWTs
+// in the Authorization header.
+const PersonalTokenPrefix = "trv_"
+
+// lastUsedPrecision is how stale the last use of a personal access token
+// may get, so that a script does not write to the db on every request.
+const lastUsedPrecision = time.Minute
+
+type personalToken struct {
+	TokenID   string         `db:"token_id"`
+	UserID    string         `db:"user_id"`
+	Scopes    pq.StringArray `db:"scopes"`
+	ExpiresAt *time.Time     `db:"expires_at"`
+	// TokenVersion is the version of the user when the token was created
+	TokenVersion int32          `db:"token_version"`
+	Roles        pq.StringArray `db:"roles"`
+	UserVersion  int32          `db:"user_version"`
+	IsActive     bool           `db:"is_active"`
+	IsDeleted    bool           `db:"is_deleted"`
+}
+
+// GeneratePersonalToken returns a new personal access token and its hash,
+// only the hash is to be stored.
+func GeneratePersonalToken() (string, string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", ErrGenPersonalToken
+	}
+	t := PersonalTokenPrefix + hex.EncodeToString(b)
+	return t, HashPersonalToken(t), nil
+}
+
+func HashPersonalToken(t string) string {
+	h := sha256.Sum256([]byte(t))
+	return hex.EncodeToString(h[:])
+}
+
+func IsPersonalToken(t string) bool {
+	return strings.HasPrefix(t, PersonalTokenPrefix)
+}
+
+// ValidatePersonalToken looks the token up by its hash and returns the claims
+// of its user with the scopes of the token. The token stops working once it
+// expires, its user is deactivated or deleted, or the token version of the
+// user changes, as on logout from everywhere or a change of roles.
+func (a *Auth) ValidatePersonalToken(ctx context.Context, t string) (Claims, error) {
+	pt := personalToken{}
+	q := `SELECT
+		t.token_id,
+		t.user_id,
+		t.scopes,
+		t.expires_at,
+		t.token_version,
+		u.roles,
+		u.token_version AS user_version,
+		u.is_active,
+		u.is_deleted
+	FROM personal_access_tokens t
+	JOIN users u ON u.user_id = t.user_id
+	WHERE t.token_hash = $1;`
+	if err := a.db.GetContext(ctx, &pt, q, HashPersonalToken(t)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			a.log.Error().Msg(ErrInvalidToken.Error())
+			return Claims{}, ErrInvalidToken
+		}
+		a.log.Err(err).Msg(ErrReadTokenFromDB.Error())
+		return Claims{}, ErrReadTokenFromDB
+	}
+	now := time.Now().UTC()
+	if pt.ExpiresAt != nil && pt.ExpiresAt.Before(now) {
+		a.log.Error().Msg(ErrExpiredToken.Error())
+		return Claims{}, ErrExpiredToken
+	}
+	if !pt.IsActive || pt.IsDeleted || pt.TokenVersion != pt.UserVersion {
+		a.log.Error().Msg(ErrInvalidToken.Error())
+		return Claims{}, ErrInvalidToken
+	}
+	qUsed := `UPDATE personal_access_tokens SET last_used_at = $1
+	WHERE token_id = $2 AND (last_used_at IS NULL OR last_used_at < $3);`
+	if _, err := a.db.ExecContext(ctx, qUsed, now, pt.TokenID, now.Add(-lastUsedPrecision)); err != nil {
+		// the request is still good, only the bookkeeping failed
+		a.log.Err(err).Msg(ErrStorePersonalTokenUse.Error())
+	}
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:      pt.TokenID,
+			Subject: pt.UserID,
+		},
+		Roles:        pt.Roles,
+		TokenVersion: pt.TokenVersion,
+		Use:          UsePersonal,
+		Scopes:       pt.Scopes,
+	}
+	if pt.ExpiresAt != nil {
+		claims.ExpiresAt = jwt.NewNumericDate(*pt.ExpiresAt)
+	}
+	return claims, nil
+}
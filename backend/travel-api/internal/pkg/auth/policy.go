@@ -0,0 +1,158 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog"
+)
+
+// Permissions granted to roles. Without a permission a user may act on
+// their own data only, the permission extends it to the data of anyone.
+const (
+	PermListReadAny    = "list.read.any"
+	PermListWriteAny   = "list.write.any"
+	PermListManageAny  = "list.manage.any"
+	PermImageReadAny   = "image.read.any"
+	PermUserReadAny    = "user.read.any"
+	PermUserWriteAny   = "user.write.any"
+	PermUserBan        = "user.ban"
+	PermUserRoleAssign = "user.role.assign"
+	PermRoleManage     = "role.manage"
+	PermAuditRead      = "audit.read"
+)
+
+// AllPermissions lists every permission, the admin role holds them all.
+var AllPermissions = []string{
+	PermListReadAny,
+	PermListWriteAny,
+	PermListManageAny,
+	PermImageReadAny,
+	PermUserReadAny,
+	PermUserWriteAny,
+	PermUserBan,
+	PermUserRoleAssign,
+	PermRoleManage,
+	PermAuditRead,
+}
+
+// IsPermission reports whether p is a known permission.
+func IsPermission(p string) bool {
+	for _, perm := range AllPermissions {
+		if p == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// PersonalPermissions returns the permissions a personal access token
+// carries out of those of its user. Scopes narrow what a token can do, so
+// it acts on the data of its user only and none of the permissions
+// extending access to the data of anyone are kept.
+func PersonalPermissions(perms []string) []string {
+	var kept []string
+	for _, perm := range perms {
+		if !strings.HasSuffix(perm, ".any") {
+			kept = append(kept, perm)
+		}
+	}
+	return kept
+}
+
+// Policy maps roles to their permissions. The mapping is kept in the
+// role_permissions table and cached in memory, it is reloaded on every
+// change made through this instance and periodically for the others.
+type Policy struct {
+	mu        sync.RWMutex
+	perms     map[string][]string
+	db        *sqlx.DB
+	log       *zerolog.Logger
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type rolePermission struct {
+	Role       string `db:"role"`
+	Permission string `db:"permission"`
+}
+
+func NewPolicy(db *sqlx.DB, log *zerolog.Logger) (*Policy, error) {
+	p := Policy{
+		perms: make(map[string][]string),
+		db:    db,
+		log:   log,
+		done:  make(chan struct{}),
+	}
+	if err := p.Reload(context.Background()); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Reload reads the permissions of all roles, on error the current
+// permissions are kept.
+func (p *Policy) Reload(ctx context.Context) error {
+	var rps []rolePermission
+	q := `SELECT role, permission FROM role_permissions;`
+	if err := p.db.SelectContext(ctx, &rps, q); err != nil {
+		p.log.Err(err).Msg(ErrLoadPolicy.Error())
+		return ErrLoadPolicy
+	}
+	perms := make(map[string][]string)
+	for _, rp := range rps {
+		perms[rp.Role] = append(perms[rp.Role], rp.Permission)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.perms = perms
+	return nil
+}
+
+// Watch reloads the permissions every interval until Close is called.
+func (p *Policy) Watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+			if err := p.Reload(context.Background()); err != nil {
+				p.log.Err(err).Msg(ErrReloadPolicy.Error())
+			}
+		}
+	}
+}
+
+func (p *Policy) Close() {
+	p.closeOnce.Do(func() {
+		close(p.done)
+	})
+}
+
+// Permissions returns the permissions of the roles combined.
+func (p *Policy) Permissions(roles []string) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	seen := make(map[string]struct{})
+	var perms []string
+	for _, role := range roles {
+		granted := p.perms[role]
+		// the admin role cannot lose a permission, so it cannot lock itself out
+		if role == RoleAdmin {
+			granted = AllPermissions
+		}
+		for _, perm := range granted {
+			if _, ok := seen[perm]; ok {
+				continue
+			}
+			seen[perm] = struct{}{}
+			perms = append(perms, perm)
+		}
+	}
+	return perms
+}
@@ -17,13 +17,20 @@ const (
 	deadlineExceeded = "57014"
 )
 
-var ErrQueryDB = errors.New("error querying db")
+var (
+	ErrQueryDB      = errors.New("error querying db")
+	ErrStaleVersion = errors.New("error stale row version")
+)
 
 func WrapStorerError(err error) error {
 	if errors.Is(err, sql.ErrNoRows) {
 		return web.ErrNotFound
 	}
 
+	if errors.Is(err, ErrStaleVersion) {
+		return web.ErrPreconditionFailed
+	}
+
 	if pgErr, ok := err.(*pgconn.PgError); ok {
 		switch pgErr.Code {
 		case uniqueViolation:
@@ -0,0 +1,112 @@
+// Package geofile reads and writes lists of waypoints in common GPS formats.
+package geofile
+
+import (
+	"errors"
+	"io"
+	"path"
+	"strings"
+)
+
+type Format string
+
+const (
+	GPX     Format = "gpx"
+	KML     Format = "kml"
+	GeoJSON Format = "geojson"
+)
+
+var (
+	ErrUnknownFormat       = errors.New("unknown file format")
+	ErrInvalidCoordinates  = errors.New("invalid coordinates")
+	ErrUnsupportedGeometry = errors.New("unsupported geometry, only points can be imported")
+)
+
+// Waypoint is a single place of a document.
+type Waypoint struct {
+	Name        string
+	Description string
+	Address     string
+	Lat         float64
+	Lng         float64
+	Visited     bool
+}
+
+// Document is a named set of waypoints. When Route is set the waypoints
+// are also written as a route in their order.
+type Document struct {
+	Name        string
+	Description string
+	Waypoints   []Waypoint
+	Route       bool
+}
+
+// Feature is a waypoint read from a file, Index is its position among the
+// features of the file. Err is set when the feature could not be read.
+type Feature struct {
+	Index    int
+	Waypoint Waypoint
+	Err      error
+}
+
+// FormatFromName guesses the format by the file extension.
+func FormatFromName(name string) (Format, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".gpx":
+		return GPX, nil
+	case ".kml":
+		return KML, nil
+	case ".geojson", ".json":
+		return GeoJSON, nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case GPX:
+		return "application/gpx+xml"
+	case KML:
+		return "application/vnd.google-earth.kml+xml"
+	case GeoJSON:
+		return "application/geo+json"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// Encode writes the document in the given format.
+func Encode(w io.Writer, f Format, d Document) error {
+	switch f {
+	case GPX:
+		return encodeGPX(w, d)
+	case KML:
+		return encodeKML(w, d)
+	case GeoJSON:
+		return encodeGeoJSON(w, d)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// Decode reads the features of a file in the given format.
+// An error is returned only when the file itself cannot be read,
+// problems with single features are reported in their Err.
+func Decode(r io.Reader, f Format) ([]Feature, error) {
+	switch f {
+	case GPX:
+		return decodeGPX(r)
+	case KML:
+		return decodeKML(r)
+	case GeoJSON:
+		return decodeGeoJSON(r)
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+func validCoordinates(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180
+}
@@ -0,0 +1,323 @@
+package geofile
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var testDocument = Document{
+	Name:        "Prague",
+	Description: "a weekend in Prague",
+	Waypoints: []Waypoint{
+		{
+			Name:        "Charles Bridge",
+			Description: "at sunrise",
+			Address:     "Karlův most, Praha 1",
+			Lat:         50.0865,
+			Lng:         14.4114,
+			Visited:     true,
+		},
+		{
+			Name: "Vyšehrad",
+			Lat:  50.0643,
+			Lng:  14.4178,
+		},
+		{
+			Name:        "Letná <beer> & \"garden\"",
+			Description: "escaped in XML",
+			Lat:         50.0966,
+			Lng:         14.4232,
+		},
+		{
+			Name: "Southern hemisphere",
+			Lat:  -33.8568,
+			Lng:  151.2153,
+		},
+	},
+}
+
+func TestEncodeDecodeRoundTrip(t *testing.T) {
+	for _, f := range []Format{GPX, KML, GeoJSON} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, f, testDocument); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			fs, err := Decode(&buf, f)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if len(fs) != len(testDocument.Waypoints) {
+				t.Fatalf("got %d features, want %d", len(fs), len(testDocument.Waypoints))
+			}
+			for i, got := range fs {
+				if got.Err != nil {
+					t.Errorf("feature %d: %v", i, got.Err)
+				}
+				if got.Index != i {
+					t.Errorf("feature %d: index %d", i, got.Index)
+				}
+				if want := testDocument.Waypoints[i]; got.Waypoint != want {
+					t.Errorf("feature %d:\n got %+v\nwant %+v", i, got.Waypoint, want)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeRoute(t *testing.T) {
+	d := testDocument
+	d.Route = true
+	tests := []struct {
+		format Format
+		// the route is read back as a feature of its own
+		wantRoute bool
+	}{
+		// a file with waypoints has its route ignored on import
+		{GPX, false},
+		{KML, true},
+		{GeoJSON, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, tt.format, d); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			fs, err := Decode(&buf, tt.format)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			want := len(d.Waypoints)
+			if tt.wantRoute {
+				want++
+			}
+			if len(fs) != want {
+				t.Fatalf("got %d features, want %d", len(fs), want)
+			}
+			if tt.wantRoute && !errors.Is(fs[len(fs)-1].Err, ErrUnsupportedGeometry) {
+				t.Errorf("route feature: got %v, want %v", fs[len(fs)-1].Err, ErrUnsupportedGeometry)
+			}
+		})
+	}
+}
+
+func TestEncodeEmpty(t *testing.T) {
+	for _, f := range []Format{GPX, KML, GeoJSON} {
+		t.Run(string(f), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, f, Document{Name: "empty", Route: true}); err != nil {
+				t.Fatalf("Encode: %v", err)
+			}
+			fs, err := Decode(&buf, f)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if len(fs) != 0 {
+				t.Errorf("got %d features, want none", len(fs))
+			}
+		})
+	}
+}
+
+func TestEncodeUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, Format("shp"), testDocument); !errors.Is(err, ErrUnknownFormat) {
+		t.Errorf("got %v, want %v", err, ErrUnknownFormat)
+	}
+}
+
+func TestContentType(t *testing.T) {
+	tests := []struct {
+		format Format
+		want   string
+	}{
+		{GPX, "application/gpx+xml"},
+		{KML, "application/vnd.google-earth.kml+xml"},
+		{GeoJSON, "application/geo+json"},
+		{Format("shp"), "application/octet-stream"},
+	}
+	for _, tt := range tests {
+		if got := tt.format.ContentType(); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.format, got, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		data    string
+		want    []Waypoint
+		wantErr []error
+	}{
+		{
+			name:   "gpx route points without waypoints",
+			format: GPX,
+			data: `<?xml version="1.0"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <rte>
+    <rtept lat="48.1" lon="11.5"><name> Munich </name></rtept>
+    <rtept lat="47.3" lon="8.5"><name>Zurich</name><type>visited</type></rtept>
+  </rte>
+</gpx>`,
+			want: []Waypoint{
+				{Name: "Munich", Lat: 48.1, Lng: 11.5},
+				{Name: "Zurich", Lat: 47.3, Lng: 8.5, Visited: true},
+			},
+			wantErr: []error{nil, nil},
+		},
+		{
+			name:   "gpx invalid coordinates",
+			format: GPX,
+			data: `<gpx version="1.1">
+  <wpt lat="91" lon="0"><name>north of the pole</name></wpt>
+  <wpt lat="0" lon="0"><name>null island</name><cmt>the address</cmt></wpt>
+</gpx>`,
+			want: []Waypoint{
+				{Name: "north of the pole", Lat: 91},
+				{Name: "null island", Address: "the address"},
+			},
+			wantErr: []error{ErrInvalidCoordinates, nil},
+		},
+		{
+			name:   "kml placemarks in folders",
+			format: KML,
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <Folder>
+      <name>day 1</name>
+      <Placemark>
+        <name>Louvre</name>
+        <Point><coordinates> 2.3376,48.8606,35 </coordinates></Point>
+      </Placemark>
+      <Folder>
+        <Placemark>
+          <name>Orsay</name>
+          <ExtendedData><Data name="visited"><value>true</value></Data></ExtendedData>
+          <Point><coordinates>2.3266,48.86</coordinates></Point>
+        </Placemark>
+      </Folder>
+    </Folder>
+    <Placemark>
+      <name>walk</name>
+      <LineString><coordinates>2.3376,48.8606 2.3266,48.86</coordinates></LineString>
+    </Placemark>
+    <Placemark>
+      <name>broken</name>
+      <Point><coordinates>2.3266</coordinates></Point>
+    </Placemark>
+  </Document>
+</kml>`,
+			want: []Waypoint{
+				{Name: "Louvre", Lat: 48.8606, Lng: 2.3376},
+				{Name: "Orsay", Lat: 48.86, Lng: 2.3266, Visited: true},
+				{Name: "walk"},
+				{Name: "broken"},
+			},
+			wantErr: []error{nil, nil, ErrUnsupportedGeometry, ErrInvalidCoordinates},
+		},
+		{
+			name:   "geojson single feature with alternative keys",
+			format: GeoJSON,
+			data: `{
+  "type": "Feature",
+  "geometry": {"type": "Point", "coordinates": [-0.1276, 51.5072, 11]},
+  "properties": {"title": "London", "desc": "the city"}
+}`,
+			want:    []Waypoint{{Name: "London", Description: "the city", Lat: 51.5072, Lng: -0.1276}},
+			wantErr: []error{nil},
+		},
+		{
+			name:   "geojson collection with bad features",
+			format: GeoJSON,
+			data: `{
+  "type": "FeatureCollection",
+  "features": [
+    {"type": "Feature", "geometry": {"type": "Polygon", "coordinates": []}, "properties": {"name": "area"}},
+    {"type": "Feature", "geometry": {"type": "Point", "coordinates": [200, 10]}, "properties": {"name": "off"}},
+    {"type": "Feature", "geometry": {"type": "Point", "coordinates": [10.75, 59.91]}, "properties": {"name": "Oslo", "visited": true}}
+  ]
+}`,
+			want: []Waypoint{
+				{Name: "area"},
+				{Name: "off"},
+				{Name: "Oslo", Lat: 59.91, Lng: 10.75, Visited: true},
+			},
+			wantErr: []error{ErrUnsupportedGeometry, ErrInvalidCoordinates, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs, err := Decode(bytes.NewBufferString(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+			if len(fs) != len(tt.want) {
+				t.Fatalf("got %d features, want %d", len(fs), len(tt.want))
+			}
+			for i, f := range fs {
+				if f.Index != i {
+					t.Errorf("feature %d: index %d", i, f.Index)
+				}
+				if !errors.Is(f.Err, tt.wantErr[i]) {
+					t.Errorf("feature %d: got error %v, want %v", i, f.Err, tt.wantErr[i])
+				}
+				// the coordinates of a broken feature are of no use
+				got, want := f.Waypoint, tt.want[i]
+				if f.Err != nil {
+					got.Lat, got.Lng, want.Lat, want.Lng = 0, 0, 0, 0
+				}
+				if got != want {
+					t.Errorf("feature %d:\n got %+v\nwant %+v", i, f.Waypoint, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"gpx not xml", GPX, "not xml"},
+		{"kml truncated", KML, "<kml><Document><Placemark><name>x</name>"},
+		{"geojson not json", GeoJSON, "{"},
+		{"geojson geometry only", GeoJSON, `{"type": "Point", "coordinates": [0, 0]}`},
+		{"unknown format", Format("shp"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(bytes.NewBufferString(tt.data), tt.format); err == nil {
+				t.Error("want an error")
+			}
+		})
+	}
+}
+
+func TestFormatFromName(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr error
+	}{
+		{"trip.gpx", GPX, nil},
+		{"Trip.GPX", GPX, nil},
+		{"places.kml", KML, nil},
+		{"places.geojson", GeoJSON, nil},
+		{"export.json", GeoJSON, nil},
+		{"dir.kml/places.kmz", "", ErrUnknownFormat},
+		{"noext", "", ErrUnknownFormat},
+	}
+	for _, tt := range tests {
+		got, err := FormatFromName(tt.name)
+		if got != tt.want || !errors.Is(err, tt.wantErr) {
+			t.Errorf("FormatFromName(%q) = %q, %v, want %q, %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
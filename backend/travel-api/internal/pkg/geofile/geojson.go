@@ -0,0 +1,138 @@
+package geofile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type geoJSONCollection struct {
+	Type     string           `json:"type"`
+	Name     string           `json:"name,omitempty"`
+	Features []geoJSONFeature `json:"features"`
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties map[string]any  `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+func encodeGeoJSON(w io.Writer, d Document) error {
+	fc := geoJSONCollection{
+		Type:     "FeatureCollection",
+		Name:     d.Name,
+		Features: []geoJSONFeature{},
+	}
+	line := make([][2]float64, 0, len(d.Waypoints))
+	for _, wp := range d.Waypoints {
+		// GeoJSON puts longitude first
+		c := [2]float64{wp.Lng, wp.Lat}
+		line = append(line, c)
+		coords, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		props := map[string]any{
+			"name":    wp.Name,
+			"visited": wp.Visited,
+		}
+		if wp.Description != "" {
+			props["description"] = wp.Description
+		}
+		if wp.Address != "" {
+			props["address"] = wp.Address
+		}
+		fc.Features = append(fc.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "Point", Coordinates: coords},
+			Properties: props,
+		})
+	}
+	if d.Route && len(d.Waypoints) > 1 {
+		coords, err := json.Marshal(line)
+		if err != nil {
+			return err
+		}
+		fc.Features = append(fc.Features, geoJSONFeature{
+			Type:       "Feature",
+			Geometry:   geoJSONGeometry{Type: "LineString", Coordinates: coords},
+			Properties: map[string]any{"name": d.Name},
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fc)
+}
+
+type geoJSONObject struct {
+	Type       string           `json:"type"`
+	Features   []geoJSONFeature `json:"features"`
+	Geometry   *geoJSONGeometry `json:"geometry"`
+	Properties map[string]any   `json:"properties"`
+}
+
+// decodeGeoJSON reads a feature collection or a single feature.
+func decodeGeoJSON(r io.Reader) ([]Feature, error) {
+	obj := geoJSONObject{}
+	if err := json.NewDecoder(r).Decode(&obj); err != nil {
+		return nil, err
+	}
+	var features []geoJSONFeature
+	switch obj.Type {
+	case "FeatureCollection":
+		features = obj.Features
+	case "Feature":
+		f := geoJSONFeature{Type: obj.Type, Properties: obj.Properties}
+		if obj.Geometry != nil {
+			f.Geometry = *obj.Geometry
+		}
+		features = []geoJSONFeature{f}
+	default:
+		return nil, fmt.Errorf("unsupported geojson type %q", obj.Type)
+	}
+	fs := make([]Feature, 0, len(features))
+	for i, gf := range features {
+		f := Feature{
+			Index: i,
+			Waypoint: Waypoint{
+				Name:        stringProperty(gf.Properties, "name", "title"),
+				Description: stringProperty(gf.Properties, "description", "desc"),
+				Address:     stringProperty(gf.Properties, "address"),
+			},
+		}
+		if v, ok := gf.Properties["visited"].(bool); ok {
+			f.Waypoint.Visited = v
+		}
+		if gf.Geometry.Type != "Point" {
+			f.Err = ErrUnsupportedGeometry
+			fs = append(fs, f)
+			continue
+		}
+		var c []float64
+		if err := json.Unmarshal(gf.Geometry.Coordinates, &c); err != nil || len(c) < 2 || !validCoordinates(c[1], c[0]) {
+			f.Err = ErrInvalidCoordinates
+			fs = append(fs, f)
+			continue
+		}
+		f.Waypoint.Lng, f.Waypoint.Lat = c[0], c[1]
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// stringProperty returns the first string property found by the keys.
+func stringProperty(props map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := props[k].(string); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
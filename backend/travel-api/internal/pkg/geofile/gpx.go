@@ -0,0 +1,119 @@
+package geofile
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+)
+
+const gpxNamespace = "http://www.topografix.com/GPX/1/1"
+
+// visited waypoints are marked with a type as GPX has no such field
+const (
+	gpxVisited   = "visited"
+	gpxUnvisited = "unvisited"
+)
+
+type gpx struct {
+	XMLName   xml.Name     `xml:"gpx"`
+	XMLNS     string       `xml:"xmlns,attr,omitempty"`
+	Version   string       `xml:"version,attr"`
+	Creator   string       `xml:"creator,attr"`
+	Metadata  *gpxMetadata `xml:"metadata,omitempty"`
+	Waypoints []gpxPoint   `xml:"wpt"`
+	Routes    []gpxRoute   `xml:"rte"`
+}
+
+type gpxMetadata struct {
+	Name        string `xml:"name,omitempty"`
+	Description string `xml:"desc,omitempty"`
+}
+
+type gpxPoint struct {
+	Lat         float64 `xml:"lat,attr"`
+	Lng         float64 `xml:"lon,attr"`
+	Name        string  `xml:"name,omitempty"`
+	Comment     string  `xml:"cmt,omitempty"`
+	Description string  `xml:"desc,omitempty"`
+	Type        string  `xml:"type,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+func encodeGPX(w io.Writer, d Document) error {
+	g := gpx{
+		XMLNS:   gpxNamespace,
+		Version: "1.1",
+		Creator: "Traillyst",
+		Metadata: &gpxMetadata{
+			Name:        d.Name,
+			Description: d.Description,
+		},
+	}
+	for _, wp := range d.Waypoints {
+		t := gpxUnvisited
+		if wp.Visited {
+			t = gpxVisited
+		}
+		g.Waypoints = append(g.Waypoints, gpxPoint{
+			Lat:         wp.Lat,
+			Lng:         wp.Lng,
+			Name:        wp.Name,
+			Comment:     wp.Address,
+			Description: wp.Description,
+			Type:        t,
+		})
+	}
+	if d.Route && len(d.Waypoints) > 1 {
+		r := gpxRoute{Name: d.Name}
+		for _, wp := range d.Waypoints {
+			r.Points = append(r.Points, gpxPoint{Lat: wp.Lat, Lng: wp.Lng, Name: wp.Name})
+		}
+		g.Routes = append(g.Routes, r)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(g); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// decodeGPX reads waypoints, a file without any reads its route points instead.
+func decodeGPX(r io.Reader) ([]Feature, error) {
+	g := gpx{}
+	if err := xml.NewDecoder(r).Decode(&g); err != nil {
+		return nil, err
+	}
+	pts := g.Waypoints
+	if len(pts) == 0 {
+		for _, rte := range g.Routes {
+			pts = append(pts, rte.Points...)
+		}
+	}
+	fs := make([]Feature, 0, len(pts))
+	for i, p := range pts {
+		f := Feature{
+			Index: i,
+			Waypoint: Waypoint{
+				Name:        strings.TrimSpace(p.Name),
+				Description: strings.TrimSpace(p.Description),
+				Address:     strings.TrimSpace(p.Comment),
+				Lat:         p.Lat,
+				Lng:         p.Lng,
+				Visited:     p.Type == gpxVisited,
+			},
+		}
+		if !validCoordinates(p.Lat, p.Lng) {
+			f.Err = ErrInvalidCoordinates
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
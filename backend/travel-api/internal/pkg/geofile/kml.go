@@ -0,0 +1,162 @@
+package geofile
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+const kmlVisited = "visited"
+
+type kml struct {
+	XMLName  xml.Name    `xml:"kml"`
+	XMLNS    string      `xml:"xmlns,attr,omitempty"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name        string         `xml:"name,omitempty"`
+	Description string         `xml:"description,omitempty"`
+	Placemarks  []kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name         string           `xml:"name,omitempty"`
+	Description  string           `xml:"description,omitempty"`
+	Address      string           `xml:"address,omitempty"`
+	ExtendedData *kmlExtendedData `xml:"ExtendedData,omitempty"`
+	Point        *kmlGeometry     `xml:"Point,omitempty"`
+	LineString   *kmlGeometry     `xml:"LineString,omitempty"`
+}
+
+type kmlExtendedData struct {
+	Data []kmlData `xml:"Data"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlGeometry struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+func encodeKML(w io.Writer, d Document) error {
+	k := kml{
+		XMLNS: kmlNamespace,
+		Document: kmlDocument{
+			Name:        d.Name,
+			Description: d.Description,
+		},
+	}
+	coords := make([]string, 0, len(d.Waypoints))
+	for _, wp := range d.Waypoints {
+		c := kmlCoordinate(wp.Lat, wp.Lng)
+		coords = append(coords, c)
+		k.Document.Placemarks = append(k.Document.Placemarks, kmlPlacemark{
+			Name:        wp.Name,
+			Description: wp.Description,
+			Address:     wp.Address,
+			ExtendedData: &kmlExtendedData{
+				Data: []kmlData{{Name: kmlVisited, Value: strconv.FormatBool(wp.Visited)}},
+			},
+			Point: &kmlGeometry{Coordinates: c},
+		})
+	}
+	if d.Route && len(d.Waypoints) > 1 {
+		k.Document.Placemarks = append(k.Document.Placemarks, kmlPlacemark{
+			Name:       d.Name,
+			LineString: &kmlGeometry{Coordinates: strings.Join(coords, " ")},
+		})
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(k); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// kmlCoordinate formats a coordinate tuple, KML puts longitude first.
+func kmlCoordinate(lat, lng float64) string {
+	return fmt.Sprintf("%s,%s",
+		strconv.FormatFloat(lng, 'f', -1, 64),
+		strconv.FormatFloat(lat, 'f', -1, 64),
+	)
+}
+
+// decodeKML reads placemarks at any depth of folders.
+func decodeKML(r io.Reader) ([]Feature, error) {
+	dec := xml.NewDecoder(r)
+	fs := []Feature{}
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "Placemark" {
+			continue
+		}
+		pm := kmlPlacemark{}
+		if err := dec.DecodeElement(&pm, &se); err != nil {
+			return nil, err
+		}
+		f := Feature{
+			Index: len(fs),
+			Waypoint: Waypoint{
+				Name:        strings.TrimSpace(pm.Name),
+				Description: strings.TrimSpace(pm.Description),
+				Address:     strings.TrimSpace(pm.Address),
+			},
+		}
+		if pm.ExtendedData != nil {
+			for _, d := range pm.ExtendedData.Data {
+				if d.Name == kmlVisited {
+					f.Waypoint.Visited, _ = strconv.ParseBool(strings.TrimSpace(d.Value))
+				}
+			}
+		}
+		if pm.Point == nil {
+			f.Err = ErrUnsupportedGeometry
+		} else if lat, lng, err := parseKMLCoordinate(pm.Point.Coordinates); err != nil {
+			f.Err = err
+		} else {
+			f.Waypoint.Lat, f.Waypoint.Lng = lat, lng
+		}
+		fs = append(fs, f)
+	}
+	return fs, nil
+}
+
+// parseKMLCoordinate parses a "lng,lat[,alt]" tuple.
+func parseKMLCoordinate(s string) (float64, float64, error) {
+	parts := strings.Split(strings.TrimSpace(s), ",")
+	if len(parts) < 2 {
+		return 0, 0, ErrInvalidCoordinates
+	}
+	lng, err := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCoordinates
+	}
+	lat, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+	if err != nil {
+		return 0, 0, ErrInvalidCoordinates
+	}
+	if !validCoordinates(lat, lng) {
+		return 0, 0, ErrInvalidCoordinates
+	}
+	return lat, lng, nil
+}
@@ -7,5 +7,10 @@ var (
 	ErrOpenKeyFile        = errors.New("error opening key file")
 	ErrReadPrivateKeyFile = errors.New("error reading auth private key file")
 	ErrParsePrivateKey    = errors.New("error parsing auth private key")
+	ErrUnsupportedKey     = errors.New("error unsupported key type, want RSA, ECDSA P-256 or Ed25519")
 	ErrPrivateKeyLookup   = errors.New("error private key lookup")
+	ErrSigningKeyLookup   = errors.New("error no key to sign with")
+	ErrReadMetadataFile   = errors.New("error reading key metadata file")
+	ErrParseMetadata      = errors.New("error parsing key metadata")
+	ErrReloadKeys         = errors.New("error reloading keys")
 )
@@ -0,0 +1,70 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+)
+
+// JWT algorithms of the supported keys.
+const (
+	AlgorithmRS256 = "RS256"
+	AlgorithmES256 = "ES256"
+	AlgorithmEdDSA = "EdDSA"
+)
+
+// ParsePrivateKeyPEM parses an RSA, ECDSA P-256 or Ed25519 private key in
+// PKCS #8, PKCS #1 or SEC 1 form and returns it with its JWT algorithm.
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, string, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, "", ErrParsePrivateKey
+	}
+	var key any
+	var err error
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	default:
+		// keys of older keygen versions are PKCS #1 labeled as PRIVATE KEY
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+		}
+	}
+	if err != nil {
+		return nil, "", ErrParsePrivateKey
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, "", ErrUnsupportedKey
+	}
+	alg, err := Algorithm(signer)
+	if err != nil {
+		return nil, "", err
+	}
+	return signer, alg, nil
+}
+
+// Algorithm returns the JWT algorithm of the key.
+func Algorithm(key crypto.Signer) (string, error) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return AlgorithmRS256, nil
+	case *ecdsa.PrivateKey:
+		if k.Curve != elliptic.P256() {
+			return "", ErrUnsupportedKey
+		}
+		return AlgorithmES256, nil
+	case ed25519.PrivateKey:
+		return AlgorithmEdDSA, nil
+	default:
+		return "", ErrUnsupportedKey
+	}
+}
@@ -0,0 +1,89 @@
+package keystore
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+func encodePEM(t *testing.T, typ string, der []byte, err error) []byte {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der})
+}
+
+func TestParsePrivateKeyPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkcs8 := func(key any) ([]byte, error) { return x509.MarshalPKCS8PrivateKey(key) }
+	der, err := pkcs8(rsaKey)
+	rsaPKCS8 := encodePEM(t, "PRIVATE KEY", der, err)
+	der, err = pkcs8(ecKey)
+	ecPKCS8 := encodePEM(t, "PRIVATE KEY", der, err)
+	der, err = pkcs8(edKey)
+	edPKCS8 := encodePEM(t, "PRIVATE KEY", der, err)
+	der, err = x509.MarshalECPrivateKey(ecKey)
+	ecSEC1 := encodePEM(t, "EC PRIVATE KEY", der, err)
+	der, err = x509.MarshalECPrivateKey(p384Key)
+	p384SEC1 := encodePEM(t, "EC PRIVATE KEY", der, err)
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantKey crypto.Signer
+		wantAlg string
+		wantErr error
+	}{
+		{"rsa pkcs1", encodePEM(t, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), nil), rsaKey, AlgorithmRS256, nil},
+		{"rsa pkcs1 labeled as pkcs8", encodePEM(t, "PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), nil), rsaKey, AlgorithmRS256, nil},
+		{"rsa pkcs8", rsaPKCS8, rsaKey, AlgorithmRS256, nil},
+		{"ecdsa p-256 sec1", ecSEC1, ecKey, AlgorithmES256, nil},
+		{"ecdsa p-256 pkcs8", ecPKCS8, ecKey, AlgorithmES256, nil},
+		{"ed25519 pkcs8", edPKCS8, edKey, AlgorithmEdDSA, nil},
+		{"ecdsa p-384", p384SEC1, nil, "", ErrUnsupportedKey},
+		{"not pem", []byte("not a key"), nil, "", ErrParsePrivateKey},
+		{"garbage in pem", encodePEM(t, "PRIVATE KEY", []byte("garbage"), nil), nil, "", ErrParsePrivateKey},
+		{"wrong label", encodePEM(t, "EC PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey), nil), nil, "", ErrParsePrivateKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, alg, err := ParsePrivateKeyPEM(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			if alg != tt.wantAlg {
+				t.Errorf("got algorithm %q, want %q", alg, tt.wantAlg)
+			}
+			if tt.wantKey == nil {
+				return
+			}
+			eq, ok := key.(interface{ Equal(crypto.PrivateKey) bool })
+			if !ok || !eq.Equal(tt.wantKey) {
+				t.Error("parsed key differs from the encoded one")
+			}
+		})
+	}
+}
@@ -1,47 +1,269 @@
 package keystore
 
 import (
-	"crypto/rsa"
+	"crypto"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"path"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
 )
 
+// Key is a private key with the JWT algorithm it signs with and the times
+// it is used in. The newest key past its NotBefore signs, the others only
+// verify until their RetireAfter.
+type Key struct {
+	ID          string
+	Private     crypto.Signer
+	Algorithm   string
+	Created     time.Time
+	NotBefore   time.Time
+	RetireAfter time.Time
+}
+
+// Metadata is read from the <kid>.json file next to the <kid>.pem file.
+// Without it the key is created and active from the file modification
+// time on and never retires.
+type Metadata struct {
+	Created     time.Time  `json:"created"`
+	NotBefore   time.Time  `json:"not_before"`
+	RetireAfter *time.Time `json:"retire_after,omitempty"`
+}
+
 type KeyStore struct {
-	mu    sync.RWMutex
-	store map[string]*rsa.PrivateKey
+	mu          sync.RWMutex
+	store       map[string]Key
+	fsys        fs.FS
+	fingerprint string
+	done        chan struct{}
+	closeOnce   sync.Once
 }
 
 func New() *KeyStore {
 
 	return &KeyStore{
 		mu:    sync.RWMutex{},
-		store: make(map[string]*rsa.PrivateKey),
+		store: make(map[string]Key),
+		done:  make(chan struct{}),
 	}
 }
 
 // NewMap constructs a KeyStore with an initial set of keys.
-func NewMap(store map[string]*rsa.PrivateKey) *KeyStore {
-	return &KeyStore{
-		store: store,
+func NewMap(store map[string]crypto.Signer) (*KeyStore, error) {
+	ks := New()
+	for kid, key := range store {
+		if err := ks.Add(kid, key); err != nil {
+			return nil, err
+		}
 	}
+	return ks, nil
 }
 
 // NewFS constructs a KeyStore based on a set of PEM files rooted inside
 // of a directory. The name of each PEM file will be used as the key id.
 // Example: keystore.NewFS(os.DirFS("/secret/jwt/"))
 // Example: /secret/jwt/77a6ddf0-c968-4800-829e-27a26e3b3cbd.pem
+// Example: /secret/jwt/77a6ddf0-c968-4800-829e-27a26e3b3cbd.json
 func NewFS(fsys fs.FS) (*KeyStore, error) {
-	// TODO: need logging here?
-	ks := KeyStore{
-		store: make(map[string]*rsa.PrivateKey),
+	ks := New()
+	ks.fsys = fsys
+	if _, err := ks.Reload(); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// Reload reads the directory again if its files changed since the last
+// load. The keys are swapped at once, on error the current keys are kept.
+func (ks *KeyStore) Reload() (bool, error) {
+	if ks.fsys == nil {
+		return false, nil
+	}
+	fingerprint, err := fingerprintFS(ks.fsys)
+	if err != nil {
+		return false, fmt.Errorf("error walking directory: %w", err)
+	}
+	// an empty directory has an empty fingerprint too, it is never loaded
+	ks.mu.RLock()
+	unchanged := ks.fingerprint != "" && fingerprint == ks.fingerprint
+	ks.mu.RUnlock()
+	if unchanged {
+		return false, nil
+	}
+	store, err := loadFS(ks.fsys)
+	if err != nil {
+		return false, fmt.Errorf("error walking directory: %w", err)
+	}
+	if _, err := signingKeyID(store, time.Now().UTC()); err != nil {
+		return false, err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	ks.store = store
+	ks.fingerprint = fingerprint
+	return true, nil
+}
+
+// Watch reloads the keys every interval until Close is called,
+// so keys can be added and retired without a restart.
+func (ks *KeyStore) Watch(log *zerolog.Logger, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ks.done:
+			return
+		case <-ticker.C:
+			reloaded, err := ks.Reload()
+			if err != nil {
+				log.Err(err).Msg(ErrReloadKeys.Error())
+				continue
+			}
+			if reloaded {
+				log.Info().Msgf("keystore: keys reloaded: %v", ks.CollectKeyIDs())
+			}
+		}
+	}
+}
+
+func (ks *KeyStore) Close() {
+	ks.closeOnce.Do(func() {
+		close(ks.done)
+	})
+}
+
+func (ks *KeyStore) Add(keyID string, key crypto.Signer) error {
+	alg, err := Algorithm(key)
+	if err != nil {
+		return err
+	}
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	now := time.Now().UTC()
+	ks.store[keyID] = Key{
+		ID:        keyID,
+		Private:   key,
+		Algorithm: alg,
+		Created:   now,
+		NotBefore: now,
+	}
+	return nil
+}
+
+func (ks *KeyStore) Remove(keyID string) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	delete(ks.store, keyID)
+}
+
+// PrivateKey searches the key store for a given kid and returns the private key.
+// A retired key is not found.
+func (ks *KeyStore) PrivateKey(kid string) (crypto.PrivateKey, error) {
+	key, err := ks.lookup(kid)
+	if err != nil {
+		return nil, err
 	}
 
+	return key.Private, nil
+}
+
+func (ks *KeyStore) PublicKey(kid string) (crypto.PublicKey, error) {
+	key, err := ks.lookup(kid)
+	if err != nil {
+		return nil, err
+	}
+
+	return key.Private.Public(), nil
+}
+
+// Algorithm returns the JWT algorithm the key signs with.
+func (ks *KeyStore) Algorithm(kid string) (string, error) {
+	key, err := ks.lookup(kid)
+	if err != nil {
+		return "", err
+	}
+
+	return key.Algorithm, nil
+}
+
+// SigningKeyID returns the id of the newest key past its NotBefore.
+func (ks *KeyStore) SigningKeyID() (string, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return signingKeyID(ks.store, time.Now().UTC())
+}
+
+// PublicKeys returns the public keys that are not retired, keys whose
+// NotBefore is ahead are included so verifiers know them before they sign.
+func (ks *KeyStore) PublicKeys() map[string]crypto.PublicKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	now := time.Now().UTC()
+	keys := make(map[string]crypto.PublicKey, len(ks.store))
+	for kid, key := range ks.store {
+		if key.retired(now) {
+			continue
+		}
+		keys[kid] = key.Private.Public()
+	}
+	return keys
+}
+
+func (ks *KeyStore) CollectKeyIDs() []string {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	var ids []string
+	for k := range ks.store {
+		ids = append(ids, k)
+	}
+	return ids
+}
+
+func (ks *KeyStore) lookup(kid string) (Key, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, found := ks.store[kid]
+	if !found || key.retired(time.Now().UTC()) {
+		return Key{}, ErrPrivateKeyLookup
+	}
+
+	return key, nil
+}
+
+func (k Key) retired(now time.Time) bool {
+	return !k.RetireAfter.IsZero() && now.After(k.RetireAfter)
+}
+
+func signingKeyID(store map[string]Key, now time.Time) (string, error) {
+	var signing *Key
+	for _, key := range store {
+		key := key
+		if key.retired(now) || key.NotBefore.After(now) {
+			continue
+		}
+		if signing == nil ||
+			key.NotBefore.After(signing.NotBefore) ||
+			key.NotBefore.Equal(signing.NotBefore) && key.ID > signing.ID {
+			signing = &key
+		}
+	}
+	if signing == nil {
+		return "", ErrSigningKeyLookup
+	}
+	return signing.ID, nil
+}
+
+func loadFS(fsys fs.FS) (map[string]Key, error) {
+	store := make(map[string]Key)
+
 	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
 		if err != nil {
 			return ErrWalkDir
@@ -69,63 +291,87 @@ func NewFS(fsys fs.FS) (*KeyStore, error) {
 			return ErrReadPrivateKeyFile
 		}
 
-		privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+		privateKey, alg, err := ParsePrivateKeyPEM(privatePEM)
 		if err != nil {
-			return ErrParsePrivateKey
+			return err
 		}
 
-		ks.store[strings.TrimSuffix(dirEntry.Name(), ".pem")] = privateKey
+		info, err := dirEntry.Info()
+		if err != nil {
+			return ErrOpenKeyFile
+		}
+		md, err := readMetadata(fsys, strings.TrimSuffix(fileName, ".pem")+".json", info.ModTime().UTC())
+		if err != nil {
+			return err
+		}
+
+		kid := strings.TrimSuffix(dirEntry.Name(), ".pem")
+		key := Key{
+			ID:        kid,
+			Private:   privateKey,
+			Algorithm: alg,
+			Created:   md.Created,
+			NotBefore: md.NotBefore,
+		}
+		if md.RetireAfter != nil {
+			key.RetireAfter = *md.RetireAfter
+		}
+		store[kid] = key
 
 		return nil
 	}
 
 	if err := fs.WalkDir(fsys, ".", fn); err != nil {
-		return nil, fmt.Errorf("error walking directory: %w", err)
+		return nil, err
 	}
 
-	return &ks, nil
+	return store, nil
 }
 
-func (ks *KeyStore) Add(keyID string, key *rsa.PrivateKey) {
-	ks.mu.Lock()
-	defer ks.mu.Unlock()
-	ks.store[keyID] = key
-}
-
-func (ks *KeyStore) Remove(keyID string) {
-	ks.mu.Lock()
-	defer ks.mu.Unlock()
-	delete(ks.store, keyID)
-}
-
-// PrivateKey searches the key store for a given kid and returns the private key.
-func (ks *KeyStore) PrivateKey(kid string) (*rsa.PrivateKey, error) {
-	ks.mu.RLock()
-	defer ks.mu.RUnlock()
-
-	privateKey, found := ks.store[kid]
-	if !found {
-		return nil, ErrPrivateKeyLookup
+func readMetadata(fsys fs.FS, fileName string, modTime time.Time) (Metadata, error) {
+	md := Metadata{}
+	data, err := fs.ReadFile(fsys, fileName)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return Metadata{}, ErrReadMetadataFile
 	}
-
-	return privateKey, nil
-}
-
-func (ks *KeyStore) PublicKey(kid string) (*rsa.PublicKey, error) {
-	privateKey, err := ks.PrivateKey(kid)
-	if err != nil {
-		return nil, err
+	if err == nil {
+		if err := json.Unmarshal(data, &md); err != nil {
+			return Metadata{}, ErrParseMetadata
+		}
 	}
-
-	return &privateKey.PublicKey, nil
+	if md.Created.IsZero() {
+		md.Created = modTime
+	}
+	if md.NotBefore.IsZero() {
+		md.NotBefore = md.Created
+	}
+	return md, nil
 }
 
-func (ks *KeyStore) CollectKeyIDs() []string {
-	ks.mu.RLock()
-	defer ks.mu.RUnlock()
-	var ids []string
-	for k := range ks.store {
-		ids = append(ids, k)
+// fingerprintFS describes the key and metadata files by name, size and
+// modification time, a change of any of them changes the fingerprint.
+func fingerprintFS(fsys fs.FS) (string, error) {
+	var files []string
+	fn := func(fileName string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return ErrWalkDir
+		}
+		if dirEntry.IsDir() {
+			return nil
+		}
+		if ext := path.Ext(fileName); ext != ".pem" && ext != ".json" {
+			return nil
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			return ErrOpenKeyFile
+		}
+		files = append(files, fmt.Sprintf("%s:%d:%d", fileName, info.Size(), info.ModTime().UnixNano()))
+		return nil
 	}
-	return ids
+	if err := fs.WalkDir(fsys, ".", fn); err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+	return strings.Join(files, ";"), nil
 }
@@ -0,0 +1,148 @@
+package keystore
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestSigningKeyID(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	hour := time.Hour
+	key := func(id string, notBefore time.Duration, retireAfter time.Duration) Key {
+		k := Key{ID: id, NotBefore: now.Add(notBefore)}
+		if retireAfter != 0 {
+			k.RetireAfter = now.Add(retireAfter)
+		}
+		return k
+	}
+	tests := []struct {
+		name    string
+		keys    []Key
+		want    string
+		wantErr error
+	}{
+		{"no keys", nil, "", ErrSigningKeyLookup},
+		{"single key", []Key{key("a", -hour, 0)}, "a", nil},
+		{"newest active key", []Key{key("old", -2*hour, 0), key("new", -hour, 0)}, "new", nil},
+		{"key not active yet", []Key{key("old", -hour, 0), key("next", hour, 0)}, "old", nil},
+		{"retired key", []Key{key("old", -2*hour, -hour), key("cur", -3*hour, 0)}, "cur", nil},
+		{"retiring later", []Key{key("old", -2*hour, 0), key("cur", -hour, hour)}, "cur", nil},
+		{"all retired or pending", []Key{key("old", -2*hour, -hour), key("next", hour, 0)}, "", ErrSigningKeyLookup},
+		{"same not before picks the greater id", []Key{key("b", -hour, 0), key("c", -hour, 0), key("a", -hour, 0)}, "c", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := make(map[string]Key)
+			for _, k := range tt.keys {
+				store[k.ID] = k
+			}
+			got, err := signingKeyID(store, now)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("signingKeyID() = %q, %v, want %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func testKeyPEM(t *testing.T) []byte {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func TestNewFS(t *testing.T) {
+	now := time.Now().UTC()
+	modTime := now.Add(-48 * time.Hour)
+	fsys := fstest.MapFS{
+		"old.pem":  {Data: testKeyPEM(t), ModTime: modTime},
+		"old.json": {Data: []byte(`{"created": "` + now.Add(-72*time.Hour).Format(time.RFC3339) + `", "retire_after": "` + now.Add(-time.Hour).Format(time.RFC3339) + `"}`)},
+		"cur.pem":  {Data: testKeyPEM(t), ModTime: modTime},
+		"next.pem": {Data: testKeyPEM(t), ModTime: modTime},
+		"next.json": {Data: []byte(`{"created": "` + now.Format(time.RFC3339) + `", "not_before": "` +
+			now.Add(time.Hour).Format(time.RFC3339) + `"}`)},
+		"README.md": {Data: []byte("not a key")},
+	}
+	ks, err := NewFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, err := ks.SigningKeyID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid != "cur" {
+		t.Errorf("signing key %q, want cur", kid)
+	}
+	pub := ks.PublicKeys()
+	if _, ok := pub["old"]; ok {
+		t.Error("retired key is published")
+	}
+	if _, ok := pub["next"]; !ok {
+		t.Error("pending key is not published")
+	}
+	if _, err := ks.PrivateKey("old"); !errors.Is(err, ErrPrivateKeyLookup) {
+		t.Errorf("retired key lookup: got %v, want %v", err, ErrPrivateKeyLookup)
+	}
+	if alg, err := ks.Algorithm("cur"); err != nil || alg != AlgorithmEdDSA {
+		t.Errorf("Algorithm() = %q, %v, want %q", alg, err, AlgorithmEdDSA)
+	}
+}
+
+func TestNewFSErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		wantErr error
+	}{
+		{"no keys", fstest.MapFS{}, ErrSigningKeyLookup},
+		{"bad key", fstest.MapFS{"a.pem": {Data: []byte("nope")}}, ErrParsePrivateKey},
+		{"bad metadata", fstest.MapFS{"a.pem": {Data: testKeyPEM(t)}, "a.json": {Data: []byte("{")}}, ErrParseMetadata},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewFS(tt.fsys); !errors.Is(err, tt.wantErr) {
+				t.Errorf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	fsys := fstest.MapFS{"a.pem": {Data: testKeyPEM(t), ModTime: old}}
+	ks, err := NewFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := ks.Reload(); reloaded || err != nil {
+		t.Errorf("Reload() of unchanged files = %v, %v", reloaded, err)
+	}
+	fsys["b.pem"] = &fstest.MapFile{Data: testKeyPEM(t), ModTime: time.Now()}
+	if reloaded, err := ks.Reload(); !reloaded || err != nil {
+		t.Errorf("Reload() of a new key = %v, %v", reloaded, err)
+	}
+	if kid, _ := ks.SigningKeyID(); kid != "b" {
+		t.Errorf("signing key %q, want b", kid)
+	}
+	// a broken directory keeps the loaded keys
+	fsys["c.pem"] = &fstest.MapFile{Data: []byte("nope")}
+	if _, err := ks.Reload(); !errors.Is(err, ErrParsePrivateKey) {
+		t.Errorf("Reload() = %v, want %v", err, ErrParsePrivateKey)
+	}
+	if kid, _ := ks.SigningKeyID(); kid != "b" {
+		t.Errorf("signing key %q after a failed reload, want b", kid)
+	}
+}
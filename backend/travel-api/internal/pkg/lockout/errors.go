@@ -0,0 +1,12 @@
+package lockout
+
+import "errors"
+
+var (
+	ErrCheckAttempts      = errors.New("error checking failed login attempts")
+	ErrStoreAttempt       = errors.New("error storing failed login attempt")
+	ErrResetAttempts      = errors.New("error resetting failed login attempts")
+	ErrGenUnlockToken     = errors.New("error generating unlock token")
+	ErrInvalidUnlockToken = errors.New("error invalid unlock token")
+	ErrLocked             = errors.New("too many failed login attempts")
+)
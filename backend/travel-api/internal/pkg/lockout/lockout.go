@@ -0,0 +1,223 @@
+// Package lockout slows down and locks logins after failed attempts. The
+// failures are counted in Redis per account and per client IP, so that the
+// counters are shared by all API instances.
+package lockout
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+// freeAttempts is the number of failures before the attempts are delayed.
+const freeAttempts = 3
+
+const (
+	failPrefix   = "login_fail:"
+	lockPrefix   = "login_lock:"
+	delayPrefix  = "login_delay:"
+	unlockPrefix = "login_unlock:"
+)
+
+type Config struct {
+	Cache *redis.Client
+	Log   *zerolog.Logger
+	// MaxAttempts is the number of failures of an account that locks it.
+	MaxAttempts int
+	// MaxIPAttempts is the number of failures from an IP that locks the IP.
+	MaxIPAttempts int
+	// Delay is the wait after the first delayed failure, it doubles with
+	// every further failure up to LockDuration.
+	Delay time.Duration
+	// LockDuration is how long a lock lasts and failures are remembered.
+	LockDuration time.Duration
+}
+
+type Lockout struct {
+	cache         *redis.Client
+	log           *zerolog.Logger
+	maxAttempts   int64
+	maxIPAttempts int64
+	delay         time.Duration
+	lockDuration  time.Duration
+}
+
+// Failure is the outcome of a failed attempt. UnlockToken is set when the
+// attempt locked the account, to send it to the owner of the account.
+type Failure struct {
+	Locked      bool
+	UnlockToken string
+	RetryAfter  time.Duration
+}
+
+func New(cfg Config) *Lockout {
+	return &Lockout{
+		cache:         cfg.Cache,
+		log:           cfg.Log,
+		maxAttempts:   int64(cfg.MaxAttempts),
+		maxIPAttempts: int64(cfg.MaxIPAttempts),
+		delay:         cfg.Delay,
+		lockDuration:  cfg.LockDuration,
+	}
+}
+
+// Check returns how long the client has to wait before the next attempt
+// for the account, zero if it may try now.
+func (l *Lockout) Check(ctx context.Context, account string, ip string) (time.Duration, error) {
+	pipe := l.cache.Pipeline()
+	ttls := []*redis.DurationCmd{
+		pipe.PTTL(ctx, lockPrefix+accountKey(account)),
+		pipe.PTTL(ctx, lockPrefix+ipKey(ip)),
+		pipe.PTTL(ctx, delayPrefix+accountKey(account)),
+		pipe.PTTL(ctx, delayPrefix+ipKey(ip)),
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		l.log.Err(err).Msg(ErrCheckAttempts.Error())
+		return 0, ErrCheckAttempts
+	}
+	var wait time.Duration
+	for _, ttl := range ttls {
+		// missing keys have negative ttls
+		if d := ttl.Val(); d > wait {
+			wait = d
+		}
+	}
+	return wait, nil
+}
+
+// Fail counts a failed attempt. Past the free attempts the next attempt is
+// delayed, the account or the IP is locked once it reaches its maximum.
+func (l *Lockout) Fail(ctx context.Context, account string, ip string) (Failure, error) {
+	pipe := l.cache.TxPipeline()
+	accountFails := pipe.Incr(ctx, failPrefix+accountKey(account))
+	pipe.Expire(ctx, failPrefix+accountKey(account), l.lockDuration)
+	ipFails := pipe.Incr(ctx, failPrefix+ipKey(ip))
+	pipe.Expire(ctx, failPrefix+ipKey(ip), l.lockDuration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		l.log.Err(err).Msg(ErrStoreAttempt.Error())
+		return Failure{}, ErrStoreAttempt
+	}
+	f := Failure{}
+	if n := ipFails.Val(); n >= l.maxIPAttempts {
+		if err := l.cache.Set(ctx, lockPrefix+ipKey(ip), n, l.lockDuration).Err(); err != nil {
+			l.log.Err(err).Msg(ErrStoreAttempt.Error())
+			return Failure{}, ErrStoreAttempt
+		}
+		f.RetryAfter = l.lockDuration
+	} else if d := l.delayOf(n, l.maxIPAttempts); d > 0 {
+		if err := l.cache.Set(ctx, delayPrefix+ipKey(ip), n, d).Err(); err != nil {
+			l.log.Err(err).Msg(ErrStoreAttempt.Error())
+			return Failure{}, ErrStoreAttempt
+		}
+		f.RetryAfter = d
+	}
+	n := accountFails.Val()
+	switch {
+	// only the attempt reaching the maximum locks, so one email goes out per lock
+	case n == l.maxAttempts:
+		token, err := l.lock(ctx, account)
+		if err != nil {
+			return Failure{}, err
+		}
+		f.Locked = true
+		f.UnlockToken = token
+		f.RetryAfter = l.lockDuration
+	case n > l.maxAttempts:
+		f.RetryAfter = l.lockDuration
+	default:
+		// the account delay is kept even when the IP one is longer, the
+		// next attempt may come from another IP
+		if d := l.delayOf(n, l.maxAttempts); d > 0 {
+			if err := l.cache.Set(ctx, delayPrefix+accountKey(account), n, d).Err(); err != nil {
+				l.log.Err(err).Msg(ErrStoreAttempt.Error())
+				return Failure{}, ErrStoreAttempt
+			}
+			if d > f.RetryAfter {
+				f.RetryAfter = d
+			}
+		}
+	}
+	return f, nil
+}
+
+// Succeed forgets the failures of the account, those of the IP stay.
+func (l *Lockout) Succeed(ctx context.Context, account string) error {
+	if err := l.cache.Del(
+		ctx,
+		failPrefix+accountKey(account),
+		delayPrefix+accountKey(account),
+	).Err(); err != nil {
+		l.log.Err(err).Msg(ErrResetAttempts.Error())
+		return ErrResetAttempts
+	}
+	return nil
+}
+
+// Unlock lifts the lock of the account the token was issued for,
+// the token can be used once.
+func (l *Lockout) Unlock(ctx context.Context, token string) error {
+	account, err := l.cache.GetDel(ctx, unlockPrefix+token).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return ErrInvalidUnlockToken
+		}
+		l.log.Err(err).Msg(ErrResetAttempts.Error())
+		return ErrResetAttempts
+	}
+	if err := l.cache.Del(
+		ctx,
+		failPrefix+accountKey(account),
+		delayPrefix+accountKey(account),
+		lockPrefix+accountKey(account),
+	).Err(); err != nil {
+		l.log.Err(err).Msg(ErrResetAttempts.Error())
+		return ErrResetAttempts
+	}
+	return nil
+}
+
+func (l *Lockout) lock(ctx context.Context, account string) (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		l.log.Err(err).Msg(ErrGenUnlockToken.Error())
+		return "", ErrGenUnlockToken
+	}
+	token := hex.EncodeToString(b)
+	pipe := l.cache.TxPipeline()
+	pipe.Set(ctx, lockPrefix+accountKey(account), l.maxAttempts, l.lockDuration)
+	pipe.Set(ctx, unlockPrefix+token, account, l.lockDuration)
+	if _, err := pipe.Exec(ctx); err != nil {
+		l.log.Err(err).Msg(ErrStoreAttempt.Error())
+		return "", ErrStoreAttempt
+	}
+	return token, nil
+}
+
+// delayOf returns the wait after the nth failure, doubling from the
+// first failure past the free attempts and never reaching a lock.
+func (l *Lockout) delayOf(n int64, max int64) time.Duration {
+	if n <= freeAttempts || n >= max {
+		return 0
+	}
+	d := l.delay
+	for i := int64(freeAttempts + 1); i < n; i++ {
+		d *= 2
+		if d >= l.lockDuration {
+			return l.lockDuration
+		}
+	}
+	return d
+}
+
+func accountKey(account string) string {
+	return "account:" + account
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
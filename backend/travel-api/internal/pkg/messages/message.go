@@ -5,12 +5,27 @@ type MessageType int
 const (
 	ResetPassword MessageType = iota
 	RegisterVerify
+	ListInvite
+	LoginUnlock
+	EmailChangeConfirm
+	EmailChangeNotice
+	MagicLink
+	DataExportReady
 )
 
 type Message struct {
-	ID    string      `json:"id"`
-	Email string      `json:"email"`
-	Name  string      `json:"name"`
-	Token string      `json:"token"`
-	Type  MessageType `json:"type"`
+	ID       string      `json:"id"`
+	Email    string      `json:"email"`
+	Name     string      `json:"name"`
+	Token    string      `json:"token"`
+	Type     MessageType `json:"type"`
+	ListID   string      `json:"list_id,omitempty"`
+	ListName string      `json:"list_name,omitempty"`
+	Sender   string      `json:"sender,omitempty"`
+}
+
+// Export asks for the archive of the data of the user to be built.
+type Export struct {
+	ID       string `json:"id"`
+	ExportID string `json:"export_id"`
 }
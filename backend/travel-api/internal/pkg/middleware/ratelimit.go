@@ -5,6 +5,7 @@ import (
 	"errors"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/f4mk/travel/backend/travel-api/internal/pkg/web"
 	"github.com/rs/zerolog"
@@ -38,7 +39,7 @@ func RateLimit(log *zerolog.Logger, rps int) web.Middleware {
 			// Context was done either by cancelation or timeout
 			if err != nil {
 				log.Warn().Msgf("user reached rate limit: %s", v.TraceID)
-				return web.NewRequestError(errors.New("too many requests"), http.StatusTooManyRequests)
+				return web.NewTooManyRequestsError(errors.New("too many requests"), time.Second)
 			}
 
 			return handler(ctx, w, r)
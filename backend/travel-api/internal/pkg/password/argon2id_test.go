@@ -0,0 +1,107 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testParams keep the tests fast, the cost is not under test.
+var testParams = Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  8,
+	KeyLength:   16,
+}
+
+func TestArgon2idHash(t *testing.T) {
+	a := NewArgon2id(testParams)
+	hash, err := a.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(hash), "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("unexpected hash %s", hash)
+	}
+	p, salt, key, err := decodeArgon2id(hash)
+	if err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if p != testParams {
+		t.Errorf("decoded params %+v, want %+v", p, testParams)
+	}
+	if len(salt) != int(testParams.SaltLength) || len(key) != int(testParams.KeyLength) {
+		t.Errorf("salt of %d bytes and key of %d bytes", len(salt), len(key))
+	}
+	other, err := a.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(other) == string(hash) {
+		t.Error("two hashes of a password share the salt")
+	}
+}
+
+func TestArgon2idCompare(t *testing.T) {
+	a := NewArgon2id(testParams)
+	hash, err := a.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		hash     string
+		password string
+		want     error
+	}{
+		{"match", string(hash), "correct horse", nil},
+		{"mismatch", string(hash), "correct horsE", ErrMismatch},
+		{"empty password", string(hash), "", ErrMismatch},
+		{
+			// the reference implementation test vector
+			"other params",
+			"$argon2id$v=19$m=65536,t=2,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c8y7cJHAph8ArZWb2GRPPc",
+			"password",
+			nil,
+		},
+		{"wrong version", "$argon2id$v=16$m=64,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c", "password", ErrMalformedHash},
+		{"missing key", "$argon2id$v=19$m=64,t=1,p=1$c29tZXNhbHQ$", "password", ErrMalformedHash},
+		{"missing part", "$argon2id$v=19$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c", "password", ErrMalformedHash},
+		{"bad params", "$argon2id$v=19$m=x,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c", "password", ErrMalformedHash},
+		{"bad salt", "$argon2id$v=19$m=64,t=1,p=1$!!!$CTFhFdXPJO1aFaMaO6Mm5c", "password", ErrMalformedHash},
+		{"other scheme", "$argon2i$v=19$m=64,t=1,p=1$c29tZXNhbHQ$CTFhFdXPJO1aFaMaO6Mm5c", "password", ErrMalformedHash},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := a.Compare([]byte(tt.hash), tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("Compare() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewArgon2idDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		p    Argon2idParams
+		want Argon2idParams
+	}{
+		{"all zero", Argon2idParams{}, DefaultArgon2idParams},
+		{"some set", Argon2idParams{Memory: 1024, KeyLength: 64}, Argon2idParams{
+			Memory:      1024,
+			Iterations:  DefaultArgon2idParams.Iterations,
+			Parallelism: DefaultArgon2idParams.Parallelism,
+			SaltLength:  DefaultArgon2idParams.SaltLength,
+			KeyLength:   64,
+		}},
+		{"all set", testParams, testParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewArgon2id(tt.p).params; got != tt.want {
+				t.Errorf("params %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package password
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Bcrypt checks the bcrypt hashes made before argon2id, it makes no new ones.
+type Bcrypt struct{}
+
+func (Bcrypt) Claims(hash []byte) bool {
+	id := phcID(hash)
+	return bytes.Equal(id, []byte("2a")) || bytes.Equal(id, []byte("2b")) || bytes.Equal(id, []byte("2y"))
+}
+
+func (Bcrypt) Compare(hash []byte, password string) error {
+	err := bcrypt.CompareHashAndPassword(hash, []byte(password))
+	if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+		return ErrMismatch
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// prefixLength is the length of the hash prefix the k-anonymity range
+// queries are made with.
+const prefixLength = 5
+
+// Breached is a set of breached passwords known by their SHA-1 hashes.
+// The hashes are grouped by their prefix the way the k-anonymity range
+// queries of Have I Been Pwned return them, so a lookup reads one range.
+type Breached struct {
+	ranges map[string][]string
+}
+
+// LoadBreached reads the breached passwords file at the path. Each line is
+// an uppercase or lowercase SHA-1 hash, optionally followed by :count as in
+// the Have I Been Pwned downloads. A range response can be stored as is
+// under a "PREFIX" line of its own, the lines that follow hold the suffixes.
+func LoadBreached(path string) (*Breached, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadBreached, err)
+	}
+	defer f.Close()
+	b := Breached{
+		ranges: make(map[string][]string),
+	}
+	prefix := ""
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		h, _, _ := strings.Cut(line, ":")
+		h = strings.ToUpper(h)
+		if !isHex(h) {
+			return nil, fmt.Errorf("%w: line %d", ErrLoadBreached, n)
+		}
+		switch len(h) {
+		case prefixLength:
+			prefix = h
+		case sha1.Size * 2:
+			b.ranges[h[:prefixLength]] = append(b.ranges[h[:prefixLength]], h[prefixLength:])
+		case sha1.Size*2 - prefixLength:
+			if prefix == "" {
+				return nil, fmt.Errorf("%w: line %d: suffix without prefix", ErrLoadBreached, n)
+			}
+			b.ranges[prefix] = append(b.ranges[prefix], h)
+		default:
+			return nil, fmt.Errorf("%w: line %d", ErrLoadBreached, n)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrLoadBreached, err)
+	}
+	for _, r := range b.ranges {
+		sort.Strings(r)
+	}
+	return &b, nil
+}
+
+// Contains reports whether the password is in the set.
+func (b *Breached) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	h := strings.ToUpper(hex.EncodeToString(sum[:]))
+	r := b.ranges[h[:prefixLength]]
+	i := sort.SearchStrings(r, h[prefixLength:])
+	return i < len(r) && r[i] == h[prefixLength:]
+}
+
+func isHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'A' || c > 'F') {
+			return false
+		}
+	}
+	return s != ""
+}
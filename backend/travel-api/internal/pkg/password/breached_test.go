@@ -0,0 +1,101 @@
+package password
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// SHA-1 hashes of the passwords the tests look up.
+const (
+	sha1Password = "5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8"
+	sha1Qwerty   = "B1B3773A05C0ED0176787A4F1574FF0075F7521E"
+	sha1Numbers  = "7C4A8D09CA3762AF61E59520943DC26494F8941B" // 123456
+)
+
+func writeBreached(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadBreached(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		in   []string
+		out  []string
+	}{
+		{
+			name: "full hashes",
+			data: sha1Password + "\n" + sha1Qwerty + "\n",
+			in:   []string{"password", "qwerty"},
+			out:  []string{"123456", "Password", ""},
+		},
+		{
+			name: "lowercase hashes with counts and comments",
+			data: "# top passwords\n\n5baa61e4c9b93f3f0682250b6cf8331b7ee68fd8:9545824\n" + sha1Numbers + ":37359195\n",
+			in:   []string{"password", "123456"},
+			out:  []string{"qwerty"},
+		},
+		{
+			name: "range responses under their prefix",
+			data: "5BAA6\n" + sha1Password[5:] + ":9545824\n0000000000000000000000000000000000A:1\nB1B37\n" + sha1Qwerty[5:] + ":1\n",
+			in:   []string{"password", "qwerty"},
+			out:  []string{"123456"},
+		},
+		{
+			name: "empty",
+			data: "",
+			out:  []string{"password"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := LoadBreached(writeBreached(t, tt.data))
+			if err != nil {
+				t.Fatalf("LoadBreached: %v", err)
+			}
+			for _, p := range tt.in {
+				if !b.Contains(p) {
+					t.Errorf("Contains(%q) = false, want true", p)
+				}
+			}
+			for _, p := range tt.out {
+				if b.Contains(p) {
+					t.Errorf("Contains(%q) = true, want false", p)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadBreachedMalformed(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"not hex", "ZZAA61E4C9B93F3F0682250B6CF8331B7EE68FD8\n"},
+		{"wrong length", "5BAA61E4C9\n"},
+		{"suffix without prefix", sha1Password[5:] + ":1\n"},
+		{"count only", ":12\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadBreached(writeBreached(t, tt.data)); !errors.Is(err, ErrLoadBreached) {
+				t.Errorf("got %v, want %v", err, ErrLoadBreached)
+			}
+		})
+	}
+}
+
+func TestLoadBreachedMissingFile(t *testing.T) {
+	_, err := LoadBreached(filepath.Join(t.TempDir(), "missing.txt"))
+	if !errors.Is(err, ErrLoadBreached) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, want %v wrapping %v", err, ErrLoadBreached, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,18 @@
+package password
+
+import "errors"
+
+var (
+	ErrMismatch      = errors.New("error password does not match hash")
+	ErrUnknownScheme = errors.New("error unknown password hash scheme")
+	ErrMalformedHash = errors.New("error malformed password hash")
+	ErrGenSalt       = errors.New("error generating password salt")
+	ErrLoadBreached  = errors.New("error loading breached passwords")
+)
+
+// Policy errors are shown to the user as the reason the password is refused.
+var (
+	ErrTooWeak           = errors.New("password is too easy to guess")
+	ErrContainsUserInput = errors.New("password must not contain your name or email")
+	ErrBreached          = errors.New("password has appeared in a data breach")
+)
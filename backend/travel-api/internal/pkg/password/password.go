@@ -0,0 +1,67 @@
+// Package password hashes and checks passwords. Hashes are stored in the
+// PHC string format, so hashes of different schemes live side by side: new
+// hashes are made with argon2id, older bcrypt hashes are still checked and
+// reported for rehashing.
+package password
+
+import (
+	"bytes"
+)
+
+// Scheme is a hashing algorithm. Hasher checks a hash with the scheme
+// that claims it.
+type Scheme interface {
+	// Claims reports whether the hash was made by the scheme.
+	Claims(hash []byte) bool
+	Compare(hash []byte, password string) error
+}
+
+type Hasher struct {
+	argon   *Argon2id
+	schemes []Scheme
+}
+
+// New returns a hasher making argon2id hashes with the params and checking
+// the hashes of the schemes given in addition to argon2id and bcrypt.
+func New(p Argon2idParams, schemes ...Scheme) *Hasher {
+	a := NewArgon2id(p)
+	return &Hasher{
+		argon:   a,
+		schemes: append([]Scheme{a, Bcrypt{}}, schemes...),
+	}
+}
+
+// Hash returns the PHC string of the argon2id hash of the password.
+func (h *Hasher) Hash(password string) ([]byte, error) {
+	return h.argon.Hash(password)
+}
+
+// Compare returns nil if the hash is of the password, ErrMismatch if not
+// and ErrUnknownScheme if no scheme claims the hash.
+func (h *Hasher) Compare(hash []byte, password string) error {
+	for _, s := range h.schemes {
+		if s.Claims(hash) {
+			return s.Compare(hash, password)
+		}
+	}
+	return ErrUnknownScheme
+}
+
+// NeedsRehash reports whether the hash should be replaced by a new one,
+// because it is not argon2id or its params are not the current ones.
+func (h *Hasher) NeedsRehash(hash []byte) bool {
+	if !h.argon.Claims(hash) {
+		return true
+	}
+	p, _, _, err := decodeArgon2id(hash)
+	return err != nil || p != h.argon.params
+}
+
+// phcID returns the scheme id of a PHC string such as $argon2id$...
+func phcID(hash []byte) []byte {
+	if len(hash) == 0 || hash[0] != '$' {
+		return nil
+	}
+	id, _, _ := bytes.Cut(hash[1:], []byte("$"))
+	return id
+}
@@ -0,0 +1,128 @@
+package password
+
+import (
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func bcryptHash(t *testing.T, password string) []byte {
+	t.Helper()
+	h, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestHasherCompare(t *testing.T) {
+	h := New(testParams)
+	argon, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := bcryptHash(t, "correct horse")
+	tests := []struct {
+		name     string
+		hash     []byte
+		password string
+		want     error
+	}{
+		{"argon2id match", argon, "correct horse", nil},
+		{"argon2id mismatch", argon, "battery staple", ErrMismatch},
+		{"bcrypt match", bc, "correct horse", nil},
+		{"bcrypt mismatch", bc, "battery staple", ErrMismatch},
+		{"bcrypt 2y", append([]byte("$2y"), bc[3:]...), "correct horse", nil},
+		{"unknown scheme", []byte("$scrypt$ln=15,r=8,p=1$c2FsdA$a2V5"), "correct horse", ErrUnknownScheme},
+		{"not phc", []byte("plain"), "plain", ErrUnknownScheme},
+		{"empty", nil, "", ErrUnknownScheme},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := h.Compare(tt.hash, tt.password); !errors.Is(err, tt.want) {
+				t.Errorf("Compare() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// plainScheme claims hashes of the form $plain$<password>.
+type plainScheme struct{}
+
+func (plainScheme) Claims(hash []byte) bool {
+	return string(phcID(hash)) == "plain"
+}
+
+func (plainScheme) Compare(hash []byte, password string) error {
+	if string(hash) != "$plain$"+password {
+		return ErrMismatch
+	}
+	return nil
+}
+
+func TestHasherExtraSchemes(t *testing.T) {
+	h := New(testParams, plainScheme{})
+	if err := h.Compare([]byte("$plain$secret"), "secret"); err != nil {
+		t.Errorf("Compare() = %v, want nil", err)
+	}
+	if !h.NeedsRehash([]byte("$plain$secret")) {
+		t.Error("a hash of another scheme does not need a rehash")
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	h := New(testParams)
+	current, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stronger := testParams
+	stronger.Iterations++
+	old, err := NewArgon2id(stronger).Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	longerKey := testParams
+	longerKey.KeyLength = 32
+	otherKey, err := NewArgon2id(longerKey).Hash("correct horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		hash []byte
+		want bool
+	}{
+		{"current params", current, false},
+		{"other iterations", old, true},
+		{"other key length", otherKey, true},
+		{"bcrypt", bcryptHash(t, "correct horse"), true},
+		{"malformed argon2id", []byte("$argon2id$v=19$broken"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.NeedsRehash(tt.hash); got != tt.want {
+				t.Errorf("NeedsRehash() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhcID(t *testing.T) {
+	tests := []struct {
+		hash string
+		want string
+	}{
+		{"$argon2id$v=19$m=64,t=1,p=1$salt$key", "argon2id"},
+		{"$2a$10$abcdefghijklmnopqrstuv", "2a"},
+		{"$argon2id", "argon2id"},
+		{"argon2id$v=19", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := string(phcID([]byte(tt.hash))); got != tt.want {
+			t.Errorf("phcID(%q) = %q, want %q", tt.hash, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package password
+
+import (
+	"strings"
+)
+
+// minUserInputLength keeps short names such as "Al" from rejecting
+// every password that happens to contain them.
+const minUserInputLength = 3
+
+// PolicyConfig tells the policy which passwords to accept. MinScore is the
+// lowest accepted strength score from 0 to 4, BreachedPath is the breached
+// passwords file, no breach check is made if it is empty.
+type PolicyConfig struct {
+	MinScore     int
+	BreachedPath string
+}
+
+// Policy decides whether a password is good enough to be set.
+type Policy struct {
+	minScore int
+	breached *Breached
+}
+
+// NewPolicy returns a policy for the config, loading the breached
+// passwords file if one is set.
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	p := Policy{
+		minScore: cfg.MinScore,
+	}
+	if cfg.BreachedPath != "" {
+		b, err := LoadBreached(cfg.BreachedPath)
+		if err != nil {
+			return nil, err
+		}
+		p.breached = b
+	}
+	return &p, nil
+}
+
+// Check returns nil if the password can be set by the user with the
+// inputs, such as their name and email. Otherwise it returns
+// ErrContainsUserInput, ErrBreached or ErrTooWeak.
+func (p *Policy) Check(password string, userInputs ...string) error {
+	inputs := splitUserInputs(userInputs)
+	lower := strings.ToLower(password)
+	for _, in := range inputs {
+		if strings.Contains(lower, in) {
+			return ErrContainsUserInput
+		}
+	}
+	if p.breached != nil && p.breached.Contains(password) {
+		return ErrBreached
+	}
+	if Score(password, inputs...) < p.minScore {
+		return ErrTooWeak
+	}
+	return nil
+}
+
+// splitUserInputs returns the lowercased inputs along with the words of the
+// names and the local part of the emails.
+func splitUserInputs(userInputs []string) []string {
+	var res []string
+	add := func(s string) {
+		if len(s) >= minUserInputLength {
+			res = append(res, s)
+		}
+	}
+	for _, in := range userInputs {
+		in = strings.ToLower(strings.TrimSpace(in))
+		add(in)
+		if local, _, ok := strings.Cut(in, "@"); ok {
+			add(local)
+			in = local
+		}
+		words := strings.FieldsFunc(in, func(r rune) bool {
+			return r == ' ' || r == '.' || r == '_' || r == '-' || r == '+'
+		})
+		if len(words) > 1 {
+			for _, w := range words {
+				add(w)
+			}
+		}
+	}
+	return res
+}
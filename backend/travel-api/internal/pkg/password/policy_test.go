@@ -0,0 +1,74 @@
+package password
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	p, err := NewPolicy(PolicyConfig{
+		MinScore:     3,
+		BreachedPath: writeBreached(t, sha1Qwerty+"\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		password   string
+		userInputs []string
+		want       error
+	}{
+		{"strong", "kjh28dbna9", []string{"Jane Smith", "jane.smith@example.com"}, nil},
+		{"weak", "password", nil, ErrTooWeak},
+		{"contains the name", "xx-smith-9q7", []string{"Jane Smith"}, ErrContainsUserInput},
+		{"contains the email local part", "9q7JANE.SMITHxx", []string{"", "jane.smith@example.com"}, ErrContainsUserInput},
+		{"contains a word of the email", "9q7smithxx", []string{"jane.smith@example.com"}, ErrContainsUserInput},
+		{"short names are ignored", "al8kjh28dbna9", []string{"Al"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := p.Check(tt.password, tt.userInputs...); !errors.Is(err, tt.want) {
+				t.Errorf("Check(%q) = %v, want %v", tt.password, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCheckBreached(t *testing.T) {
+	p, err := NewPolicy(PolicyConfig{
+		MinScore:     0,
+		BreachedPath: writeBreached(t, sha1Password+"\n"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Check("password"); !errors.Is(err, ErrBreached) {
+		t.Errorf("Check() = %v, want %v", err, ErrBreached)
+	}
+	if err := p.Check("qwerty"); err != nil {
+		t.Errorf("Check() = %v, want nil", err)
+	}
+}
+
+func TestNewPolicyBadBreachedFile(t *testing.T) {
+	if _, err := NewPolicy(PolicyConfig{BreachedPath: writeBreached(t, "nope\n")}); !errors.Is(err, ErrLoadBreached) {
+		t.Errorf("got %v, want %v", err, ErrLoadBreached)
+	}
+}
+
+func TestSplitUserInputs(t *testing.T) {
+	got := splitUserInputs([]string{" Jane Smith ", "Jane.Smith+trips@Example.com", "Al"})
+	want := []string{
+		"jane smith", "jane", "smith",
+		"jane.smith+trips@example.com", "jane.smith+trips", "jane", "smith", "trips",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package password
+
+import (
+	_ "embed"
+	"math"
+	"strings"
+)
+
+// The strength is estimated the way zxcvbn does it: the password is split
+// into the patterns an attacker would try first (common passwords, user
+// inputs, keyboard walks, sequences, repeats, years) and brute force for the
+// rest, the split needing the fewest guesses gives the score.
+
+// maxScoredLength bounds the work of scoring, the tail of a longer password
+// only makes it stronger.
+const maxScoredLength = 64
+
+const (
+	bruteforceCardinality = 10
+	minGuessesSingleChar  = 10
+	minGuessesMultiChar   = 50
+	referenceYear         = 2020
+	minYearSpace          = 20
+)
+
+//go:embed common.txt
+var commonList string
+
+// commonRanks ranks the common passwords and words by how often they are used.
+var commonRanks = func() map[string]int {
+	m := make(map[string]int)
+	for i, w := range strings.Fields(commonList) {
+		if _, ok := m[w]; !ok {
+			m[w] = i + 1
+		}
+	}
+	return m
+}()
+
+var keyboardRows = []string{
+	"1234567890",
+	"qwertyuiop",
+	"asdfghjkl",
+	"zxcvbnm",
+	"qazwsxedcrfvtgbyhnujmikolp",
+}
+
+var leetSubs = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i',
+	'!': 'i', '|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '2': 'z',
+}
+
+// Score returns the strength of the password from 0, too guessable, to 4,
+// very unguessable. The user inputs are guessed along with common passwords.
+func Score(password string, userInputs ...string) int {
+	g := log10Guesses(password, userInputs)
+	switch {
+	case g < 3:
+		return 0
+	case g < 6:
+		return 1
+	case g < 8:
+		return 2
+	case g < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// log10Guesses returns the log10 of the guesses needed to find the password.
+func log10Guesses(password string, userInputs []string) float64 {
+	r := []rune(password)
+	if len(r) > maxScoredLength {
+		r = r[:maxScoredLength]
+	}
+	n := len(r)
+	if n == 0 {
+		return 0
+	}
+	ranks := make(map[string]int, len(userInputs))
+	for i, in := range userInputs {
+		ranks[strings.ToLower(in)] = i + 1
+	}
+	// matches[j] holds the patterns ending at j as start index and guesses
+	matches := make([][]match, n)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			matches[j] = append(matches[j], match{start: i, guesses: patternGuesses(r[i:j+1], ranks)})
+		}
+	}
+	// best[j][l] is the fewest guesses of r[:j+1] split into l+1 parts
+	best := make([][]float64, n)
+	for j := range best {
+		best[j] = make([]float64, n)
+		for l := range best[j] {
+			best[j][l] = math.Inf(1)
+		}
+	}
+	for j := 0; j < n; j++ {
+		for _, m := range matches[j] {
+			if m.start == 0 {
+				best[j][0] = math.Min(best[j][0], m.guesses)
+				continue
+			}
+			for l := 1; l <= m.start; l++ {
+				if prev := best[m.start-1][l-1]; !math.IsInf(prev, 1) {
+					best[j][l] = math.Min(best[j][l], prev+m.guesses)
+				}
+			}
+		}
+	}
+	// guessing the order of the parts costs a factorial of their count
+	res := math.Inf(1)
+	fact := 0.0
+	for l := 0; l < n; l++ {
+		fact += math.Log10(float64(l + 1))
+		res = math.Min(res, best[n-1][l]+fact)
+	}
+	return res
+}
+
+type match struct {
+	start int
+	// guesses are in log10
+	guesses float64
+}
+
+// patternGuesses returns the log10 guesses of the token as the cheapest
+// pattern it matches, brute force matches every token.
+func patternGuesses(token []rune, userRanks map[string]int) float64 {
+	g := float64(len(token)) * math.Log10(bruteforceCardinality)
+	lower := strings.ToLower(string(token))
+	if p, ok := dictionaryGuesses(lower, userRanks); ok {
+		g = math.Min(g, p+math.Log10(uppercaseVariations(token)))
+	}
+	if p, ok := repeatGuesses(token); ok {
+		g = math.Min(g, p)
+	}
+	if p, ok := sequenceGuesses(token); ok {
+		g = math.Min(g, p)
+	}
+	if p, ok := spatialGuesses(lower); ok {
+		g = math.Min(g, p)
+	}
+	if p, ok := yearGuesses(token); ok {
+		g = math.Min(g, p)
+	}
+	floor := float64(minGuessesMultiChar)
+	if len(token) == 1 {
+		floor = minGuessesSingleChar
+	}
+	return math.Max(g, math.Log10(floor))
+}
+
+func dictionaryGuesses(lower string, userRanks map[string]int) (float64, bool) {
+	rank := func(w string) int {
+		if r, ok := userRanks[w]; ok {
+			return r
+		}
+		return commonRanks[w]
+	}
+	best := math.Inf(1)
+	if r := rank(lower); r > 0 {
+		best = math.Log10(float64(r))
+	}
+	if r := rank(reverse(lower)); r > 0 {
+		best = math.Min(best, math.Log10(float64(r)*2))
+	}
+	if unleet, ok := unleet(lower); ok {
+		if r := rank(unleet); r > 0 {
+			best = math.Min(best, math.Log10(float64(r)*2))
+		}
+	}
+	return best, !math.IsInf(best, 1)
+}
+
+// uppercaseVariations returns the number of ways the letters of the token
+// could have been capitalized, the common ones count as two.
+func uppercaseVariations(token []rune) float64 {
+	var upper, lower int
+	for _, c := range token {
+		switch {
+		case c >= 'A' && c <= 'Z':
+			upper++
+		case c >= 'a' && c <= 'z':
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	first := token[0] >= 'A' && token[0] <= 'Z'
+	last := token[len(token)-1] >= 'A' && token[len(token)-1] <= 'Z'
+	if lower == 0 || upper == 1 && (first || last) {
+		return 2
+	}
+	var v float64
+	for k := 1; k <= upper && k <= lower; k++ {
+		v += binomial(upper+lower, k)
+	}
+	return v
+}
+
+func repeatGuesses(token []rune) (float64, bool) {
+	if len(token) < 3 {
+		return 0, false
+	}
+	for _, c := range token[1:] {
+		if c != token[0] {
+			return 0, false
+		}
+	}
+	return math.Log10(charsetSize(token[0]) * float64(len(token))), true
+}
+
+func sequenceGuesses(token []rune) (float64, bool) {
+	if len(token) < 3 {
+		return 0, false
+	}
+	delta := token[1] - token[0]
+	if delta != 1 && delta != -1 {
+		return 0, false
+	}
+	for i := 2; i < len(token); i++ {
+		if token[i]-token[i-1] != delta {
+			return 0, false
+		}
+	}
+	var base float64
+	switch token[0] {
+	case 'a', 'A', 'z', 'Z', '0', '1', '9':
+		base = 4
+	default:
+		base = charsetSize(token[0])
+	}
+	if delta < 0 {
+		base *= 2
+	}
+	return math.Log10(base * float64(len(token))), true
+}
+
+func spatialGuesses(lower string) (float64, bool) {
+	if len(lower) < 4 {
+		return 0, false
+	}
+	for _, row := range keyboardRows {
+		if strings.Contains(row, lower) || strings.Contains(row, reverse(lower)) {
+			return math.Log10(float64(len(row) * len(lower) * 4)), true
+		}
+	}
+	return 0, false
+}
+
+func yearGuesses(token []rune) (float64, bool) {
+	if len(token) != 4 {
+		return 0, false
+	}
+	year := 0
+	for _, c := range token {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		year = year*10 + int(c-'0')
+	}
+	if year < 1900 || year > 2099 {
+		return 0, false
+	}
+	space := math.Max(math.Abs(float64(year-referenceYear)), minYearSpace)
+	return math.Log10(space), true
+}
+
+func charsetSize(c rune) float64 {
+	switch {
+	case c >= '0' && c <= '9':
+		return 10
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		return 26
+	default:
+		return 33
+	}
+}
+
+func unleet(lower string) (string, bool) {
+	changed := false
+	res := []rune(lower)
+	for i, c := range res {
+		if s, ok := leetSubs[c]; ok {
+			res[i] = s
+			changed = true
+		}
+	}
+	return string(res), changed
+}
+
+func reverse(s string) string {
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r)
+}
+
+func binomial(n, k int) float64 {
+	res := 1.0
+	for i := 1; i <= k; i++ {
+		res = res * float64(n-k+i) / float64(i)
+	}
+	return res
+}
@@ -0,0 +1,172 @@
+package password
+
+import (
+	"math"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		password string
+		want     int
+	}{
+		{"", 0},
+		{"password", 0},
+		{"123456", 0},
+		{"qwerty", 0},
+		{"aaaaaaaa", 0},
+		{"abcdefgh", 0},
+		{"1990", 0},
+		{"P@ssw0rd", 0},
+		{"drowssap", 0},
+		{"PASSWORD", 0},
+		{"Password", 0},
+		{"zxcvbnm", 0},
+		{"qazwsx", 0},
+		{"dragon12", 1},
+		{"jsmith2024", 3},
+		{"kjh28dbna9", 4},
+		{"Xk9#mQ2$vL7!pR4z", 4},
+		{"correcthorsebatterystaple", 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			if got := Score(tt.password); got != tt.want {
+				t.Errorf("Score(%q) = %d, want %d", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreUserInputs(t *testing.T) {
+	tests := []struct {
+		password   string
+		userInputs []string
+		want       int
+	}{
+		{"jsmith2024", nil, 3},
+		{"jsmith2024", []string{"jsmith"}, 1},
+		{"JSmith2024", []string{"jsmith"}, 1},
+		{"htimsj2024", []string{"jsmith"}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			if got := Score(tt.password, tt.userInputs...); got != tt.want {
+				t.Errorf("Score(%q, %v) = %d, want %d", tt.password, tt.userInputs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreLongPassword(t *testing.T) {
+	long := ""
+	for i := 0; i < 10; i++ {
+		long += "Xk9#mQ2$vL7!pR4z"
+	}
+	if got := Score(long); got != 4 {
+		t.Errorf("Score() = %d, want 4", got)
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	log := math.Log10
+	tests := []struct {
+		name   string
+		fn     func(string) (float64, bool)
+		token  string
+		want   float64
+		wantOK bool
+	}{
+		{"repeat", runes(repeatGuesses), "aaaa", log(26 * 4), true},
+		{"repeat digits", runes(repeatGuesses), "111", log(10 * 3), true},
+		{"repeat too short", runes(repeatGuesses), "aa", 0, false},
+		{"repeat mixed", runes(repeatGuesses), "aab", 0, false},
+		{"sequence from an obvious start", runes(sequenceGuesses), "abcd", log(4 * 4), true},
+		{"sequence descending", runes(sequenceGuesses), "dcba", log(26 * 2 * 4), true},
+		{"sequence digits", runes(sequenceGuesses), "3456", log(10 * 4), true},
+		{"sequence with gaps", runes(sequenceGuesses), "1357", 0, false},
+		{"spatial row", spatialGuesses, "qwer", log(10 * 4 * 4), true},
+		{"spatial reversed", spatialGuesses, "fdsa", log(9 * 4 * 4), true},
+		{"spatial too short", spatialGuesses, "qwe", 0, false},
+		{"year near the reference", runes(yearGuesses), "2019", log(minYearSpace), true},
+		{"year far from the reference", runes(yearGuesses), "1950", log(70), true},
+		{"year out of range", runes(yearGuesses), "1899", 0, false},
+		{"year not digits", runes(yearGuesses), "19a0", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.fn(tt.token)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("%q: got %f, %v, want %f, %v", tt.token, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func runes(fn func([]rune) (float64, bool)) func(string) (float64, bool) {
+	return func(s string) (float64, bool) {
+		return fn([]rune(s))
+	}
+}
+
+func TestDictionaryGuesses(t *testing.T) {
+	userRanks := map[string]int{"jsmith": 1, "alice": 2}
+	tests := []struct {
+		name   string
+		lower  string
+		want   float64
+		wantOK bool
+	}{
+		{"user input", "alice", math.Log10(2), true},
+		{"common word", "password", math.Log10(float64(commonRanks["password"])), true},
+		{"reversed", "drowssap", math.Log10(float64(commonRanks["password"]) * 2), true},
+		{"l33t", "p@ssw0rd", math.Log10(float64(commonRanks["password"]) * 2), true},
+		{"unknown", "kjh28dbna9", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := dictionaryGuesses(tt.lower, userRanks)
+			if ok != tt.wantOK || ok && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("dictionaryGuesses(%q) = %f, %v, want %f, %v", tt.lower, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestUppercaseVariations(t *testing.T) {
+	tests := []struct {
+		token string
+		want  float64
+	}{
+		{"password", 1},
+		{"Password", 2},
+		{"passworD", 2},
+		{"PASSWORD", 2},
+		{"pAssword", 8},
+		{"PAssword", binomial(8, 1) + binomial(8, 2)},
+		{"1234", 1},
+	}
+	for _, tt := range tests {
+		if got := uppercaseVariations([]rune(tt.token)); got != tt.want {
+			t.Errorf("uppercaseVariations(%q) = %f, want %f", tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestUnleet(t *testing.T) {
+	tests := []struct {
+		lower   string
+		want    string
+		changed bool
+	}{
+		{"p@ssw0rd", "password", true},
+		{"l3tm31n", "letmein", true},
+		{"password", "password", false},
+	}
+	for _, tt := range tests {
+		got, changed := unleet(tt.lower)
+		if got != tt.want || changed != tt.changed {
+			t.Errorf("unleet(%q) = %q, %v, want %q, %v", tt.lower, got, changed, tt.want, tt.changed)
+		}
+	}
+}
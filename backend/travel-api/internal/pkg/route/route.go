@@ -0,0 +1,172 @@
+// Package route computes near-optimal visiting orders of geographic points.
+// The path is open: it is not required to come back to the first point,
+// unless the start and the end are the same point.
+package route
+
+import "math"
+
+const earthRadius = 6371008.8 // meters
+
+// NoPoint marks an unconstrained start or end.
+const NoPoint = -1
+
+// maxPasses bounds 2-opt improvement passes over the whole path.
+const maxPasses = 100
+
+// maxStarts bounds how many starting points are tried for a free start.
+const maxStarts = 16
+
+type Point struct {
+	Lat float64
+	Lng float64
+}
+
+// Haversine returns the great-circle distance between a and b in meters.
+func Haversine(a, b Point) float64 {
+	lat1 := a.Lat * math.Pi / 180
+	lat2 := b.Lat * math.Pi / 180
+	dLat := lat2 - lat1
+	dLng := (b.Lng - a.Lng) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Optimize returns the indexes of points in a suggested visiting order and
+// the length of that path in meters. start and end are indexes of points that
+// must open and close the path, or NoPoint. When start equals end the path is
+// a round trip and the returned order lists that point once.
+// A nearest neighbour tour is built first and then improved with 2-opt.
+func Optimize(points []Point, start, end int) ([]int, float64) {
+	n := len(points)
+	if n == 0 {
+		return []int{}, 0
+	}
+	if n == 1 {
+		return []int{0}, 0
+	}
+
+	// a round trip is solved as a path ending at a copy of the start
+	roundTrip := start != NoPoint && start == end
+	if roundTrip {
+		points = append(append([]Point{}, points...), points[start])
+		end = n
+		n++
+	}
+
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			dist[i][j] = Haversine(points[i], points[j])
+		}
+	}
+
+	var best []int
+	bestLen := math.Inf(1)
+	starts := []int{start}
+	if start == NoPoint {
+		starts = make([]int, 0, maxStarts)
+		step := n/maxStarts + 1
+		for i := 0; i < n; i += step {
+			if i != end {
+				starts = append(starts, i)
+			}
+		}
+		if len(starts) == 0 {
+			starts = append(starts, (end+1)%n)
+		}
+	}
+	for _, s := range starts {
+		p := nearestNeighbour(dist, s, end)
+		twoOpt(dist, p, start != NoPoint, end != NoPoint)
+		if l := pathLength(dist, p); l < bestLen {
+			best, bestLen = p, l
+		}
+	}
+
+	if roundTrip {
+		best = best[:len(best)-1]
+	}
+	return best, bestLen
+}
+
+func nearestNeighbour(dist [][]float64, start, end int) []int {
+	n := len(dist)
+	visited := make([]bool, n)
+	path := make([]int, 0, n)
+	path = append(path, start)
+	visited[start] = true
+	if end != NoPoint {
+		visited[end] = true
+	}
+	cur := start
+	for len(path) < n {
+		next := NoPoint
+		for j := 0; j < n; j++ {
+			if !visited[j] && (next == NoPoint || dist[cur][j] < dist[cur][next]) {
+				next = j
+			}
+		}
+		if next == NoPoint {
+			break
+		}
+		path = append(path, next)
+		visited[next] = true
+		cur = next
+	}
+	if end != NoPoint && end != start {
+		path = append(path, end)
+	}
+	return path
+}
+
+// twoOpt reverses path segments while that shortens the path.
+// Fixed ends are never moved.
+func twoOpt(dist [][]float64, path []int, fixedStart, fixedEnd bool) {
+	n := len(path)
+	lo, hi := 0, n-1
+	if fixedStart {
+		lo = 1
+	}
+	if fixedEnd {
+		hi = n - 2
+	}
+	for pass := 0; pass < maxPasses; pass++ {
+		improved := false
+		for i := lo; i < hi; i++ {
+			for j := i + 1; j <= hi; j++ {
+				var before, after float64
+				if i > 0 {
+					before += dist[path[i-1]][path[i]]
+					after += dist[path[i-1]][path[j]]
+				}
+				if j < n-1 {
+					before += dist[path[j]][path[j+1]]
+					after += dist[path[i]][path[j+1]]
+				}
+				if after < before-1e-9 {
+					reverse(path[i : j+1])
+					improved = true
+				}
+			}
+		}
+		if !improved {
+			return
+		}
+	}
+}
+
+func pathLength(dist [][]float64, path []int) float64 {
+	var l float64
+	for i := 1; i < len(path); i++ {
+		l += dist[path[i-1]][path[i]]
+	}
+	return l
+}
+
+func reverse(p []int) {
+	for i, j := 0, len(p)-1; i < j; i, j = i+1, j-1 {
+		p[i], p[j] = p[j], p[i]
+	}
+}
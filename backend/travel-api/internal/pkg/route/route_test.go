@@ -0,0 +1,211 @@
+package route
+
+import (
+	"math"
+	"testing"
+)
+
+// line returns points on the equator at the given longitudes.
+func line(lngs ...float64) []Point {
+	ps := make([]Point, 0, len(lngs))
+	for _, lng := range lngs {
+		ps = append(ps, Point{Lat: 0, Lng: lng})
+	}
+	return ps
+}
+
+func equalOrder(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func reversed(p []int) []int {
+	r := append([]int{}, p...)
+	reverse(r)
+	return r
+}
+
+func TestHaversine(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{"same point", Point{10, 20}, Point{10, 20}, 0},
+		{"one degree of latitude", Point{0, 0}, Point{1, 0}, earthRadius * math.Pi / 180},
+		{"one degree of longitude on the equator", Point{0, 0}, Point{0, 1}, earthRadius * math.Pi / 180},
+		{"antipodes", Point{0, 0}, Point{0, 180}, earthRadius * math.Pi},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Haversine(tt.a, tt.b); math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("Haversine() = %f, want %f", got, tt.want)
+			}
+			if got, back := Haversine(tt.a, tt.b), Haversine(tt.b, tt.a); math.Abs(got-back) > 1e-6 {
+				t.Errorf("Haversine() is not symmetric: %f, %f", got, back)
+			}
+		})
+	}
+}
+
+func TestOptimize(t *testing.T) {
+	// indexes sorted by longitude: 1, 3, 5, 0, 4, 2
+	shuffled := line(0.03, 0, 0.05, 0.01, 0.04, 0.02)
+	span := Haversine(Point{0, 0}, Point{0, 0.05})
+	tests := []struct {
+		name       string
+		points     []Point
+		start, end int
+		// any of the orders is accepted
+		want    [][]int
+		wantLen float64
+	}{
+		{"no points", nil, NoPoint, NoPoint, [][]int{{}}, 0},
+		{"one point", line(0), NoPoint, NoPoint, [][]int{{0}}, 0},
+		{
+			"free ends",
+			shuffled, NoPoint, NoPoint,
+			[][]int{{1, 3, 5, 0, 4, 2}, reversed([]int{1, 3, 5, 0, 4, 2})},
+			span,
+		},
+		{"fixed start", shuffled, 1, NoPoint, [][]int{{1, 3, 5, 0, 4, 2}}, span},
+		{"fixed end", shuffled, NoPoint, 1, [][]int{{2, 4, 0, 5, 3, 1}}, span},
+		{"fixed start and end", shuffled, 2, 1, [][]int{{2, 4, 0, 5, 3, 1}}, span},
+		{
+			// the start in the middle makes one side walked twice
+			"fixed start in the middle",
+			shuffled, 5, NoPoint,
+			[][]int{{5, 3, 1, 0, 4, 2}, {5, 0, 4, 2, 3, 1}},
+			0,
+		},
+		{
+			"round trip",
+			shuffled, 1, 1,
+			[][]int{{1, 3, 5, 0, 4, 2}, {1, 2, 4, 0, 5, 3}},
+			2 * span,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotLen := Optimize(tt.points, tt.start, tt.end)
+			ok := false
+			for _, w := range tt.want {
+				if equalOrder(got, w) {
+					ok = true
+				}
+			}
+			if !ok {
+				t.Errorf("Optimize() order = %v, want one of %v", got, tt.want)
+			}
+			if tt.wantLen != 0 && math.Abs(gotLen-tt.wantLen) > 1e-6 {
+				t.Errorf("Optimize() length = %f, want %f", gotLen, tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestOptimizeVisitsAllOnce(t *testing.T) {
+	ps := make([]Point, 0, 40)
+	for i := 0; i < 40; i++ {
+		// a spiral, so that neither order nor distances are trivial
+		a := float64(i) * 0.7
+		r := 0.001 * float64(i+1)
+		ps = append(ps, Point{Lat: 50 + r*math.Sin(a), Lng: 14 + r*math.Cos(a)})
+	}
+	tests := []struct {
+		name       string
+		start, end int
+		wantN      int
+	}{
+		{"free ends", NoPoint, NoPoint, 40},
+		{"fixed start", 7, NoPoint, 40},
+		{"fixed end", NoPoint, 7, 40},
+		{"fixed start and end", 3, 30, 40},
+		{"round trip", 12, 12, 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _ := Optimize(ps, tt.start, tt.end)
+			if len(got) != tt.wantN {
+				t.Fatalf("got %d points, want %d", len(got), tt.wantN)
+			}
+			seen := make(map[int]bool)
+			for _, i := range got {
+				if seen[i] {
+					t.Fatalf("point %d visited twice: %v", i, got)
+				}
+				seen[i] = true
+			}
+			if tt.start != NoPoint && got[0] != tt.start {
+				t.Errorf("path starts at %d, want %d", got[0], tt.start)
+			}
+			if tt.end != NoPoint && tt.end != tt.start && got[len(got)-1] != tt.end {
+				t.Errorf("path ends at %d, want %d", got[len(got)-1], tt.end)
+			}
+		})
+	}
+}
+
+func TestNearestNeighbour(t *testing.T) {
+	ps := line(0, 0.01, 0.03, 0.02)
+	dist := distances(ps)
+	tests := []struct {
+		name       string
+		start, end int
+		want       []int
+	}{
+		{"open", 0, NoPoint, []int{0, 1, 3, 2}},
+		{"fixed end is kept last", 0, 1, []int{0, 3, 2, 1}},
+		{"start from the far end", 2, NoPoint, []int{2, 3, 1, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nearestNeighbour(dist, tt.start, tt.end); !equalOrder(got, tt.want) {
+				t.Errorf("nearestNeighbour() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTwoOpt(t *testing.T) {
+	ps := line(0, 0.01, 0.02, 0.03)
+	dist := distances(ps)
+	tests := []struct {
+		name                 string
+		path                 []int
+		fixedStart, fixedEnd bool
+		want                 []int
+	}{
+		{"uncrosses the path", []int{0, 2, 1, 3}, true, true, []int{0, 1, 2, 3}},
+		{"keeps a shortest path", []int{0, 1, 2, 3}, true, true, []int{0, 1, 2, 3}},
+		{"moves a free end", []int{1, 0, 2, 3}, false, true, []int{0, 1, 2, 3}},
+		{"keeps the fixed ends", []int{1, 0, 3, 2}, true, true, []int{1, 0, 3, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := append([]int{}, tt.path...)
+			twoOpt(dist, p, tt.fixedStart, tt.fixedEnd)
+			if !equalOrder(p, tt.want) {
+				t.Errorf("twoOpt(%v) = %v, want %v", tt.path, p, tt.want)
+			}
+		})
+	}
+}
+
+func distances(ps []Point) [][]float64 {
+	dist := make([][]float64, len(ps))
+	for i := range dist {
+		dist[i] = make([]float64, len(ps))
+		for j := range dist[i] {
+			dist[i][j] = Haversine(ps[i], ps[j])
+		}
+	}
+	return dist
+}
@@ -18,6 +18,7 @@ type Values struct {
 	Tracer     trace.Tracer
 	Now        time.Time
 	StatusCode int
+	ClientIP   string
 }
 
 func GetValues(ctx context.Context) (*Values, error) {
@@ -42,6 +43,16 @@ func GetTraceID(ctx context.Context) string {
 	return v.TraceID
 }
 
+// GetClientIP returns the address of the client the request came from.
+func GetClientIP(ctx context.Context) string {
+	v, ok := ctx.Value(key).(*Values)
+
+	if !ok {
+		return ""
+	}
+	return v.ClientIP
+}
+
 func AddSpan(ctx context.Context, spanName string, keyValues ...attribute.KeyValue) (context.Context, trace.Span) {
 	v, ok := ctx.Value(key).(*Values)
 	if !ok || v.Tracer == nil {
@@ -6,14 +6,16 @@ import (
 	"errors"
 	"net/http"
 	"syscall"
+	"time"
 )
 
 var (
-	ErrNotFound      = errors.New("error not found")
-	ErrForbidden     = errors.New("error not allowed")
-	ErrAuthFailed    = errors.New("error authentication failed")
-	ErrAlreadyExists = errors.New("error already exists")
-	ErrCritical      = errors.New("error data integrity")
+	ErrNotFound           = errors.New("error not found")
+	ErrForbidden          = errors.New("error not allowed")
+	ErrAuthFailed         = errors.New("error authentication failed")
+	ErrAlreadyExists      = errors.New("error already exists")
+	ErrCritical           = errors.New("error data integrity")
+	ErrPreconditionFailed = errors.New("error precondition failed")
 )
 
 type ResponseError struct {
@@ -24,6 +26,8 @@ type ResponseError struct {
 type RequestError struct {
 	Err    error
 	Status int
+	// RetryAfter is sent as the Retry-After header when set
+	RetryAfter time.Duration
 }
 
 type FieldError struct {
@@ -87,7 +91,16 @@ func (re *RequestError) Error() string {
 }
 
 func NewRequestError(err error, status int) error {
-	return &RequestError{err, status}
+	return &RequestError{Err: err, Status: status}
+}
+
+// NewTooManyRequestsError creates a 429 error telling the client when to retry.
+func NewTooManyRequestsError(err error, retryAfter time.Duration) error {
+	return &RequestError{
+		Err:        err,
+		Status:     http.StatusTooManyRequests,
+		RetryAfter: retryAfter,
+	}
 }
 
 func IsRequestError(err error) bool {
@@ -155,6 +168,16 @@ func GetResponseErrorFromBusiness(err error) error {
 			err,
 			http.StatusUnauthorized,
 		)
+	case errors.Is(err, ErrPreconditionFailed):
+		return NewRequestError(
+			err,
+			http.StatusPreconditionFailed,
+		)
+	case IsFieldErrors(err):
+		return NewRequestError(
+			err,
+			http.StatusBadRequest,
+		)
 	default:
 		return err
 	}
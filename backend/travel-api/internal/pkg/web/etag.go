@@ -0,0 +1,85 @@
+package web
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidIfMatch = errors.New("invalid If-Match header, a list of entity tags or * is expected")
+
+// Tagger is implemented by response data that carries an entity version,
+// Respond sends it in the ETag header.
+type Tagger interface {
+	ETag() string
+}
+
+// ETag formats an entity version as a strong entity tag.
+func ETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// Precondition is the set of entity versions an If-Match header matches.
+// The zero value matches any version.
+type Precondition struct {
+	versions []int
+	isSet    bool
+}
+
+// Match reports whether the entity version meets the precondition.
+func (p Precondition) Match(version int) bool {
+	if !p.isSet {
+		return true
+	}
+	for _, v := range p.versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// IfMatch parses the If-Match header. Tags are compared strongly, so weak
+// tags and tags that are not an entity version never match. Only a header
+// that is not a list of entity tags is invalid.
+func IfMatch(r *http.Request) (Precondition, error) {
+	h := strings.TrimSpace(r.Header.Get("If-Match"))
+	if h == "" || h == "*" {
+		return Precondition{}, nil
+	}
+	p := Precondition{isSet: true}
+	for _, tag := range strings.Split(h, ",") {
+		tag = strings.TrimSpace(tag)
+		weak := strings.HasPrefix(tag, "W/")
+		opaque := strings.TrimPrefix(tag, "W/")
+		if len(opaque) < 2 || opaque[0] != '"' || opaque[len(opaque)-1] != '"' ||
+			strings.Contains(opaque[1:len(opaque)-1], `"`) {
+			return Precondition{}, ErrInvalidIfMatch
+		}
+		if weak {
+			continue
+		}
+		if v, err := strconv.Atoi(opaque[1 : len(opaque)-1]); err == nil {
+			p.versions = append(p.versions, v)
+		}
+	}
+	return p, nil
+}
+
+// NoneMatch reports whether the If-None-Match header matches the entity tag.
+// Tags are compared weakly, as required for GET and HEAD.
+func NoneMatch(r *http.Request, etag string) bool {
+	h := r.Header.Get("If-None-Match")
+	if h == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(h, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
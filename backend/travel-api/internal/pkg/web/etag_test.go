@@ -0,0 +1,52 @@
+package web
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		match   []int
+		noMatch []int
+		wantErr error
+	}{
+		{"absent", "", []int{1, 7}, nil, nil},
+		{"any", "*", []int{1, 7}, nil, nil},
+		{"single tag", `"3"`, []int{3}, []int{2, 4}, nil},
+		{"list of tags", `"3", "5"`, []int{3, 5}, []int{4}, nil},
+		{"weak tag", `W/"3"`, nil, []int{3}, nil},
+		{"weak and strong tags", `W/"3", "4"`, []int{4}, []int{3}, nil},
+		{"not a version", `"abc"`, nil, []int{1}, nil},
+		{"empty tag", `""`, nil, []int{0, 1}, nil},
+		{"unquoted", `3`, nil, nil, ErrInvalidIfMatch},
+		{"unterminated", `"3`, nil, nil, ErrInvalidIfMatch},
+		{"quote inside", `"3"4"`, nil, nil, ErrInvalidIfMatch},
+		{"empty list member", `"3",`, nil, nil, ErrInvalidIfMatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("PUT", "/", nil)
+			if tt.header != "" {
+				r.Header.Set("If-Match", tt.header)
+			}
+			p, err := IfMatch(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IfMatch(%q) error = %v, want %v", tt.header, err, tt.wantErr)
+			}
+			for _, v := range tt.match {
+				if !p.Match(v) {
+					t.Errorf("IfMatch(%q) does not match version %d", tt.header, v)
+				}
+			}
+			for _, v := range tt.noMatch {
+				if p.Match(v) {
+					t.Errorf("IfMatch(%q) matches version %d", tt.header, v)
+				}
+			}
+		})
+	}
+}
@@ -2,8 +2,15 @@ package web
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"net/netip"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dimfeld/httptreemux/v5"
 )
@@ -34,3 +41,157 @@ func Decode(r *http.Request, val any) error {
 
 	return nil
 }
+
+// DecodeQuery fills the struct pointed by val from the url query.
+// Query keys are matched against the form tags of the struct fields,
+// falling back to the json tags. A form key without omitempty is required.
+// Only scalar fields and pointers to them are supported.
+func DecodeQuery(r *http.Request, val any) error {
+	rv := reflect.ValueOf(val)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("decoding error: value must be a pointer to struct")
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+	q := r.URL.Query()
+
+	for i := 0; i < rt.NumField(); i++ {
+		tag, isForm := rt.Field(i).Tag.Lookup("form")
+		if !isForm {
+			tag = rt.Field(i).Tag.Get("json")
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		raw, ok := q[name]
+		if !ok || len(raw) == 0 {
+			if isForm && !strings.Contains(opts, "omitempty") {
+				return fmt.Errorf("validation error: %w", NewFieldsError(name, errors.New("is required")))
+			}
+			continue
+		}
+		if err := setQueryField(rv.Field(i), raw[len(raw)-1]); err != nil {
+			return fmt.Errorf("decoding error: %s: %w", name, err)
+		}
+	}
+
+	if v, ok := val.(validator); ok {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("validation error: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func setQueryField(v reflect.Value, raw string) error {
+	if v.Kind() == reflect.Pointer {
+		p := reflect.New(v.Type().Elem())
+		if err := setQueryField(p.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	if v.Type() == reflect.TypeOf(time.Time{}) {
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// ParseTrustedProxies parses the addresses of the proxies in front of the
+// api, each one is an IP or a CIDR range.
+func ParseTrustedProxies(proxies []string) ([]netip.Prefix, error) {
+	res := make([]netip.Prefix, 0, len(proxies))
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		if !strings.Contains(p, "/") {
+			ip, err := netip.ParseAddr(p)
+			if err != nil {
+				return nil, fmt.Errorf("trusted proxy %q: %w", p, err)
+			}
+			res = append(res, netip.PrefixFrom(ip, ip.BitLen()))
+			continue
+		}
+		pr, err := netip.ParsePrefix(p)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %w", p, err)
+		}
+		res = append(res, pr.Masked())
+	}
+	return res, nil
+}
+
+// ClientIP returns the address of the client. X-Forwarded-For is the
+// client's to write, so it is only read when the peer is a trusted proxy,
+// walking it from the right the first address not of a trusted proxy is
+// the client. Otherwise the peer is the client.
+func ClientIP(r *http.Request, trusted []netip.Prefix) string {
+	peer := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		peer = host
+	}
+	if !isTrustedProxy(peer, trusted) {
+		return peer
+	}
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	client := peer
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if hop == "" {
+			continue
+		}
+		client = hop
+		if !isTrustedProxy(hop, trusted) {
+			break
+		}
+	}
+	return client
+}
+
+func isTrustedProxy(addr string, trusted []netip.Prefix) bool {
+	ip, err := netip.ParseAddr(addr)
+	if err != nil {
+		return false
+	}
+	ip = ip.Unmap()
+	for _, p := range trusted {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"go.opentelemetry.io/otel/attribute"
 )
@@ -15,7 +17,11 @@ func Respond(ctx context.Context, w http.ResponseWriter, data any, statusCode in
 	// TODO: think what to do with this
 	_ = SetStatusCode(ctx, statusCode)
 
-	if statusCode == http.StatusNoContent {
+	if t, ok := data.(Tagger); ok {
+		w.Header().Set("ETag", t.ETag())
+	}
+
+	if statusCode == http.StatusNoContent || statusCode == http.StatusNotModified {
 		w.WriteHeader(statusCode)
 		return nil
 	}
@@ -63,6 +69,11 @@ func RespondError(ctx context.Context, w http.ResponseWriter, err error) error {
 	switch {
 	case IsRequestError(err):
 		reqErr := GetRequestError(err)
+		if reqErr.RetryAfter > 0 {
+			// whole seconds, rounded up so the client does not retry too early
+			secs := (reqErr.RetryAfter + time.Second - 1) / time.Second
+			w.Header().Set("Retry-After", strconv.FormatInt(int64(secs), 10))
+		}
 		if IsFieldErrors(reqErr.Err) {
 			fieldErrors := GetFieldErrors(reqErr.Err)
 
@@ -0,0 +1,59 @@
+package web
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// EventStream writes server-sent events to the client.
+type EventStream struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// NewEventStream starts a text/event-stream response. The server read and
+// write deadlines are lifted so the stream lasts until either side ends it.
+func NewEventStream(ctx context.Context, w http.ResponseWriter) (*EventStream, error) {
+	rc := http.NewResponseController(w)
+	for _, set := range []func(time.Time) error{rc.SetReadDeadline, rc.SetWriteDeadline} {
+		if err := set(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return nil, err
+		}
+	}
+	_ = SetStatusCode(ctx, http.StatusOK)
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	// disables response buffering of nginx
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	es := EventStream{w: w, rc: rc}
+	return &es, rc.Flush()
+}
+
+// Send writes an event named event with data encoded as JSON.
+func (es *EventStream) Send(id string, event string, data any) error {
+	jsonData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(es.w, "id: %s\nevent: %s\ndata: %s\n\n", id, event, jsonData); err != nil {
+		return err
+	}
+	return es.rc.Flush()
+}
+
+// Ping writes a comment, it keeps idle connections from being closed
+// by proxies and detects clients that went away.
+func (es *EventStream) Ping() error {
+	if _, err := fmt.Fprint(es.w, ": ping\n\n"); err != nil {
+		return err
+	}
+	return es.rc.Flush()
+}
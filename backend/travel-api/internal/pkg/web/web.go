@@ -3,6 +3,7 @@ package web
 import (
 	"context"
 	"net/http"
+	"net/netip"
 	"os"
 	"syscall"
 	"time"
@@ -22,6 +23,8 @@ type App struct {
 	shutdown   chan os.Signal
 	timeout    time.Duration
 	middleware []Middleware
+	// proxies are the proxies trusted to set X-Forwarded-For
+	proxies []netip.Prefix
 }
 type Handler func(ctx context.Context, w http.ResponseWriter, r *http.Request) error
 
@@ -40,23 +43,42 @@ func New(shutdown chan os.Signal, timeout time.Duration, tracer trace.Tracer, mw
 	return &app
 }
 
+// TrustProxies sets the proxies whose X-Forwarded-For is trusted.
+func (a *App) TrustProxies(proxies []netip.Prefix) {
+	a.proxies = proxies
+}
+
 func (a *App) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	a.otmux.ServeHTTP(w, r)
 }
 
 func (a *App) Handle(method string, path string, handler Handler, mw ...Middleware) {
+	a.handle(method, path, a.timeout, handler, mw...)
+}
+
+// HandleStream registers a handler of a long-lived response,
+// it is not bound by the request timeout.
+func (a *App) HandleStream(method string, path string, handler Handler, mw ...Middleware) {
+	a.handle(method, path, 0, handler, mw...)
+}
+
+func (a *App) handle(method string, path string, timeout time.Duration, handler Handler, mw ...Middleware) {
 
 	h := func(w http.ResponseWriter, r *http.Request) {
 		ctx, span := a.startSpan(w, r)
 		defer span.End()
 
-		ctx, cancel := context.WithTimeout(ctx, a.timeout)
-		defer cancel()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
 
 		v := Values{
-			TraceID: span.SpanContext().TraceID().String(),
-			Tracer:  a.tracer,
-			Now:     time.Now().UTC(),
+			TraceID:  span.SpanContext().TraceID().String(),
+			Tracer:   a.tracer,
+			Now:      time.Now().UTC(),
+			ClientIP: ClientIP(r, a.proxies),
 		}
 		ctx = SetValues(ctx, &v)
 